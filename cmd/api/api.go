@@ -9,39 +9,82 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
 	"log/slog"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"sync"
+	"syscall"
 
 	"github.com/casbin/casbin/v2"
 	"github.com/zohirovs/internal/config"
 	"github.com/zohirovs/internal/http/app"
 	"github.com/zohirovs/internal/http/handler"
+	"github.com/zohirovs/internal/logging"
+	"github.com/zohirovs/internal/metrics"
 	"github.com/zohirovs/internal/service"
 	"github.com/zohirovs/internal/storage"
+	"github.com/zohirovs/internal/storage/blob"
 	mongo "github.com/zohirovs/internal/storage/mongoDB"
 	"github.com/zohirovs/internal/storage/redis"
+	"github.com/zohirovs/internal/tracing"
+	websocket "github.com/zohirovs/internal/ws"
 )
 
 func Run() error {
 	// Load configuration and handle errors
 	cfg, err := config.New()
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
 	if err != nil {
 		log.Fatal(err)
 		return err // Add return statement after log.Fatal for better error handling
 	}
+	if cfg.PrintOnly {
+		fmt.Print(cfg)
+		return nil
+	}
 
-	// Set up logger with file output
-	logFile, err := os.OpenFile("application.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	// Initialize structured logger; records logged with a request's context
+	// carry its request ID, user, route and trace
+	logger, logOutput := logging.New(cfg.Log)
+	defer logOutput.Close()
+	slog.SetDefault(logger)
+
+	// Initialize tracing before anything that starts spans
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing)
 	if err != nil {
-		log.Fatal(err)
+		logger.Error("Error while initializing tracing", slog.String("err", err.Error()))
 		return err
 	}
-	defer logFile.Close()
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Error("Error while flushing traces", slog.String("err", err.Error()))
+		}
+	}()
 
-	// Initialize structured logger with JSON format
-	logger := slog.New(slog.NewJSONHandler(logFile, nil))
+	// Cancelled on SIGINT or SIGTERM to shut down gracefully
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Background jobs run until the server has drained, then are stopped
+	// and waited for before their connections are closed
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	var jobs sync.WaitGroup
+	runJob := func(job func(context.Context)) {
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			job(jobsCtx)
+		}()
+	}
 
 	// Initialize MongoDB connection
 	db, err := mongo.ConnectDB(cfg)
@@ -50,23 +93,48 @@ func Run() error {
 		return err
 	}
 
+	// Initialize Redis client and service. An unreachable Redis only
+	// disables caching; the breaker reconnects in the background.
 	redisClient, err := redis.NewRedisClient(cfg)
 	if err != nil {
-		logger.Error("Error while connecting to Redis", slog.String("err", err.Error()))
+		logger.Error("Error while configuring Redis", slog.String("err", err.Error()))
 		return err
 	}
-
-	// Initialize Redis client and service
 	redisService := redis.New(redisClient, logger)
+	if err := redisService.Ping(context.Background()); err != nil {
+		logger.Warn("Redis is unavailable, starting without cache", slog.String("err", err.Error()))
+	}
+	runJob(redisService.Watch)
 
 	// Initialize storage layer with MongoDB and Redis
 	storage := storage.New(db, cfg, logger, redisService)
 
+	// Initialize attachment file storage
+	files, err := blob.New(cfg)
+	if err != nil {
+		logger.Error("Error while initializing attachment storage", slog.String("err", err.Error()))
+		return err
+	}
+
+	// Tender rooms for live updates; services publish to it, the handler
+	// registers clients.
+	wsManager := websocket.NewManager()
+	go wsManager.Run()
+	metrics.RegisterWebSocket(wsManager)
+
 	// Initialize service layer
-	service := service.NewService(redisService, logger, storage)
+	service := service.NewService(redisService, logger, storage, files, wsManager, runJob, cfg)
+
+	// Send saved-search email digests in the background
+	runJob(func(ctx context.Context) {
+		service.Notification.RunDigests(ctx, cfg.Email.DigestInterval)
+	})
+
+	// Purge soft-deleted records past their retention period
+	runJob(service.Retention.Run)
 
 	// Initialize HTTP handler
-	handler := handler.NewHandler(logger, service, cfg)
+	handler := handler.NewHandler(logger, service, cfg, wsManager)
 
 	// Set up Casbin enforcer for authorization
 	modelPath := filepath.Join("internal", "casbin", "model.conf")
@@ -78,8 +146,49 @@ func Run() error {
 		return err
 	}
 
-	log.Println("SERVER HAST STARTED")
-
 	// Start the HTTP server
-	return app.Run(handler, logger, cfg, enforcer)
+	server, err := app.NewServer(handler, logger, cfg, enforcer, redisService.RateLimit, service.Quota, service.User)
+	if err != nil {
+		logger.Error("Error building server", slog.String("err", err.Error()))
+		return err
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+	logger.Info("Server started", slog.String("addr", server.Addr))
+
+	var runErr error
+	select {
+	case runErr = <-serveErr:
+		logger.Error("Server failed", slog.String("err", runErr.Error()))
+	case <-ctx.Done():
+		// A second signal kills the process instead of waiting
+		stop()
+		logger.Info("Shutting down")
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	// Stop accepting connections and let in-flight requests finish
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Error("Error while draining requests", slog.String("err", err.Error()))
+	}
+
+	// WebSockets are hijacked, so Shutdown does not wait for them
+	wsManager.Shutdown()
+
+	stopJobs()
+	jobs.Wait()
+
+	if err := storage.Close(shutdownCtx); err != nil {
+		logger.Error("Error while disconnecting from MongoDB", slog.String("err", err.Error()))
+	}
+	if err := redisService.Close(); err != nil {
+		logger.Error("Error while closing Redis", slog.String("err", err.Error()))
+	}
+
+	logger.Info("Server stopped")
+	return runErr
 }
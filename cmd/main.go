@@ -2,10 +2,13 @@ package main
 
 import (
 	"log"
+	_ "time/tzdata"
 
 	"github.com/zohirovs/cmd/api"
 )
 
 func main() {
-	log.Fatal(api.Run())
+	if err := api.Run(); err != nil {
+		log.Fatal(err)
+	}
 }
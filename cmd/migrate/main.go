@@ -0,0 +1,92 @@
+// Command migrate applies, reverts and reports versioned MongoDB schema
+// migrations.
+//
+// Usage:
+//
+//	migrate up [N]     apply all (or the next N) pending migrations
+//	migrate down [N]   revert the last (or last N) applied migrations
+//	migrate status     list migrations and when they were applied
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	_ "time/tzdata"
+
+	"github.com/zohirovs/internal/config"
+	mongo "github.com/zohirovs/internal/storage/mongoDB"
+	"github.com/zohirovs/internal/storage/mongoDB/migrations"
+)
+
+const usage = "usage: migrate up [N] | down [N] | status"
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
+	}
+
+	steps := 0
+	if len(os.Args) > 2 {
+		n, err := strconv.Atoi(os.Args[2])
+		if err != nil || n < 0 {
+			log.Fatalf("invalid step count %q\n%s", os.Args[2], usage)
+		}
+		steps = n
+	}
+
+	cfg, err := config.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	db, err := mongo.ConnectDB(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Client().Disconnect(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	migrator := migrations.NewMigrator(db, logger)
+
+	switch os.Args[1] {
+	case "up":
+		applied, err := migrator.Up(ctx, steps)
+		if err != nil {
+			log.Fatalf("applied %v before failing: %v", applied, err)
+		}
+		log.Printf("applied migrations: %v", applied)
+
+	case "down":
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			log.Fatalf("reverted %v before failing: %v", reverted, err)
+		}
+		log.Printf("reverted migrations: %v", reverted)
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d  %-25s  %s\n", status.Version, appliedAt, status.Description)
+		}
+
+	default:
+		log.Fatal(usage)
+	}
+}
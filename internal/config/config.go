@@ -1,74 +1,355 @@
 package config
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
 	"os"
 	"strconv"
-
-	"github.com/joho/godotenv"
+	"strings"
+	"time"
 )
 
 type (
+	// Config is read by Load from, in increasing precedence: the defaults
+	// below, an optional YAML file, environment variables (and a .env file)
+	// and command-line flags. Each field's yaml tag names its key in the
+	// file and, joined with dots, its flag; its env tag names its variable,
+	// prefixed by the env tags of the structs it is nested in. Fields
+	// tagged secret are masked when the config is printed.
 	Config struct {
-		Server   ServerConfig
-		MongoDb  MongoDbConfig
-		JWT      JWTConfig
-		Email    EmailConfig
-		RedisURI string
+		Server      ServerConfig     `yaml:"server"`
+		MongoDb     MongoDbConfig    `yaml:"mongodb"`
+		Redis       RedisConfig      `yaml:"redis"`
+		JWT         JWTConfig        `yaml:"jwt"`
+		Email       EmailConfig      `yaml:"email"`
+		Attachments AttachmentConfig `yaml:"attachments"`
+		Search      SearchConfig     `yaml:"search"`
+		Questions   QuestionConfig   `yaml:"questions"`
+		Retention   RetentionConfig  `yaml:"retention"`
+		RateLimit   RateLimitConfig  `yaml:"rate_limit"`
+		Quotas      QuotaConfig      `yaml:"quotas"`
+		Tracing     TracingConfig    `yaml:"tracing"`
+		Log         LogConfig        `yaml:"log"`
+
+		// File is the YAML file the config was read from, if any.
+		File string `yaml:"-"`
+		// PrintOnly is set by -print-config: the caller should print the
+		// config and exit.
+		PrintOnly bool `yaml:"-"`
 	}
 	JWTConfig struct {
-		SecretKey string
+		SecretKey string `yaml:"secret_key" env:"JWT_SECRET_KEY" secret:"true"`
 	}
 
 	ServerConfig struct {
-		Port string
+		Port         string        `yaml:"port" env:"SERVER_PORT"`
+		ReadTimeout  time.Duration `yaml:"read_timeout" env:"SERVER_READ_TIMEOUT"`
+		WriteTimeout time.Duration `yaml:"write_timeout" env:"SERVER_WRITE_TIMEOUT"`
+		IdleTimeout  time.Duration `yaml:"idle_timeout" env:"SERVER_IDLE_TIMEOUT"`
+		// ShutdownTimeout bounds how long draining in-flight requests and
+		// closing connections may take once the server is asked to stop.
+		ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SERVER_SHUTDOWN_TIMEOUT"`
+		// TrustedProxies lists the proxy IPs or CIDRs whose forwarding
+		// headers decide the client IP. When empty only the connection's
+		// remote address is used.
+		TrustedProxies []string `yaml:"trusted_proxies" env:"SERVER_TRUSTED_PROXIES"`
 	}
+
+	// MongoDbConfig connects to URI when it is set, and otherwise to
+	// Host:Port. User, when set, overrides any credentials in URI.
 	MongoDbConfig struct {
-		Host     string
-		Port     string
-		User     string
-		Password string
-		DBName   string
+		URI           string    `yaml:"uri" env:"MONGODB_URI" secret:"true"`
+		Host          string    `yaml:"host" env:"DB_HOST"`
+		Port          string    `yaml:"port" env:"DB_PORT"`
+		User          string    `yaml:"user" env:"DB_USER"`
+		Password      string    `yaml:"password" env:"DB_PASSWORD" secret:"true"`
+		AuthSource    string    `yaml:"auth_source" env:"DB_AUTH_SOURCE"`
+		AuthMechanism string    `yaml:"auth_mechanism" env:"DB_AUTH_MECHANISM"`
+		DBName        string    `yaml:"database" env:"DB_NAME"`
+		TLS           TLSConfig `yaml:"tls" env:"DB_"`
+	}
+
+	// RedisConfig connects to URI, either host:port or a redis:// or
+	// rediss:// URL. Password, Username and DB, when set, override the URL.
+	RedisConfig struct {
+		URI      string    `yaml:"uri" env:"REDIS_URI" secret:"true"`
+		Username string    `yaml:"username" env:"REDIS_USERNAME"`
+		Password string    `yaml:"password" env:"REDIS_PASSWORD" secret:"true"`
+		DB       int       `yaml:"db" env:"REDIS_DB"`
+		TLS      TLSConfig `yaml:"tls" env:"REDIS_"`
+	}
+
+	TLSConfig struct {
+		Enabled bool `yaml:"enabled" env:"TLS"`
+		// CAFile verifies the server against these CAs instead of the
+		// system pool.
+		CAFile string `yaml:"ca_file" env:"TLS_CA_FILE"`
+		// CertFile and KeyFile hold a client certificate, e.g. for MongoDB
+		// X.509 authentication.
+		CertFile           string `yaml:"cert_file" env:"TLS_CERT_FILE"`
+		KeyFile            string `yaml:"key_file" env:"TLS_KEY_FILE"`
+		InsecureSkipVerify bool   `yaml:"insecure_skip_verify" env:"TLS_INSECURE_SKIP_VERIFY"`
 	}
 
 	EmailConfig struct {
-		SmtpHost string
-		SmtpPort int
-		SmtpUser string
-		SmtpPass string
+		SmtpHost string `yaml:"smtp_host" env:"SMTP_HOST"`
+		SmtpPort int    `yaml:"smtp_port" env:"SMTP_PORT"`
+		SmtpUser string `yaml:"smtp_user" env:"SMTP_USER"`
+		SmtpPass string `yaml:"smtp_pass" env:"SMTP_PASS" secret:"true"`
+
+		// DigestInterval is how often saved-search email digests are sent.
+		DigestInterval time.Duration `yaml:"digest_interval" env:"DIGEST_INTERVAL"`
+	}
+
+	AttachmentConfig struct {
+		Driver     string        `yaml:"storage" env:"ATTACHMENT_STORAGE"` // "local" or "s3"
+		LocalDir   string        `yaml:"dir" env:"ATTACHMENT_DIR"`
+		MaxSizeMB  int64         `yaml:"max_size_mb" env:"ATTACHMENT_MAX_SIZE_MB"`
+		MaxSize    int64         `yaml:"-"` // bytes, from MaxSizeMB
+		URLTTL     time.Duration `yaml:"url_ttl" env:"ATTACHMENT_URL_TTL"`
+		SigningKey string        `yaml:"signing_key" env:"ATTACHMENT_SIGNING_KEY" secret:"true"` // separate from the JWT secret
+		S3         S3Config      `yaml:"s3"`
+	}
+
+	SearchConfig struct {
+		// Language is the default stemming language for tender search.
+		Language string `yaml:"language" env:"SEARCH_LANGUAGE"`
+	}
+
+	QuestionConfig struct {
+		// Cutoff is how long before a tender's deadline questions close.
+		Cutoff time.Duration `yaml:"cutoff" env:"QUESTION_CUTOFF"`
+	}
+
+	RetentionConfig struct {
+		// Period is how long soft-deleted tenders, bids and users can be
+		// restored before they are purged.
+		Period time.Duration `yaml:"period" env:"SOFT_DELETE_RETENTION"`
+		// Interval is how often the purge runs.
+		Interval time.Duration `yaml:"interval" env:"RETENTION_INTERVAL"`
+	}
+
+	// RateLimit admits Limit requests per sliding Window; a zero Limit
+	// disables it. It is written as "<requests>/<window>", e.g. "10/1m".
+	RateLimit struct {
+		Limit  int
+		Window time.Duration
+	}
+
+	RateLimitConfig struct {
+		LoginIP       RateLimit `yaml:"login_ip" env:"RATE_LIMIT_LOGIN_IP"`
+		LoginUsername RateLimit `yaml:"login_username" env:"RATE_LIMIT_LOGIN_USERNAME"`
+		RegisterIP    RateLimit `yaml:"register_ip" env:"RATE_LIMIT_REGISTER_IP"`
+
+		// LockoutThreshold failed logins lock an account for LockoutBase;
+		// every further failure doubles the lockout, up to LockoutMax.
+		LockoutThreshold int           `yaml:"lockout_threshold" env:"LOGIN_LOCKOUT_THRESHOLD"`
+		LockoutBase      time.Duration `yaml:"lockout_base" env:"LOGIN_LOCKOUT_BASE"`
+		LockoutMax       time.Duration `yaml:"lockout_max" env:"LOGIN_LOCKOUT_MAX"`
+	}
+
+	// QuotaConfig holds the default quotas of each role; admins can override
+	// them per user.
+	QuotaConfig struct {
+		Client     Quota `yaml:"client" env:"QUOTA_CLIENT_"`
+		Contractor Quota `yaml:"contractor" env:"QUOTA_CONTRACTOR_"`
+		Admin      Quota `yaml:"admin" env:"QUOTA_ADMIN_"`
+	}
+
+	// Quota limits are per user; zero means unlimited.
+	Quota struct {
+		RequestsPerMinute    int `yaml:"requests_per_minute" env:"REQUESTS_PER_MINUTE"`
+		TendersPerDay        int `yaml:"tenders_per_day" env:"TENDERS_PER_DAY"`
+		BidsPerTenderPerHour int `yaml:"bids_per_tender_per_hour" env:"BIDS_PER_TENDER_PER_HOUR"`
+	}
+
+	TracingConfig struct {
+		// Exporter is "none", "stdout" for local use, or "otlp". The stdout
+		// exporter writes to stderr so spans do not mix with the JSON logs.
+		Exporter string `yaml:"exporter" env:"TRACING_EXPORTER"`
+		// Endpoint is the OTLP/HTTP collector URL; empty uses the standard
+		// OTEL_EXPORTER_OTLP_* variables.
+		Endpoint    string `yaml:"endpoint" env:"TRACING_OTLP_ENDPOINT"`
+		ServiceName string `yaml:"service_name" env:"TRACING_SERVICE_NAME"`
+		// SampleRatio is the share of new traces kept, from 0 to 1.
+		SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO"`
+	}
+
+	LogConfig struct {
+		Level slog.Level `yaml:"level" env:"LOG_LEVEL"`
+		// Output is "stdout" or "file"; files are rotated by size.
+		Output     string `yaml:"output" env:"LOG_OUTPUT"`
+		File       string `yaml:"file" env:"LOG_FILE"`
+		MaxSizeMB  int    `yaml:"max_size_mb" env:"LOG_MAX_SIZE_MB"`
+		MaxBackups int    `yaml:"max_backups" env:"LOG_MAX_BACKUPS"`
+		MaxAgeDays int    `yaml:"max_age_days" env:"LOG_MAX_AGE_DAYS"`
+	}
+
+	S3Config struct {
+		Endpoint     string `yaml:"endpoint" env:"S3_ENDPOINT"`
+		Region       string `yaml:"region" env:"S3_REGION"`
+		Bucket       string `yaml:"bucket" env:"S3_BUCKET"`
+		AccessKey    string `yaml:"access_key" env:"S3_ACCESS_KEY" secret:"true"`
+		SecretKey    string `yaml:"secret_key" env:"S3_SECRET_KEY" secret:"true"`
+		UsePathStyle bool   `yaml:"path_style" env:"S3_PATH_STYLE"`
 	}
 )
 
-func (c *Config) Load() error {
-	if err := godotenv.Load(); err != nil {
-		return err
+// Default returns the configuration used for anything no source sets.
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Port:            ":8080",
+			ReadTimeout:     30 * time.Second,
+			WriteTimeout:    60 * time.Second,
+			IdleTimeout:     120 * time.Second,
+			ShutdownTimeout: 30 * time.Second,
+		},
+		MongoDb: MongoDbConfig{
+			Host:   "localhost",
+			Port:   "27017",
+			DBName: "tender",
+		},
+		Redis: RedisConfig{
+			URI: "localhost:6379",
+		},
+		Email: EmailConfig{
+			SmtpPort:       587,
+			DigestInterval: 24 * time.Hour,
+		},
+		Attachments: AttachmentConfig{
+			Driver:    "local",
+			LocalDir:  "attachments",
+			MaxSizeMB: 20,
+			URLTTL:    5 * time.Minute,
+			S3: S3Config{
+				Region:       "us-east-1",
+				UsePathStyle: true,
+			},
+		},
+		Search: SearchConfig{
+			Language: "english",
+		},
+		Questions: QuestionConfig{
+			Cutoff: 48 * time.Hour,
+		},
+		Retention: RetentionConfig{
+			Period:   30 * 24 * time.Hour,
+			Interval: 24 * time.Hour,
+		},
+		RateLimit: RateLimitConfig{
+			LoginIP:          RateLimit{Limit: 20, Window: time.Minute},
+			LoginUsername:    RateLimit{Limit: 10, Window: time.Minute},
+			RegisterIP:       RateLimit{Limit: 5, Window: time.Hour},
+			LockoutThreshold: 5,
+			LockoutBase:      time.Minute,
+			LockoutMax:       time.Hour,
+		},
+		Quotas: QuotaConfig{
+			Client:     Quota{RequestsPerMinute: 120, TendersPerDay: 20},
+			Contractor: Quota{RequestsPerMinute: 120, BidsPerTenderPerHour: 5},
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			ServiceName: "tender-api",
+			SampleRatio: 1,
+		},
+		Log: LogConfig{
+			Level:      slog.LevelInfo,
+			Output:     "stdout",
+			File:       "application.log",
+			MaxSizeMB:  100,
+			MaxBackups: 5,
+			MaxAgeDays: 30,
+		},
 	}
+}
 
-	smtpPort, err := strconv.Atoi(os.Getenv("SMTP_PORT"))
-	if err != nil {
-		return err
+// ConnectionURI returns URI, or a mongodb:// URI for Host and Port when it
+// is unset. Credentials are passed separately, see ConnectDB.
+func (m MongoDbConfig) ConnectionURI() string {
+	if m.URI != "" {
+		return m.URI
+	}
+	return (&url.URL{Scheme: "mongodb", Host: net.JoinHostPort(m.Host, m.Port)}).String()
+}
+
+// Config builds the TLS client configuration.
+func (t TLSConfig) Config() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: t.InsecureSkipVerify,
+	}
+
+	if t.CAFile != "" {
+		pem, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in CA file %s", t.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if t.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// parseRateLimit reads a limit written as "<requests>/<window>", e.g. "10/1m",
+// or "0" to disable it.
+func parseRateLimit(s string) (RateLimit, error) {
+	if s == "0" {
+		return RateLimit{}, nil
+	}
+
+	limit, window, ok := strings.Cut(s, "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: want <requests>/<window>", s)
 	}
 
-	c.Server.Port = os.Getenv("SERVER_PORT")
-	c.MongoDb.Host = os.Getenv("DB_HOST")
-	c.MongoDb.Port = os.Getenv("DB_PORT")
-	c.MongoDb.User = os.Getenv("DB_USER")
-	c.MongoDb.Password = os.Getenv("DB_PASSWORD")
-	c.MongoDb.DBName = os.Getenv("DB_NAME")
-	c.RedisURI = os.Getenv("REDIS_URI")
-	c.JWT.SecretKey = os.Getenv("JWT_SECRET_KEY")
+	n, err := strconv.Atoi(limit)
+	if err != nil {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: %w", s, err)
+	}
+	d, err := time.ParseDuration(window)
+	if err != nil {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: %w", s, err)
+	}
 
-	c.Email.SmtpHost = os.Getenv("SMTP_HOST")
-	c.Email.SmtpPort = smtpPort
-	c.Email.SmtpUser = os.Getenv("SMTP_USER")
-	c.Email.SmtpPass = os.Getenv("SMTP_PASS")
+	return RateLimit{Limit: n, Window: d}, nil
+}
 
+func (r *RateLimit) UnmarshalText(text []byte) error {
+	limit, err := parseRateLimit(string(text))
+	if err != nil {
+		return err
+	}
+	*r = limit
 	return nil
 }
 
-func New() (*Config, error) {
-	var config Config
-	if err := config.Load(); err != nil {
-		return nil, err
+func (r RateLimit) MarshalText() ([]byte, error) {
+	if r.Limit == 0 {
+		return []byte("0"), nil
 	}
-	return &config, nil
+	return []byte(fmt.Sprintf("%d/%s", r.Limit, r.Window)), nil
+}
+
+// New loads the configuration from the process's environment and
+// command-line flags; see Load.
+func New() (*Config, error) {
+	return Load(os.Args[1:], os.LookupEnv)
 }
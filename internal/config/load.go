@@ -0,0 +1,255 @@
+package config
+
+import (
+	"encoding"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Problems lists everything wrong with a configuration, so all of it can be
+// fixed in one go.
+type Problems []string
+
+func (p Problems) Error() string {
+	return "invalid configuration:\n  " + strings.Join(p, "\n  ")
+}
+
+// field is one configurable value.
+type field struct {
+	path   string // dotted yaml keys, e.g. server.read_timeout
+	env    string
+	secret bool
+	value  reflect.Value
+}
+
+var (
+	durationType      = reflect.TypeOf(time.Duration(0))
+	textUnmarshalType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Load reads the configuration from, in increasing precedence, Default, the
+// YAML file named by -config or CONFIG_FILE, environment variables looked
+// up with lookupEnv (after loading a .env file, if there is one) and flags
+// in args. It reports every invalid value and failed check as Problems.
+func Load(args []string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	cfg := Default()
+	fields := cfg.fields()
+
+	flags := flag.NewFlagSet("tender", flag.ContinueOnError)
+	flags.StringVar(&cfg.File, "config", "", "YAML config file (env CONFIG_FILE)")
+	flags.BoolVar(&cfg.PrintOnly, "print-config", false, "print the effective config, secrets hidden, and exit")
+	flagValues := make(map[string]*string, len(fields))
+	for _, f := range fields {
+		flagValues[f.path] = flags.String(f.path, "", "env "+f.env)
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	// Variables already set take precedence over .env.
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to load .env: %w", err)
+	}
+
+	var problems Problems
+	set := func(f field, source, value string) {
+		if err := setValue(f.value, value); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", source, err))
+		}
+	}
+
+	if cfg.File == "" {
+		cfg.File, _ = lookupEnv("CONFIG_FILE")
+	}
+	if cfg.File != "" {
+		values, err := readYAML(cfg.File)
+		if err != nil {
+			return nil, err
+		}
+		byPath := make(map[string]field, len(fields))
+		for _, f := range fields {
+			byPath[f.path] = f
+		}
+		for _, path := range sortedKeys(values) {
+			f, ok := byPath[path]
+			if !ok {
+				problems = append(problems, fmt.Sprintf("%s: unknown key %s", cfg.File, path))
+				continue
+			}
+			set(f, cfg.File+": "+path, values[path])
+		}
+	}
+
+	for _, f := range fields {
+		// Empty variables count as unset, as in example.env.
+		if v, ok := lookupEnv(f.env); ok && v != "" {
+			set(f, f.env, v)
+		}
+	}
+
+	flags.Visit(func(fl *flag.Flag) {
+		for _, f := range fields {
+			if f.path == fl.Name {
+				set(f, "-"+fl.Name, *flagValues[fl.Name])
+			}
+		}
+	})
+
+	cfg.Attachments.MaxSize = cfg.Attachments.MaxSizeMB << 20
+
+	problems = append(problems, cfg.validate()...)
+	if len(problems) > 0 {
+		return nil, problems
+	}
+	return &cfg, nil
+}
+
+// fields lists c's configurable values in declaration order.
+func (c *Config) fields() []field {
+	var fields []field
+	var walk func(v reflect.Value, path, env string)
+	walk = func(v reflect.Value, path, env string) {
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			name := sf.Tag.Get("yaml")
+			if name == "" || name == "-" {
+				continue
+			}
+			if path != "" {
+				name = path + "." + name
+			}
+			fv := v.Field(i)
+			if sf.Type.Kind() == reflect.Struct && !reflect.PointerTo(sf.Type).Implements(textUnmarshalType) {
+				walk(fv, name, env+sf.Tag.Get("env"))
+				continue
+			}
+			fields = append(fields, field{
+				path:   name,
+				env:    env + sf.Tag.Get("env"),
+				secret: sf.Tag.Get("secret") == "true",
+				value:  fv,
+			})
+		}
+	}
+	walk(reflect.ValueOf(c).Elem(), "", "")
+	return fields
+}
+
+func setValue(v reflect.Value, s string) error {
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", s)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		v.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", s)
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", v.Type())
+		}
+		var items []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// readYAML flattens a YAML file into dotted keys and scalar values; lists
+// become comma-separated values, as in environment variables.
+func readYAML(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	values := make(map[string]string)
+	var flatten func(prefix string, m map[string]any) error
+	flatten = func(prefix string, m map[string]any) error {
+		for key, value := range m {
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			switch value := value.(type) {
+			case nil:
+			case map[string]any:
+				if err := flatten(key, value); err != nil {
+					return err
+				}
+			case []any:
+				items := make([]string, len(value))
+				for i, item := range value {
+					if _, ok := item.(map[string]any); ok {
+						return fmt.Errorf("config file %s: %s: lists of maps are not supported", path, key)
+					}
+					items[i] = fmt.Sprint(item)
+				}
+				values[key] = strings.Join(items, ",")
+			default:
+				values[key] = fmt.Sprint(value)
+			}
+		}
+		return nil
+	}
+	if err := flatten("", doc); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// String lists every setting as "key = value" with secrets masked, so the
+// effective config can be printed or logged safely. URIs keep everything
+// but their password.
+func (c *Config) String() string {
+	var b strings.Builder
+	if c.File != "" {
+		fmt.Fprintf(&b, "# from %s\n", c.File)
+	}
+	for _, f := range c.fields() {
+		fmt.Fprintf(&b, "%s = %s\n", f.path, formatValue(f))
+	}
+	return b.String()
+}
+
+func formatValue(f field) string {
+	var value string
+	if m, ok := f.value.Interface().(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		if err != nil {
+			return fmt.Sprintf("<%v>", err)
+		}
+		value = string(text)
+	} else if items, ok := f.value.Interface().([]string); ok {
+		value = strings.Join(items, ",")
+	} else {
+		value = fmt.Sprint(f.value.Interface())
+	}
+
+	if !f.secret || value == "" {
+		return value
+	}
+	if u, err := url.Parse(value); err == nil && u.Scheme != "" && u.Host != "" {
+		return u.Redacted()
+	}
+	return "******"
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// validate checks the loaded values; each problem names the setting by its
+// key and variable.
+func (c *Config) validate() Problems {
+	envs := make(map[string]string)
+	for _, f := range c.fields() {
+		envs[f.path] = f.env
+	}
+
+	var problems Problems
+	check := func(ok bool, path, format string, args ...any) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s (%s): %s", path, envs[path], fmt.Sprintf(format, args...)))
+		}
+	}
+	oneOf := func(value, path string, allowed ...string) {
+		for _, a := range allowed {
+			if value == a {
+				return
+			}
+		}
+		check(false, path, "%q is not one of %s", value, strings.Join(allowed, ", "))
+	}
+	tlsFiles := func(t TLSConfig, path string) {
+		for _, file := range []struct{ name, path string }{
+			{t.CAFile, path + ".ca_file"},
+			{t.CertFile, path + ".cert_file"},
+			{t.KeyFile, path + ".key_file"},
+		} {
+			if file.name != "" {
+				_, err := os.Stat(file.name)
+				check(err == nil, file.path, "%v", err)
+			}
+		}
+		check((t.CertFile == "") == (t.KeyFile == ""), path+".cert_file", "cert_file and key_file must be set together")
+	}
+
+	check(c.Server.Port != "", "server.port", "is required")
+	check(c.Server.ReadTimeout > 0, "server.read_timeout", "must be positive")
+	check(c.Server.WriteTimeout > 0, "server.write_timeout", "must be positive")
+	check(c.Server.IdleTimeout > 0, "server.idle_timeout", "must be positive")
+	check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout", "must be positive")
+	for _, proxy := range c.Server.TrustedProxies {
+		_, _, err := net.ParseCIDR(proxy)
+		check(err == nil || net.ParseIP(proxy) != nil, "server.trusted_proxies", "%q is not an IP or CIDR", proxy)
+	}
+
+	if c.MongoDb.URI != "" {
+		u, err := url.Parse(c.MongoDb.URI)
+		check(err == nil && (u.Scheme == "mongodb" || u.Scheme == "mongodb+srv"), "mongodb.uri", "must be a mongodb:// or mongodb+srv:// URI")
+	} else {
+		check(c.MongoDb.Host != "", "mongodb.host", "is required when mongodb.uri is not set")
+		_, err := strconv.ParseUint(c.MongoDb.Port, 10, 16)
+		check(err == nil, "mongodb.port", "%q is not a port", c.MongoDb.Port)
+	}
+	check(c.MongoDb.Password == "" || c.MongoDb.User != "", "mongodb.password", "is set without mongodb.user")
+	check(c.MongoDb.DBName != "", "mongodb.database", "is required")
+	tlsFiles(c.MongoDb.TLS, "mongodb.tls")
+
+	check(c.Redis.URI != "", "redis.uri", "is required")
+	if strings.Contains(c.Redis.URI, "://") {
+		u, err := url.Parse(c.Redis.URI)
+		check(err == nil && (u.Scheme == "redis" || u.Scheme == "rediss"), "redis.uri", "must be host:port or a redis:// or rediss:// URL")
+	}
+	check(c.Redis.DB >= 0, "redis.db", "must not be negative")
+	tlsFiles(c.Redis.TLS, "redis.tls")
+
+	check(c.JWT.SecretKey != "", "jwt.secret_key", "is required")
+
+	check(c.Email.SmtpPort > 0 && c.Email.SmtpPort < 1<<16, "email.smtp_port", "%d is not a port", c.Email.SmtpPort)
+	check(c.Email.DigestInterval > 0, "email.digest_interval", "must be positive")
+
+	oneOf(c.Attachments.Driver, "attachments.storage", "local", "s3")
+	if c.Attachments.Driver == "s3" {
+		check(c.Attachments.S3.Bucket != "", "attachments.s3.bucket", "is required for s3 storage")
+	} else {
+		check(c.Attachments.LocalDir != "", "attachments.dir", "is required for local storage")
+	}
+	check(c.Attachments.MaxSizeMB > 0, "attachments.max_size_mb", "must be positive")
+	// Download links must not be forgeable by whoever can sign tokens, and
+	// rotating one key must not invalidate the other.
+	check(c.Attachments.SigningKey != "", "attachments.signing_key", "is required")
+	check(c.Attachments.SigningKey == "" || c.Attachments.SigningKey != c.JWT.SecretKey,
+		"attachments.signing_key", "must differ from jwt.secret_key")
+	check(c.Attachments.URLTTL > 0, "attachments.url_ttl", "must be positive")
+
+	check(c.Questions.Cutoff >= 0, "questions.cutoff", "must not be negative")
+	check(c.Retention.Period > 0, "retention.period", "must be positive")
+	check(c.Retention.Interval > 0, "retention.interval", "must be positive")
+
+	for _, rl := range []struct {
+		path  string
+		limit RateLimit
+	}{
+		{"rate_limit.login_ip", c.RateLimit.LoginIP},
+		{"rate_limit.login_username", c.RateLimit.LoginUsername},
+		{"rate_limit.register_ip", c.RateLimit.RegisterIP},
+	} {
+		check(rl.limit.Limit >= 0 && (rl.limit.Limit == 0 || rl.limit.Window > 0), rl.path, "needs a non-negative limit and a positive window")
+	}
+	check(c.RateLimit.LockoutThreshold >= 0, "rate_limit.lockout_threshold", "must not be negative")
+	check(c.RateLimit.LockoutBase > 0, "rate_limit.lockout_base", "must be positive")
+	check(c.RateLimit.LockoutMax >= c.RateLimit.LockoutBase, "rate_limit.lockout_max", "must be at least rate_limit.lockout_base")
+
+	for _, role := range []struct {
+		name  string
+		quota Quota
+	}{
+		{"client", c.Quotas.Client},
+		{"contractor", c.Quotas.Contractor},
+		{"admin", c.Quotas.Admin},
+	} {
+		quota, prefix := role.quota, "quotas."+role.name+"."
+		check(quota.RequestsPerMinute >= 0, prefix+"requests_per_minute", "must not be negative")
+		check(quota.TendersPerDay >= 0, prefix+"tenders_per_day", "must not be negative")
+		check(quota.BidsPerTenderPerHour >= 0, prefix+"bids_per_tender_per_hour", "must not be negative")
+	}
+
+	oneOf(c.Tracing.Exporter, "tracing.exporter", "none", "stdout", "otlp")
+	check(c.Tracing.ServiceName != "", "tracing.service_name", "is required")
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio", "must be between 0 and 1")
+
+	oneOf(c.Log.Output, "log.output", "stdout", "file")
+	if c.Log.Output == "file" {
+		check(c.Log.File != "", "log.file", "is required for file output")
+	}
+	check(c.Log.MaxSizeMB > 0, "log.max_size_mb", "must be positive")
+	check(c.Log.MaxBackups >= 0, "log.max_backups", "must not be negative")
+	check(c.Log.MaxAgeDays >= 0, "log.max_age_days", "must not be negative")
+
+	return problems
+}
@@ -23,7 +23,9 @@
 package app
 
 import (
+	"fmt"
 	"log/slog"
+	"net/http"
 
 	"github.com/casbin/casbin/v2"
 	"github.com/gin-contrib/cors"
@@ -33,10 +35,21 @@ import (
 	"github.com/zohirovs/internal/config"
 	_ "github.com/zohirovs/internal/http/app/docs"
 	"github.com/zohirovs/internal/http/handler"
+	"github.com/zohirovs/internal/metrics"
+	"github.com/zohirovs/internal/middleware"
 )
 
-func Run(handler *handler.Handler, logger *slog.Logger, config *config.Config, enforcer *casbin.Enforcer) error {
-	router := gin.Default()
+// NewServer builds the HTTP server with every route registered; the caller
+// starts it and shuts it down.
+func NewServer(handler *handler.Handler, logger *slog.Logger, config *config.Config, enforcer *casbin.Enforcer, limiter middleware.RateLimiter, quotaResolver middleware.QuotaResolver, activeUsers middleware.ActiveUsers) (*http.Server, error) {
+	router := gin.New()
+	router.Use(gin.Recovery())
+
+	// Only listed proxies may set the client IP used for rate limits and
+	// logs; with none, it is always the connection's remote address.
+	if err := router.SetTrustedProxies(config.Server.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
 
 	// CORS configuration
 	corsConfig := cors.DefaultConfig()
@@ -51,15 +64,41 @@ func Run(handler *handler.Handler, logger *slog.Logger, config *config.Config, e
 	url := ginSwagger.URL("/swagger/doc.json")
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, url, ginSwagger.PersistAuthorization(true)))
 
-	router.Use(gin.Logger())
-	router.Use(gin.Recovery())
+	// Probes are registered before the middleware below so they are not
+	// traced, logged or counted against quotas
+	router.GET("/healthz", handler.HealthHandler.Healthz)
+	router.GET("/readyz", handler.HealthHandler.Readyz)
+
+	router.Use(middleware.Tracing())
+	router.Use(middleware.Metrics())
+	router.Use(middleware.RequestMeta(config))
+	router.Use(middleware.AccessLog(logger))
+
+	// Tokens outlive their users; refuse those of deleted accounts
+	router.Use(middleware.ActiveUser(activeUsers, config, logger))
+
+	// Per-user quotas; creating tenders and bids is limited further below
+	quotas := func(rules ...middleware.QuotaRule) gin.HandlerFunc {
+		return middleware.Quota(limiter, quotaResolver, config, logger, rules...)
+	}
+	router.Use(quotas(middleware.RequestsPerMinute))
 
 	// API endpoints
 	// User endpoints
 	users := router.Group("/")
 	{
-		users.POST("/register", handler.UserHandler.RegisterUser)
-		users.POST("/login", handler.UserHandler.LoginUser)
+		limits := config.RateLimit
+		users.POST("/register",
+			middleware.RateLimit(limiter, logger,
+				middleware.RateRule{Name: "register:ip", RateLimit: limits.RegisterIP, Key: middleware.ByIP},
+			),
+			handler.UserHandler.RegisterUser)
+		users.POST("/login",
+			middleware.RateLimit(limiter, logger,
+				middleware.RateRule{Name: "login:ip", RateLimit: limits.LoginIP, Key: middleware.ByIP},
+				middleware.RateRule{Name: "login:username", RateLimit: limits.LoginUsername, Key: middleware.ByUsername},
+			),
+			handler.UserHandler.LoginUser)
 	}
 
 	// Client endpoints group
@@ -68,10 +107,32 @@ func Run(handler *handler.Handler, logger *slog.Logger, config *config.Config, e
 		// Tender endpoints
 		tenders := clients.Group("/tenders")
 		{
-			tenders.POST("", handler.TenderHandler.CreateTender)
+			tenders.POST("", quotas(middleware.TendersPerDay), handler.TenderHandler.CreateTender)
 			tenders.GET("/:id", handler.TenderHandler.GetTender)
 			tenders.PUT("/:id/status", handler.TenderHandler.UpdateTenderStatus)
 			tenders.DELETE("/:id", handler.TenderHandler.DeleteTender)
+			tenders.POST("/:id/attachments", handler.AttachmentHandler.UploadTenderAttachment)
+			tenders.GET("/:id/attachments", handler.AttachmentHandler.ListTenderAttachments)
+			tenders.POST("/:id/review", handler.ReputationHandler.ReviewContractor)
+			tenders.PUT("/:id/questions/:question_id/answer", handler.QuestionHandler.AnswerQuestion)
+			tenders.POST("/:id/invitations", handler.InvitationHandler.InviteContractors)
+			tenders.GET("/:id/invitations", handler.InvitationHandler.ListTenderInvitations)
+			tenders.POST("/:id/lots/:lot_id/award", handler.LotHandler.AwardLot)
+			tenders.GET("/:id/bids/compare", handler.BidHandler.CompareBids)
+			tenders.GET("/:id/bids/summary", handler.BidHandler.SummarizeBids)
+			tenders.POST("/:id/lots/:lot_id/cancel", handler.LotHandler.CancelLot)
+			tenders.POST("/:id/clone", quotas(middleware.TendersPerDay), handler.TemplateHandler.CloneTender)
+		}
+
+		// Tender template endpoints
+		templates := clients.Group("/templates")
+		{
+			templates.POST("", handler.TemplateHandler.CreateTemplate)
+			templates.GET("", handler.TemplateHandler.ListTemplates)
+			templates.GET("/:id", handler.TemplateHandler.GetTemplate)
+			templates.PUT("/:id", handler.TemplateHandler.UpdateTemplate)
+			templates.DELETE("/:id", handler.TemplateHandler.DeleteTemplate)
+			templates.POST("/:id/tenders", quotas(middleware.TendersPerDay), handler.TemplateHandler.CreateFromTemplate)
 		}
 
 		// Bid endpoints for clients (viewing bids)
@@ -84,13 +145,102 @@ func Run(handler *handler.Handler, logger *slog.Logger, config *config.Config, e
 	// Contractor endpoints group
 	contractors := router.Group("api/contractors")
 	{
+		contractors.GET("/profile", handler.ContractorHandler.GetOwnProfile)
+		contractors.PUT("/profile", handler.ContractorHandler.SaveProfile)
+		contractors.GET("/:id/profile", handler.ContractorHandler.GetProfile)
+		contractors.GET("/:id/reputation", handler.ReputationHandler.GetReputation)
+		contractors.GET("/:id/reviews", handler.ReputationHandler.ListReviews)
+
+		searches := contractors.Group("/searches")
+		{
+			searches.POST("", handler.SavedSearchHandler.CreateSavedSearch)
+			searches.GET("", handler.SavedSearchHandler.ListSavedSearches)
+			searches.DELETE("/:id", handler.SavedSearchHandler.DeleteSavedSearch)
+		}
+
+		contractors.POST("/tenders/:id/questions", handler.QuestionHandler.AskQuestion)
+		contractors.GET("/invitations", handler.InvitationHandler.ListMyInvitations)
+		contractors.PUT("/invitations/:id", handler.InvitationHandler.RespondInvitation)
+
 		// Bid endpoints for contractors (submitting bids)
 		bids := contractors.Group("/bids")
 		{
-			bids.POST("", handler.BidHandler.SubmitBid)
+			bids.POST("", quotas(middleware.BidsPerTenderPerHour), handler.BidHandler.SubmitBid)
+			bids.DELETE("/:id", handler.BidHandler.WithdrawBid)
+			bids.POST("/:id/attachments", handler.AttachmentHandler.UploadBidAttachment)
 		}
 	}
 
-	// Start the server
-	return router.Run(config.Server.Port)
+	// Admin endpoints
+	admin := router.Group("api/admin")
+	{
+		admin.GET("/contractors", handler.ContractorHandler.ListProfiles)
+		admin.PUT("/contractors/:id/verification", handler.ContractorHandler.VerifyContractor)
+		admin.POST("/categories", handler.CategoryHandler.CreateCategory)
+		admin.DELETE("/categories/:id", handler.CategoryHandler.DeleteCategory)
+		admin.GET("/audit", handler.AuditHandler.QueryAudit)
+		admin.GET("/audit/verify", handler.AuditHandler.VerifyAudit)
+		admin.POST("/tenders/:id/restore", handler.TenderHandler.RestoreTender)
+		admin.POST("/bids/:id/restore", handler.BidHandler.RestoreBid)
+		admin.DELETE("/users/:id", handler.UserHandler.DeleteUser)
+		admin.POST("/users/:id/restore", handler.UserHandler.RestoreUser)
+		admin.GET("/quotas", handler.QuotaHandler.ListQuotaOverrides)
+		admin.GET("/quotas/:user_id", handler.QuotaHandler.GetQuota)
+		admin.PUT("/quotas/:user_id", handler.QuotaHandler.SetQuotaOverride)
+		admin.DELETE("/quotas/:user_id", handler.QuotaHandler.DeleteQuotaOverride)
+	}
+
+	router.GET("api/categories", handler.CategoryHandler.ListCategories)
+	router.GET("api/tenders/search", handler.SearchHandler.SearchTenders)
+	router.GET("api/tenders/:id/questions", handler.QuestionHandler.ListQuestions)
+
+	// Prometheus scrape endpoint
+	router.GET("metrics", gin.WrapH(metrics.Handler()))
+
+	// Live tender updates; join a room with ?tender_id= (and ?token= when the
+	// Authorization header cannot be set)
+	router.GET("ws", handler.HandleWebSocket)
+
+	// Organization endpoints
+	organizations := router.Group("api/organizations")
+	{
+		organizations.POST("", handler.OrganizationHandler.CreateOrganization)
+		organizations.GET("", handler.OrganizationHandler.ListMyOrganizations)
+		organizations.GET("/invitations", handler.OrganizationHandler.ListMyInvites)
+		organizations.PUT("/invitations/:id", handler.OrganizationHandler.RespondInvite)
+		organizations.GET("/:id", handler.OrganizationHandler.GetOrganization)
+		organizations.GET("/:id/members", handler.OrganizationHandler.ListMembers)
+		organizations.PUT("/:id/members/:user_id", handler.OrganizationHandler.ChangeMemberRole)
+		organizations.DELETE("/:id/members/:user_id", handler.OrganizationHandler.RemoveMember)
+		organizations.POST("/:id/invitations", handler.OrganizationHandler.InviteMember)
+		organizations.GET("/:id/invitations", handler.OrganizationHandler.ListInvites)
+		organizations.GET("/:id/tenders", handler.OrganizationHandler.ListTenders)
+		organizations.GET("/:id/bids", handler.OrganizationHandler.ListBids)
+	}
+
+	// Notification endpoints
+	notifications := router.Group("api/notifications")
+	{
+		notifications.GET("", handler.NotificationHandler.ListNotifications)
+		notifications.PUT("/:id/read", handler.NotificationHandler.MarkRead)
+	}
+
+	// Attachment endpoints (downloads are authorized by the signed link)
+	attachments := router.Group("api/attachments")
+	{
+		attachments.GET("/:id/url", handler.AttachmentHandler.GetDownloadURL)
+		attachments.GET("/:id/download", handler.AttachmentHandler.Download)
+	}
+
+	addr := config.Server.Port
+	if addr == "" {
+		addr = ":8080"
+	}
+	return &http.Server{
+		Addr:         addr,
+		Handler:      router,
+		ReadTimeout:  config.Server.ReadTimeout,
+		WriteTimeout: config.Server.WriteTimeout,
+		IdleTimeout:  config.Server.IdleTimeout,
+	}, nil
 }
@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zohirovs/internal/config"
+	"github.com/zohirovs/internal/middleware"
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/service"
+)
+
+// multipartOverhead is the slack allowed on top of the file size for the
+// multipart envelope when capping the request body.
+const multipartOverhead = 1 << 20
+
+type AttachmentHandler struct {
+	ser    *service.AttachmentService
+	logger *slog.Logger
+	cfg    *config.Config
+}
+
+func NewAttachmentHandler(logger *slog.Logger, ser *service.AttachmentService, cfg *config.Config) *AttachmentHandler {
+	return &AttachmentHandler{
+		ser:    ser,
+		logger: logger,
+		cfg:    cfg,
+	}
+}
+
+// UploadTenderAttachment godoc
+// @Summary      Upload a tender document
+// @Description  Upload a file (PDF, image, zip/Office, text) to a tender. Only the tender owner may upload.
+// @Tags         attachments
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id   path     string true "Tender ID"
+// @Param        file formData file   true "Document"
+// @Success      201 {object} models.Attachment
+// @Failure      400 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse
+// @Failure      413 {object} ErrorResponse
+// @Failure      415 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/clients/tenders/{id}/attachments [post]
+func (h *AttachmentHandler) UploadTenderAttachment(c *gin.Context) {
+	h.upload(c, h.ser.UploadTenderAttachment)
+}
+
+// UploadBidAttachment godoc
+// @Summary      Upload a technical proposal
+// @Description  Upload a file to a bid. Only the contractor who submitted the bid may upload.
+// @Tags         attachments
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id   path     string true "Bid ID"
+// @Param        file formData file   true "Document"
+// @Success      201 {object} models.Attachment
+// @Failure      400 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse
+// @Failure      413 {object} ErrorResponse
+// @Failure      415 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/contractors/bids/{id}/attachments [post]
+func (h *AttachmentHandler) UploadBidAttachment(c *gin.Context) {
+	h.upload(c, h.ser.UploadBidAttachment)
+}
+
+// ListTenderAttachments godoc
+// @Summary      List tender documents
+// @Description  List the documents attached to a tender. Available to the owner and contractors who bid on it.
+// @Tags         attachments
+// @Produce      json
+// @Param        id path string true "Tender ID"
+// @Success      200 {array}  models.Attachment
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/clients/tenders/{id}/attachments [get]
+func (h *AttachmentHandler) ListTenderAttachments(c *gin.Context) {
+	attachments, err := h.ser.ListTenderAttachments(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"))
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to list attachments", "error", err)
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, attachments)
+}
+
+// GetDownloadURL godoc
+// @Summary      Get a signed download link
+// @Description  Returns a short-lived URL for downloading an attachment
+// @Tags         attachments
+// @Produce      json
+// @Param        id path string true "Attachment ID"
+// @Success      200 {object} models.AttachmentURL
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/attachments/{id}/url [get]
+func (h *AttachmentHandler) GetDownloadURL(c *gin.Context) {
+	url, err := h.ser.SignedURL(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"))
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to sign attachment url", "error", err)
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, url)
+}
+
+// Download godoc
+// @Summary      Download an attachment
+// @Description  Streams an attachment using a signed link obtained from the url endpoint
+// @Tags         attachments
+// @Produce      octet-stream
+// @Param        id        path  string true "Attachment ID"
+// @Param        expires   query string true "Expiry (unix seconds)"
+// @Param        signature query string true "Link signature"
+// @Success      200
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Router       /api/attachments/{id}/download [get]
+func (h *AttachmentHandler) Download(c *gin.Context) {
+	attachment, content, err := h.ser.Open(c.Request.Context(), c.Param("id"), c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to open attachment", "error", err)
+		h.respondError(c, err)
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition":    fmt.Sprintf("attachment; filename=%q", attachment.FileName),
+		"X-Content-Type-Options": "nosniff",
+		"X-Checksum-Sha256":      attachment.Checksum,
+	})
+}
+
+type uploadFunc func(ctx context.Context, userID, parentID, fileName string, size int64, content io.Reader) (*models.Attachment, error)
+
+func (h *AttachmentHandler) upload(c *gin.Context, store uploadFunc) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.ser.MaxSize()+multipartOverhead)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to read uploaded file", "error", err)
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: "File is too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "A file is required in the 'file' form field"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to open uploaded file", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid file"})
+		return
+	}
+	defer file.Close()
+
+	attachment, err := store(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"), fileHeader.Filename, fileHeader.Size, file)
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to upload attachment", "error", err)
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, attachment)
+}
+
+func (h *AttachmentHandler) respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Access denied"})
+	case errors.Is(err, service.ErrInvalidSignature):
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Invalid or expired download link"})
+	case errors.Is(err, service.ErrFileTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: "File is too large"})
+	case errors.Is(err, service.ErrUnsupportedFileType):
+		c.JSON(http.StatusUnsupportedMediaType, ErrorResponse{Error: "Unsupported file type"})
+	case strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to process attachment"})
+	}
+}
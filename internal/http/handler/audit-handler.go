@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zohirovs/internal/config"
+	"github.com/zohirovs/internal/middleware"
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/service"
+)
+
+type AuditHandler struct {
+	ser    *service.AuditService
+	logger *slog.Logger
+	cfg    *config.Config
+}
+
+func NewAuditHandler(logger *slog.Logger, ser *service.AuditService, cfg *config.Config) *AuditHandler {
+	return &AuditHandler{
+		ser:    ser,
+		logger: logger,
+		cfg:    cfg,
+	}
+}
+
+// QueryAudit godoc
+// @Summary      Query the audit log
+// @Description  Admin only. Newest entries first; page with before_seq.
+// @Tags         admin
+// @Produce      json
+// @Param        actor_id      query    string false "Actor user ID"
+// @Param        action        query    string false "Action, e.g. tender.created"
+// @Param        resource_type query    string false "Resource type"
+// @Param        resource_id   query    string false "Resource ID"
+// @Param        request_id    query    string false "Request ID"
+// @Param        from          query    string false "Earliest time (RFC3339)"
+// @Param        to            query    string false "Latest time (RFC3339)"
+// @Param        before_seq    query    int    false "Only entries older than this sequence number"
+// @Param        limit         query    int    false "Page size (max 500)"
+// @Success      200 {array}  models.AuditEntry
+// @Failure      400 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/admin/audit [get]
+func (h *AuditHandler) QueryAudit(c *gin.Context) {
+	if middleware.GetUserRole(c, h.cfg) != string(models.Admin) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Admin access required"})
+		return
+	}
+
+	var query models.AuditQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to bind query", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid query parameters"})
+		return
+	}
+
+	entries, err := h.ser.Query(c.Request.Context(), &query)
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to query audit log", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to query audit log"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// VerifyAudit godoc
+// @Summary      Verify the audit log hash chain
+// @Description  Admin only. Walks the whole chain and reports the first broken entry.
+// @Tags         admin
+// @Produce      json
+// @Success      200 {object} models.AuditVerification
+// @Failure      403 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/admin/audit/verify [get]
+func (h *AuditHandler) VerifyAudit(c *gin.Context) {
+	if middleware.GetUserRole(c, h.cfg) != string(models.Admin) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Admin access required"})
+		return
+	}
+
+	result, err := h.ser.Verify(c.Request.Context())
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to verify audit log", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to verify audit log"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -2,9 +2,11 @@
 package handler
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/zohirovs/internal/config"
@@ -36,19 +38,21 @@ func NewBidHandler(logger *slog.Logger, ser *service.BidService, cfg *config.Con
 // @Param        bid body     models.CreateBid true "Bid object"
 // @Success      201  {object}  models.Bid
 // @Failure      400  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
 // @Failure      404  {object}  ErrorResponse
 // @Failure      500  {object}  ErrorResponse
 // @Router       /api/contractors/bids [post]
 func (h *BidHandler) SubmitBid(c *gin.Context) {
 	var createBid models.CreateBid
 	if err := c.ShouldBindJSON(&createBid); err != nil {
-		h.logger.Error("failed to bind JSON", "error", err)
+		h.logger.ErrorContext(c.Request.Context(), "failed to bind JSON", "error", err)
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
 		return
 	}
 
-	// Validate price and delivery time
-	if createBid.Price <= 0 {
+	// Validate price and delivery time; lot and itemized bids are priced
+	// per lot or line
+	if len(createBid.LotPrices) == 0 && len(createBid.Lines) == 0 && createBid.Price <= 0 {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Price must be greater than 0"})
 		return
 	}
@@ -63,11 +67,28 @@ func (h *BidHandler) SubmitBid(c *gin.Context) {
 		Price:        createBid.Price,
 		DeliveryTime: createBid.DeliveryTime,
 		Comments:     createBid.Comments,
+		LotPrices:    createBid.LotPrices,
+		Lines:        createBid.Lines,
+
+		OrganizationId: createBid.OrganizationId,
 	}
 
 	createdBid, err := h.ser.CreateBid(c.Request.Context(), &bid)
 	if err != nil {
-		h.logger.Error("failed to create bid", "error", err)
+		h.logger.ErrorContext(c.Request.Context(), "failed to create bid", "error", err)
+		if errors.Is(err, service.ErrNotQualified) || errors.Is(err, service.ErrNotInvited) || errors.Is(err, service.ErrInvitationNotAccepted) ||
+			errors.Is(err, service.ErrForbidden) || errors.Is(err, service.ErrAccountKind) {
+			c.JSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "organization not found") {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Unknown organization"})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidLots) || errors.Is(err, service.ErrInvalidLines) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to create bid"})
 		return
 	}
@@ -77,20 +98,23 @@ func (h *BidHandler) SubmitBid(c *gin.Context) {
 
 // ListBidsForTender godoc
 // @Summary      List all bids for a tender
-// @Description  Retrieve all bids submitted for a specific tender with optional filtering
+// @Description  Retrieve all bids submitted for your tender with optional filtering
 // @Tags         bids
 // @Accept       json
 // @Produce      json
-// @Param        tender_id    path      string  true  "Tender ID"
+// @Param        id           path      string  true  "Tender ID"
 // @Param        min_price    query     number  false "Minimum price filter"
 // @Param        max_price    query     number  false "Maximum price filter"
 // @Param        max_delivery query     number  false "Maximum delivery time filter"
+// @Param        lot_id       query     string  false "Only bids pricing this lot"
 // @Success      200  {array}   models.Bid
 // @Failure      400  {object}  ErrorResponse
+// @Failure      403  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
 // @Failure      500  {object}  ErrorResponse
-// @Router       /api/clients/tenders/{tender_id}/bids [get]
+// @Router       /api/clients/bids/tender/{id} [get]
 func (h *BidHandler) ListBidsForTender(c *gin.Context) {
-	tenderId := c.Param("tender_id")
+	tenderId := c.Param("id")
 	if tenderId == "" {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Tender ID is required"})
 		return
@@ -103,7 +127,7 @@ func (h *BidHandler) ListBidsForTender(c *gin.Context) {
 	if minPrice := c.Query("min_price"); minPrice != "" {
 		price, err := strconv.ParseFloat(minPrice, 64)
 		if err != nil {
-			h.logger.Error("invalid min_price parameter", "error", err)
+			h.logger.ErrorContext(c.Request.Context(), "invalid min_price parameter", "error", err)
 			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid min_price parameter"})
 			return
 		}
@@ -115,7 +139,7 @@ func (h *BidHandler) ListBidsForTender(c *gin.Context) {
 	if maxPrice := c.Query("max_price"); maxPrice != "" {
 		price, err := strconv.ParseFloat(maxPrice, 64)
 		if err != nil {
-			h.logger.Error("invalid max_price parameter", "error", err)
+			h.logger.ErrorContext(c.Request.Context(), "invalid max_price parameter", "error", err)
 			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid max_price parameter"})
 			return
 		}
@@ -128,7 +152,7 @@ func (h *BidHandler) ListBidsForTender(c *gin.Context) {
 	if maxDelivery := c.Query("max_delivery"); maxDelivery != "" {
 		days, err := strconv.ParseInt(maxDelivery, 10, 64)
 		if err != nil {
-			h.logger.Error("invalid max_delivery parameter", "error", err)
+			h.logger.ErrorContext(c.Request.Context(), "invalid max_delivery parameter", "error", err)
 			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid max_delivery parameter"})
 			return
 		}
@@ -137,13 +161,149 @@ func (h *BidHandler) ListBidsForTender(c *gin.Context) {
 		}
 	}
 
+	if lotId := c.Query("lot_id"); lotId != "" {
+		filter["lot_prices.lot_id"] = lotId
+	}
+
 	// Call service with separate tenderId parameter
-	bids, err := h.ser.ListBidsForTender(c.Request.Context(), tenderId, filter)
+	bids, err := h.ser.ListBidsForTender(c.Request.Context(), middleware.GetUserId(c, h.cfg), tenderId, filter)
 	if err != nil {
-		h.logger.Error("failed to list bids", "error", err, "tender_id", tenderId)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to retrieve bids"})
+		h.logger.ErrorContext(c.Request.Context(), "failed to list bids", "error", err, "tender_id", tenderId)
+		switch {
+		case errors.Is(err, service.ErrForbidden):
+			c.JSON(http.StatusForbidden, ErrorResponse{Error: "Only the tender owner can view its bids"})
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Tender not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to retrieve bids"})
+		}
 		return
 	}
 
 	c.JSON(http.StatusOK, bids)
 }
+
+// CompareBids godoc
+// @Summary      Compare bids line by line
+// @Description  Side-by-side unit prices and totals of every bid for each line item of your tender
+// @Tags         bids
+// @Produce      json
+// @Param        id path string true "Tender ID"
+// @Success      200 {object} models.BidComparison
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/clients/tenders/{id}/bids/compare [get]
+func (h *BidHandler) CompareBids(c *gin.Context) {
+	comparison, err := h.ser.CompareBids(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"))
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to compare bids", "error", err)
+		switch {
+		case errors.Is(err, service.ErrForbidden):
+			c.JSON(http.StatusForbidden, ErrorResponse{Error: "Only the tender owner can compare bids"})
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Tender not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to compare bids"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, comparison)
+}
+
+// SummarizeBids godoc
+// @Summary      Summarize bids
+// @Description  Number of bids on your tender and the lowest price offered
+// @Tags         bids
+// @Produce      json
+// @Param        id path string true "Tender ID"
+// @Success      200 {object} models.BidSummary
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/clients/tenders/{id}/bids/summary [get]
+func (h *BidHandler) SummarizeBids(c *gin.Context) {
+	summary, err := h.ser.SummarizeBids(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"))
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to summarize bids", "error", err)
+		switch {
+		case errors.Is(err, service.ErrForbidden):
+			c.JSON(http.StatusForbidden, ErrorResponse{Error: "Only the tender owner can view the bid summary"})
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Tender not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to summarize bids"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// WithdrawBid godoc
+// @Summary      Withdraw a bid
+// @Description  Deletes a pending bid while its tender is still open. An admin can restore it.
+// @Tags         bids
+// @Produce      json
+// @Param        id path     string true "Bid ID"
+// @Success      204
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      409 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/contractors/bids/{id} [delete]
+func (h *BidHandler) WithdrawBid(c *gin.Context) {
+	err := h.ser.WithdrawBid(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"))
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to withdraw bid", "error", err)
+		switch {
+		case errors.Is(err, service.ErrForbidden):
+			c.JSON(http.StatusForbidden, ErrorResponse{Error: "You cannot manage this bid"})
+		case errors.Is(err, service.ErrBidNotWithdrawable):
+			c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Bid not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to withdraw bid"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// RestoreBid godoc
+// @Summary      Restore a withdrawn bid
+// @Description  Admin only. Bids deleted with their tender are restored with the tender instead.
+// @Tags         admin
+// @Produce      json
+// @Param        id path     string true "Bid ID"
+// @Success      200 {object} models.Bid
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      409 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/admin/bids/{id}/restore [post]
+func (h *BidHandler) RestoreBid(c *gin.Context) {
+	if middleware.GetUserRole(c, h.cfg) != string(models.Admin) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Admin access required"})
+		return
+	}
+
+	bid, err := h.ser.RestoreBid(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to restore bid", "error", err)
+		switch {
+		case errors.Is(err, service.ErrTenderDeleted):
+			c.JSON(http.StatusConflict, ErrorResponse{Error: "The bid's tender is deleted; restore the tender instead"})
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Deleted bid not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to restore bid"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, bid)
+}
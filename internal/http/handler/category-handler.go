@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zohirovs/internal/config"
+	"github.com/zohirovs/internal/middleware"
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/service"
+)
+
+type CategoryHandler struct {
+	ser    *service.CategoryService
+	logger *slog.Logger
+	cfg    *config.Config
+}
+
+func NewCategoryHandler(logger *slog.Logger, ser *service.CategoryService, cfg *config.Config) *CategoryHandler {
+	return &CategoryHandler{
+		ser:    ser,
+		logger: logger,
+		cfg:    cfg,
+	}
+}
+
+// ListCategories godoc
+// @Summary      List tender categories
+// @Description  Flat list; build the tree from parent_id or path
+// @Tags         categories
+// @Produce      json
+// @Success      200 {array}  models.Category
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/categories [get]
+func (h *CategoryHandler) ListCategories(c *gin.Context) {
+	categories, err := h.ser.ListCategories(c.Request.Context())
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to list categories", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to list categories"})
+		return
+	}
+
+	c.JSON(http.StatusOK, categories)
+}
+
+// CreateCategory godoc
+// @Summary      Create a tender category
+// @Description  Admin only. Set parent_id to create a subcategory.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        category body     models.CreateCategory true "Category"
+// @Success      201 {object} models.Category
+// @Failure      400 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/admin/categories [post]
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	if middleware.GetUserRole(c, h.cfg) != string(models.Admin) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Admin access required"})
+		return
+	}
+
+	var req models.CreateCategory
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+
+	category, err := h.ser.CreateCategory(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to create category", "error", err)
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Parent category not found"})
+		case strings.Contains(err.Error(), "already in use"):
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Category slug already in use"})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to create category"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, category)
+}
+
+// DeleteCategory godoc
+// @Summary      Delete a tender category
+// @Description  Admin only. Only categories without subcategories can be deleted.
+// @Tags         admin
+// @Produce      json
+// @Param        id path string true "Category ID"
+// @Success      204
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      409 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/admin/categories/{id} [delete]
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	if middleware.GetUserRole(c, h.cfg) != string(models.Admin) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Admin access required"})
+		return
+	}
+
+	err := h.ser.DeleteCategory(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to delete category", "error", err)
+		switch {
+		case errors.Is(err, service.ErrCategoryHasChildren):
+			c.JSON(http.StatusConflict, ErrorResponse{Error: "Category has subcategories"})
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Category not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to delete category"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
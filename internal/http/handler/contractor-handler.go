@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zohirovs/internal/config"
+	"github.com/zohirovs/internal/middleware"
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/service"
+)
+
+type ContractorHandler struct {
+	ser    *service.ContractorService
+	logger *slog.Logger
+	cfg    *config.Config
+}
+
+func NewContractorHandler(logger *slog.Logger, ser *service.ContractorService, cfg *config.Config) *ContractorHandler {
+	return &ContractorHandler{
+		ser:    ser,
+		logger: logger,
+		cfg:    cfg,
+	}
+}
+
+// SaveProfile godoc
+// @Summary      Create or update the contractor profile
+// @Description  Saves the authenticated contractor's company profile. Any change resets verification to pending.
+// @Tags         contractors
+// @Accept       json
+// @Produce      json
+// @Param        profile body     models.UpsertContractorProfile true "Contractor profile"
+// @Success      200 {object} models.ContractorProfile
+// @Failure      400 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/contractors/profile [put]
+func (h *ContractorHandler) SaveProfile(c *gin.Context) {
+	if middleware.GetUserRole(c, h.cfg) != string(models.Contractor) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Only contractors have a contractor profile"})
+		return
+	}
+
+	var req models.UpsertContractorProfile
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+
+	profile, err := h.ser.SaveProfile(c.Request.Context(), middleware.GetUserId(c, h.cfg), &req)
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to save contractor profile", "error", err)
+		if strings.Contains(err.Error(), "already in use") {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Registration number already in use"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to save contractor profile"})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// GetOwnProfile godoc
+// @Summary      Get own contractor profile
+// @Tags         contractors
+// @Produce      json
+// @Success      200 {object} models.ContractorProfile
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/contractors/profile [get]
+func (h *ContractorHandler) GetOwnProfile(c *gin.Context) {
+	h.getProfile(c, middleware.GetUserId(c, h.cfg))
+}
+
+// GetProfile godoc
+// @Summary      Get a contractor profile
+// @Tags         contractors
+// @Produce      json
+// @Param        id path string true "Contractor user ID"
+// @Success      200 {object} models.ContractorProfile
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/contractors/{id}/profile [get]
+func (h *ContractorHandler) GetProfile(c *gin.Context) {
+	h.getProfile(c, c.Param("id"))
+}
+
+// ListProfiles godoc
+// @Summary      List contractor profiles for review
+// @Description  Admin only. Optionally filter by verification status.
+// @Tags         admin
+// @Produce      json
+// @Param        status query string false "pending, verified or rejected"
+// @Success      200 {array}  models.ContractorProfile
+// @Failure      403 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/admin/contractors [get]
+func (h *ContractorHandler) ListProfiles(c *gin.Context) {
+	if !h.isAdmin(c) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Admin access required"})
+		return
+	}
+
+	profiles, err := h.ser.ListProfiles(c.Request.Context(), models.VerificationStatus(c.Query("status")))
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to list contractor profiles", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to list contractor profiles"})
+		return
+	}
+
+	c.JSON(http.StatusOK, profiles)
+}
+
+// VerifyContractor godoc
+// @Summary      Set a contractor's verification status
+// @Description  Admin only.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id      path string                  true "Contractor user ID"
+// @Param        request body models.VerifyContractor true "Verification decision"
+// @Success      200 {object} models.ContractorProfile
+// @Failure      400 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/admin/contractors/{id}/verification [put]
+func (h *ContractorHandler) VerifyContractor(c *gin.Context) {
+	if !h.isAdmin(c) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Admin access required"})
+		return
+	}
+
+	var req models.VerifyContractor
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+
+	profile, err := h.ser.Verify(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"), &req)
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to verify contractor", "error", err)
+		switch {
+		case strings.Contains(err.Error(), "invalid verification status"):
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid verification status"})
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Contractor profile not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to verify contractor"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+func (h *ContractorHandler) getProfile(c *gin.Context, userID string) {
+	profile, err := h.ser.GetProfile(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to get contractor profile", "error", err)
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Contractor profile not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to get contractor profile"})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+func (h *ContractorHandler) isAdmin(c *gin.Context) bool {
+	return middleware.GetUserRole(c, h.cfg) == string(models.Admin)
+}
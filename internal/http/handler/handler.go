@@ -21,16 +21,50 @@ type Handler struct {
 	BidHandler          *BidHandler
 	NotificationHandler *NotificationHandler
 	TenderHandler       *TenderHandler
+	AttachmentHandler   *AttachmentHandler
+	ContractorHandler   *ContractorHandler
+	ReputationHandler   *ReputationHandler
+	CategoryHandler     *CategoryHandler
+	SavedSearchHandler  *SavedSearchHandler
+	SearchHandler       *SearchHandler
+	QuestionHandler     *QuestionHandler
+	InvitationHandler   *InvitationHandler
+	LotHandler          *LotHandler
+	TemplateHandler     *TemplateHandler
+	OrganizationHandler *OrganizationHandler
+	AuditHandler        *AuditHandler
+	QuotaHandler        *QuotaHandler
+	HealthHandler       *HealthHandler
 	WsManager           *websocket.Manager
+
+	tenders *service.TenderService
+	logger  *slog.Logger
+	cfg     *config.Config
 }
 
-func NewHandler(logger *slog.Logger, service *service.Service, cfg *config.Config) *Handler {
-	wsManager := websocket.NewManager()
+func NewHandler(logger *slog.Logger, service *service.Service, cfg *config.Config, wsManager *websocket.Manager) *Handler {
 	return &Handler{
-		UserHandler:         NewUserHandler(logger, service.User),
+		UserHandler:         NewUserHandler(logger, service.User, cfg),
 		BidHandler:          NewBidHandler(logger, service.Bid, cfg),
-		NotificationHandler: NewNotificationHandler(logger, service.Notification),
+		NotificationHandler: NewNotificationHandler(logger, service.Notification, cfg),
 		TenderHandler:       NewTenderHandler(logger, service.Tender, cfg),
+		AttachmentHandler:   NewAttachmentHandler(logger, service.Attachment, cfg),
+		ContractorHandler:   NewContractorHandler(logger, service.Contractor, cfg),
+		ReputationHandler:   NewReputationHandler(logger, service.Reputation, cfg),
+		CategoryHandler:     NewCategoryHandler(logger, service.Category, cfg),
+		SavedSearchHandler:  NewSavedSearchHandler(logger, service.SavedSearch, cfg),
+		SearchHandler:       NewSearchHandler(logger, service.Search, cfg),
+		QuestionHandler:     NewQuestionHandler(logger, service.Question, cfg),
+		InvitationHandler:   NewInvitationHandler(logger, service.Invitation, cfg),
+		LotHandler:          NewLotHandler(logger, service.Lot, cfg),
+		TemplateHandler:     NewTemplateHandler(logger, service.Template, cfg),
+		OrganizationHandler: NewOrganizationHandler(logger, service.Organization, cfg),
+		AuditHandler:        NewAuditHandler(logger, service.Audit, cfg),
+		QuotaHandler:        NewQuotaHandler(logger, service.Quota, cfg),
+		HealthHandler:       NewHealthHandler(logger, service.Health, cfg),
 		WsManager:           wsManager,
+		tenders:             service.Tender,
+		logger:              logger,
+		cfg:                 cfg,
 	}
 }
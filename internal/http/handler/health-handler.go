@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zohirovs/internal/config"
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/service"
+)
+
+type HealthHandler struct {
+	ser    *service.HealthService
+	logger *slog.Logger
+	cfg    *config.Config
+}
+
+func NewHealthHandler(logger *slog.Logger, ser *service.HealthService, cfg *config.Config) *HealthHandler {
+	return &HealthHandler{
+		ser:    ser,
+		logger: logger,
+		cfg:    cfg,
+	}
+}
+
+// Healthz godoc
+// @Summary      Liveness probe
+// @Description  Answers while the process is running; it does not check dependencies.
+// @Tags         health
+// @Produce      json
+// @Success      200 {object} map[string]string
+// @Router       /healthz [get]
+func (h *HealthHandler) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": models.HealthOK})
+}
+
+// Readyz godoc
+// @Summary      Readiness probe
+// @Description  Pings MongoDB and Redis. Fails when MongoDB is down; Redis being down only degrades it, since the API runs uncached without it.
+// @Tags         health
+// @Produce      json
+// @Success      200 {object} models.Readiness
+// @Failure      503 {object} models.Readiness
+// @Router       /readyz [get]
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	readiness := h.ser.Ready(c.Request.Context())
+
+	status := http.StatusOK
+	if readiness.Status == models.HealthUnavailable {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, readiness)
+}
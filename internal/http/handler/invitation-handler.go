@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zohirovs/internal/config"
+	"github.com/zohirovs/internal/middleware"
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/service"
+)
+
+type InvitationHandler struct {
+	ser    *service.InvitationService
+	logger *slog.Logger
+	cfg    *config.Config
+}
+
+func NewInvitationHandler(logger *slog.Logger, ser *service.InvitationService, cfg *config.Config) *InvitationHandler {
+	return &InvitationHandler{
+		ser:    ser,
+		logger: logger,
+		cfg:    cfg,
+	}
+}
+
+// InviteContractors godoc
+// @Summary      Invite contractors to an invite-only tender
+// @Description  Invitees are given by user ID or email; emails without an account can be claimed after registering
+// @Tags         invitations
+// @Accept       json
+// @Produce      json
+// @Param        id          path     string                   true "Tender ID"
+// @Param        invitations body     models.InviteContractors true "Invitees"
+// @Success      201 {array}  models.Invitation
+// @Failure      400 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/clients/tenders/{id}/invitations [post]
+func (h *InvitationHandler) InviteContractors(c *gin.Context) {
+	var req models.InviteContractors
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+	if len(req.ContractorIds) == 0 && len(req.Emails) == 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "contractor_ids or emails is required"})
+		return
+	}
+
+	invitations, err := h.ser.Invite(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"), &req)
+	if err != nil {
+		h.respondError(c, "failed to invite contractors", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, invitations)
+}
+
+// ListTenderInvitations godoc
+// @Summary      List invitations to your tender
+// @Tags         invitations
+// @Produce      json
+// @Param        id path string true "Tender ID"
+// @Success      200 {array}  models.Invitation
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/clients/tenders/{id}/invitations [get]
+func (h *InvitationHandler) ListTenderInvitations(c *gin.Context) {
+	invitations, err := h.ser.ListTenderInvitations(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"))
+	if err != nil {
+		h.respondError(c, "failed to list tender invitations", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invitations)
+}
+
+// ListMyInvitations godoc
+// @Summary      List my tender invitations
+// @Tags         invitations
+// @Produce      json
+// @Success      200 {array}  models.Invitation
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/contractors/invitations [get]
+func (h *InvitationHandler) ListMyInvitations(c *gin.Context) {
+	invitations, err := h.ser.ListMyInvitations(c.Request.Context(), middleware.GetUserId(c, h.cfg))
+	if err != nil {
+		h.respondError(c, "failed to list invitations", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invitations)
+}
+
+// RespondInvitation godoc
+// @Summary      Accept or decline a tender invitation
+// @Description  Bidding on an invite-only tender requires an accepted invitation
+// @Tags         invitations
+// @Accept       json
+// @Produce      json
+// @Param        id       path     string                   true "Invitation ID"
+// @Param        response body     models.RespondInvitation true "Response"
+// @Success      200 {object} models.Invitation
+// @Failure      400 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/contractors/invitations/{id} [put]
+func (h *InvitationHandler) RespondInvitation(c *gin.Context) {
+	if middleware.GetUserRole(c, h.cfg) != string(models.Contractor) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Only contractors can respond to invitations"})
+		return
+	}
+
+	var req models.RespondInvitation
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+
+	invitation, err := h.ser.Respond(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"), &req)
+	if err != nil {
+		h.respondError(c, "failed to respond to invitation", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invitation)
+}
+
+func (h *InvitationHandler) respondError(c *gin.Context, msg string, err error) {
+	h.logger.ErrorContext(c.Request.Context(), msg, "error", err)
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "You are not allowed to manage this invitation"})
+	case errors.Is(err, service.ErrTenderNotInviteOnly):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Tender is not invite-only"})
+	case strings.Contains(err.Error(), "contractor not found"):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Tender or invitation not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to process invitation"})
+	}
+}
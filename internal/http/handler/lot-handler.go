@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zohirovs/internal/config"
+	"github.com/zohirovs/internal/middleware"
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/service"
+)
+
+type LotHandler struct {
+	ser    *service.LotService
+	logger *slog.Logger
+	cfg    *config.Config
+}
+
+func NewLotHandler(logger *slog.Logger, ser *service.LotService, cfg *config.Config) *LotHandler {
+	return &LotHandler{
+		ser:    ser,
+		logger: logger,
+		cfg:    cfg,
+	}
+}
+
+// AwardLot godoc
+// @Summary      Award a lot to a bid
+// @Description  The bid must price the lot. The tender becomes AWARDED once every lot is awarded or cancelled.
+// @Tags         lots
+// @Accept       json
+// @Produce      json
+// @Param        id     path     string          true "Tender ID"
+// @Param        lot_id path     string          true "Lot ID"
+// @Param        award  body     models.AwardLot true "Winning bid"
+// @Success      200 {object} models.Tender
+// @Failure      400 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      409 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/clients/tenders/{id}/lots/{lot_id}/award [post]
+func (h *LotHandler) AwardLot(c *gin.Context) {
+	var req models.AwardLot
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+
+	tender, err := h.ser.AwardLot(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"), c.Param("lot_id"), &req)
+	if err != nil {
+		h.respondError(c, "failed to award lot", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tender)
+}
+
+// CancelLot godoc
+// @Summary      Cancel a lot
+// @Description  Withdraws the lot without awarding it. The tender is CLOSED if every lot is cancelled.
+// @Tags         lots
+// @Produce      json
+// @Param        id     path     string true "Tender ID"
+// @Param        lot_id path     string true "Lot ID"
+// @Success      200 {object} models.Tender
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      409 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/clients/tenders/{id}/lots/{lot_id}/cancel [post]
+func (h *LotHandler) CancelLot(c *gin.Context) {
+	tender, err := h.ser.CancelLot(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"), c.Param("lot_id"))
+	if err != nil {
+		h.respondError(c, "failed to cancel lot", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tender)
+}
+
+func (h *LotHandler) respondError(c *gin.Context, msg string, err error) {
+	h.logger.ErrorContext(c.Request.Context(), msg, "error", err)
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Only the tender owner can decide lots"})
+	case errors.Is(err, service.ErrLotResolved):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: service.ErrLotResolved.Error()})
+	case errors.Is(err, service.ErrInvalidLots):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Tender, lot or bid not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to update lot"})
+	}
+}
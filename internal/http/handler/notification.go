@@ -2,18 +2,69 @@ package handler
 
 import (
 	"log/slog"
+	"net/http"
+	"strings"
 
+	"github.com/gin-gonic/gin"
+	"github.com/zohirovs/internal/config"
+	"github.com/zohirovs/internal/middleware"
 	"github.com/zohirovs/internal/service"
 )
 
 type NotificationHandler struct {
 	logger              *slog.Logger
 	notificationService *service.NotificationService
+	cfg                 *config.Config
 }
 
-func NewNotificationHandler(logger *slog.Logger, notification *service.NotificationService) *NotificationHandler {
+func NewNotificationHandler(logger *slog.Logger, notification *service.NotificationService, cfg *config.Config) *NotificationHandler {
 	return &NotificationHandler{
 		logger:              logger,
 		notificationService: notification,
+		cfg:                 cfg,
 	}
 }
+
+// ListNotifications godoc
+// @Summary      List my notifications
+// @Description  Newest first, at most 100
+// @Tags         notifications
+// @Produce      json
+// @Param        unread query bool false "Only unread notifications"
+// @Success      200 {array}  models.Notification
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/notifications [get]
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	notifications, err := h.notificationService.ListNotifications(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Query("unread") == "true")
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to list notifications", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to list notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, notifications)
+}
+
+// MarkRead godoc
+// @Summary      Mark a notification as read
+// @Tags         notifications
+// @Produce      json
+// @Param        id path string true "Notification ID"
+// @Success      200 {object} SuccessResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/notifications/{id}/read [put]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	err := h.notificationService.MarkRead(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"))
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to mark notification read", "error", err)
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Notification not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to mark notification read"})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{Message: "Notification marked as read"})
+}
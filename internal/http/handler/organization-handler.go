@@ -0,0 +1,317 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zohirovs/internal/config"
+	"github.com/zohirovs/internal/middleware"
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/service"
+)
+
+type OrganizationHandler struct {
+	ser    *service.OrganizationService
+	logger *slog.Logger
+	cfg    *config.Config
+}
+
+func NewOrganizationHandler(logger *slog.Logger, ser *service.OrganizationService, cfg *config.Config) *OrganizationHandler {
+	return &OrganizationHandler{
+		ser:    ser,
+		logger: logger,
+		cfg:    cfg,
+	}
+}
+
+// CreateOrganization godoc
+// @Summary      Create an organization
+// @Description  Clients create client organizations and contractors contractor ones; the creator becomes its owner
+// @Tags         organizations
+// @Accept       json
+// @Produce      json
+// @Param        organization body     models.CreateOrganization true "Organization"
+// @Success      201 {object} models.Organization
+// @Failure      400 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/organizations [post]
+func (h *OrganizationHandler) CreateOrganization(c *gin.Context) {
+	var req models.CreateOrganization
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+
+	org, err := h.ser.CreateOrganization(c.Request.Context(), middleware.GetUserId(c, h.cfg), &req)
+	if err != nil {
+		h.respondError(c, "failed to create organization", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, org)
+}
+
+// ListMyOrganizations godoc
+// @Summary      List the organizations you belong to
+// @Tags         organizations
+// @Produce      json
+// @Success      200 {array}  models.Organization
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/organizations [get]
+func (h *OrganizationHandler) ListMyOrganizations(c *gin.Context) {
+	orgs, err := h.ser.ListMyOrganizations(c.Request.Context(), middleware.GetUserId(c, h.cfg))
+	if err != nil {
+		h.respondError(c, "failed to list organizations", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, orgs)
+}
+
+// GetOrganization godoc
+// @Summary      Get an organization you belong to
+// @Tags         organizations
+// @Produce      json
+// @Param        id path string true "Organization ID"
+// @Success      200 {object} models.Organization
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/organizations/{id} [get]
+func (h *OrganizationHandler) GetOrganization(c *gin.Context) {
+	org, err := h.ser.GetOrganization(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"))
+	if err != nil {
+		h.respondError(c, "failed to get organization", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, org)
+}
+
+// ListMembers godoc
+// @Summary      List an organization's members
+// @Tags         organizations
+// @Produce      json
+// @Param        id path string true "Organization ID"
+// @Success      200 {array}  models.OrganizationMember
+// @Failure      403 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/organizations/{id}/members [get]
+func (h *OrganizationHandler) ListMembers(c *gin.Context) {
+	members, err := h.ser.ListMembers(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"))
+	if err != nil {
+		h.respondError(c, "failed to list members", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+// ChangeMemberRole godoc
+// @Summary      Change a member's role
+// @Description  Owners only. The last owner cannot be demoted.
+// @Tags         organizations
+// @Accept       json
+// @Produce      json
+// @Param        id      path     string                  true "Organization ID"
+// @Param        user_id path     string                  true "Member user ID"
+// @Param        role    body     models.ChangeMemberRole true "New role"
+// @Success      200 {object} SuccessResponse
+// @Failure      400 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      409 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/organizations/{id}/members/{user_id} [put]
+func (h *OrganizationHandler) ChangeMemberRole(c *gin.Context) {
+	var req models.ChangeMemberRole
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+
+	if err := h.ser.ChangeMemberRole(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"), c.Param("user_id"), req.Role); err != nil {
+		h.respondError(c, "failed to change member role", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{Message: "Member role updated successfully"})
+}
+
+// RemoveMember godoc
+// @Summary      Remove a member or leave an organization
+// @Description  Owners can remove anyone; members can remove themselves. The last owner cannot leave.
+// @Tags         organizations
+// @Produce      json
+// @Param        id      path string true "Organization ID"
+// @Param        user_id path string true "Member user ID"
+// @Success      204
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      409 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/organizations/{id}/members/{user_id} [delete]
+func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
+	if err := h.ser.RemoveMember(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"), c.Param("user_id")); err != nil {
+		h.respondError(c, "failed to remove member", err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// InviteMember godoc
+// @Summary      Invite someone to an organization
+// @Description  Owners only. The invite is accepted by the account registered with the email.
+// @Tags         organizations
+// @Accept       json
+// @Produce      json
+// @Param        id     path     string              true "Organization ID"
+// @Param        invite body     models.InviteMember true "Invitee and role"
+// @Success      201 {object} models.MemberInvite
+// @Failure      400 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/organizations/{id}/invitations [post]
+func (h *OrganizationHandler) InviteMember(c *gin.Context) {
+	var req models.InviteMember
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+
+	invite, err := h.ser.InviteMember(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"), &req)
+	if err != nil {
+		h.respondError(c, "failed to invite member", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, invite)
+}
+
+// ListInvites godoc
+// @Summary      List an organization's member invitations
+// @Tags         organizations
+// @Produce      json
+// @Param        id path string true "Organization ID"
+// @Success      200 {array}  models.MemberInvite
+// @Failure      403 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/organizations/{id}/invitations [get]
+func (h *OrganizationHandler) ListInvites(c *gin.Context) {
+	invites, err := h.ser.ListInvites(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"))
+	if err != nil {
+		h.respondError(c, "failed to list invites", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invites)
+}
+
+// ListMyInvites godoc
+// @Summary      List the invitations to join organizations the user has answered
+// @Tags         organizations
+// @Produce      json
+// @Success      200 {array}  models.MemberInvite
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/organizations/invitations [get]
+func (h *OrganizationHandler) ListMyInvites(c *gin.Context) {
+	invites, err := h.ser.ListMyInvites(c.Request.Context(), middleware.GetUserId(c, h.cfg))
+	if err != nil {
+		h.respondError(c, "failed to list invites", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invites)
+}
+
+// RespondInvite godoc
+// @Summary      Accept or decline an invitation to join an organization
+// @Tags         organizations
+// @Accept       json
+// @Produce      json
+// @Param        id       path     string                     true "Invite ID"
+// @Param        response body     models.RespondMemberInvite true "Response"
+// @Success      200 {object} models.MemberInvite
+// @Failure      400 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/organizations/invitations/{id} [put]
+func (h *OrganizationHandler) RespondInvite(c *gin.Context) {
+	var req models.RespondMemberInvite
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+
+	invite, err := h.ser.RespondInvite(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"), &req)
+	if err != nil {
+		h.respondError(c, "failed to respond to invite", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invite)
+}
+
+// ListTenders godoc
+// @Summary      List a client organization's tenders
+// @Tags         organizations
+// @Produce      json
+// @Param        id path string true "Organization ID"
+// @Success      200 {array}  models.Tender
+// @Failure      403 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/organizations/{id}/tenders [get]
+func (h *OrganizationHandler) ListTenders(c *gin.Context) {
+	tenders, err := h.ser.ListTenders(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"))
+	if err != nil {
+		h.respondError(c, "failed to list organization tenders", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tenders)
+}
+
+// ListBids godoc
+// @Summary      List a contractor organization's bids
+// @Tags         organizations
+// @Produce      json
+// @Param        id path string true "Organization ID"
+// @Success      200 {array}  models.Bid
+// @Failure      403 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/organizations/{id}/bids [get]
+func (h *OrganizationHandler) ListBids(c *gin.Context) {
+	bids, err := h.ser.ListBids(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"))
+	if err != nil {
+		h.respondError(c, "failed to list organization bids", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, bids)
+}
+
+func (h *OrganizationHandler) respondError(c *gin.Context, msg string, err error) {
+	h.logger.ErrorContext(c.Request.Context(), msg, "error", err)
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "You are not allowed to do this in the organization"})
+	case errors.Is(err, service.ErrAccountKind):
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrLastOwner):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	case strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Organization, member or invitation not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to process organization request"})
+	}
+}
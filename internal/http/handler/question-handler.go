@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zohirovs/internal/config"
+	"github.com/zohirovs/internal/middleware"
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/service"
+)
+
+type QuestionHandler struct {
+	ser    *service.QuestionService
+	logger *slog.Logger
+	cfg    *config.Config
+}
+
+func NewQuestionHandler(logger *slog.Logger, ser *service.QuestionService, cfg *config.Config) *QuestionHandler {
+	return &QuestionHandler{
+		ser:    ser,
+		logger: logger,
+		cfg:    cfg,
+	}
+}
+
+// AskQuestion godoc
+// @Summary      Ask a clarification question on a tender
+// @Description  Contractors only. Closes at the configured cutoff before the deadline. Anonymous hides the asker from other bidders.
+// @Tags         questions
+// @Accept       json
+// @Produce      json
+// @Param        id       path     string                true "Tender ID"
+// @Param        question body     models.CreateQuestion true "Question"
+// @Success      201 {object} models.Question
+// @Failure      400 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      409 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/contractors/tenders/{id}/questions [post]
+func (h *QuestionHandler) AskQuestion(c *gin.Context) {
+	if middleware.GetUserRole(c, h.cfg) != string(models.Contractor) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Only contractors can ask questions"})
+		return
+	}
+
+	var req models.CreateQuestion
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+
+	question, err := h.ser.AskQuestion(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"), &req)
+	if err != nil {
+		h.respondError(c, "failed to ask question", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, question)
+}
+
+// ListQuestions godoc
+// @Summary      List a tender's questions
+// @Description  The tender's client sees all questions; contractors see their own and published answers
+// @Tags         questions
+// @Produce      json
+// @Param        id path string true "Tender ID"
+// @Success      200 {array}  models.Question
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/tenders/{id}/questions [get]
+func (h *QuestionHandler) ListQuestions(c *gin.Context) {
+	questions, err := h.ser.ListQuestions(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"))
+	if err != nil {
+		h.respondError(c, "failed to list questions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, questions)
+}
+
+// AnswerQuestion godoc
+// @Summary      Answer a question on your tender
+// @Description  Set publish to share the answer with all bidders; a published answer cannot be unpublished
+// @Tags         questions
+// @Accept       json
+// @Produce      json
+// @Param        id          path     string                true "Tender ID"
+// @Param        question_id path     string                true "Question ID"
+// @Param        answer      body     models.AnswerQuestion true "Answer"
+// @Success      200 {object} models.Question
+// @Failure      400 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/clients/tenders/{id}/questions/{question_id}/answer [put]
+func (h *QuestionHandler) AnswerQuestion(c *gin.Context) {
+	var req models.AnswerQuestion
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+
+	question, err := h.ser.AnswerQuestion(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"), c.Param("question_id"), &req)
+	if err != nil {
+		h.respondError(c, "failed to answer question", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, question)
+}
+
+func (h *QuestionHandler) respondError(c *gin.Context, msg string, err error) {
+	h.logger.ErrorContext(c.Request.Context(), msg, "error", err)
+	switch {
+	case errors.Is(err, service.ErrQuestionsClosed):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: "Questions are closed for this tender"})
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Only the tender owner can answer questions"})
+	case errors.Is(err, service.ErrNotInvited), strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Tender or question not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to process question"})
+	}
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zohirovs/internal/config"
+	"github.com/zohirovs/internal/middleware"
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/service"
+)
+
+type QuotaHandler struct {
+	ser    *service.QuotaService
+	logger *slog.Logger
+	cfg    *config.Config
+}
+
+func NewQuotaHandler(logger *slog.Logger, ser *service.QuotaService, cfg *config.Config) *QuotaHandler {
+	return &QuotaHandler{
+		ser:    ser,
+		logger: logger,
+		cfg:    cfg,
+	}
+}
+
+// ListQuotaOverrides godoc
+// @Summary      List quota overrides
+// @Description  Admin only. Users without an override use their role's quota.
+// @Tags         admin
+// @Produce      json
+// @Success      200 {array}  models.QuotaOverride
+// @Failure      403 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/admin/quotas [get]
+func (h *QuotaHandler) ListQuotaOverrides(c *gin.Context) {
+	if middleware.GetUserRole(c, h.cfg) != string(models.Admin) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Admin access required"})
+		return
+	}
+
+	overrides, err := h.ser.ListOverrides(c.Request.Context())
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to list quota overrides", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to list quota overrides"})
+		return
+	}
+
+	c.JSON(http.StatusOK, overrides)
+}
+
+// GetQuota godoc
+// @Summary      Get a user's quota
+// @Description  Admin only. Shows the quota in force and the override behind it, if any.
+// @Tags         admin
+// @Produce      json
+// @Param        user_id path     string true "User ID"
+// @Success      200 {object} models.UserQuota
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/admin/quotas/{user_id} [get]
+func (h *QuotaHandler) GetQuota(c *gin.Context) {
+	if middleware.GetUserRole(c, h.cfg) != string(models.Admin) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Admin access required"})
+		return
+	}
+
+	quota, err := h.ser.GetQuota(c.Request.Context(), c.Param("user_id"))
+	if err != nil {
+		h.respondError(c, "failed to get quota", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quota)
+}
+
+// SetQuotaOverride godoc
+// @Summary      Override a user's quota
+// @Description  Admin only. Replaces the user's override; omitted fields use the role's quota and 0 means unlimited.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        user_id  path     string                  true "User ID"
+// @Param        override body     models.SetQuotaOverride true "Quota override"
+// @Success      200 {object} models.UserQuota
+// @Failure      400 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/admin/quotas/{user_id} [put]
+func (h *QuotaHandler) SetQuotaOverride(c *gin.Context) {
+	if middleware.GetUserRole(c, h.cfg) != string(models.Admin) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Admin access required"})
+		return
+	}
+
+	var req models.SetQuotaOverride
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+
+	quota, err := h.ser.SetOverride(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("user_id"), &req)
+	if err != nil {
+		h.respondError(c, "failed to set quota override", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quota)
+}
+
+// DeleteQuotaOverride godoc
+// @Summary      Remove a user's quota override
+// @Description  Admin only. The user goes back to their role's quota.
+// @Tags         admin
+// @Produce      json
+// @Param        user_id path     string true "User ID"
+// @Success      204
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/admin/quotas/{user_id} [delete]
+func (h *QuotaHandler) DeleteQuotaOverride(c *gin.Context) {
+	if middleware.GetUserRole(c, h.cfg) != string(models.Admin) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Admin access required"})
+		return
+	}
+
+	if err := h.ser.DeleteOverride(c.Request.Context(), c.Param("user_id")); err != nil {
+		h.respondError(c, "failed to delete quota override", err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *QuotaHandler) respondError(c *gin.Context, msg string, err error) {
+	h.logger.ErrorContext(c.Request.Context(), msg, "error", err)
+	switch {
+	case strings.Contains(err.Error(), "not found"), strings.Contains(err.Error(), "invalid user ID"):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "User or quota override not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to process quota"})
+	}
+}
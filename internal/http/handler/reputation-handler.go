@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zohirovs/internal/config"
+	"github.com/zohirovs/internal/middleware"
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/service"
+)
+
+type ReputationHandler struct {
+	ser    *service.ReputationService
+	logger *slog.Logger
+	cfg    *config.Config
+}
+
+func NewReputationHandler(logger *slog.Logger, ser *service.ReputationService, cfg *config.Config) *ReputationHandler {
+	return &ReputationHandler{
+		ser:    ser,
+		logger: logger,
+		cfg:    cfg,
+	}
+}
+
+// ReviewContractor godoc
+// @Summary      Review an awarded contractor
+// @Description  The tender owner rates a contractor whose bid was accepted, once per tender. contractor_id is required when lots were awarded to several contractors.
+// @Tags         reputation
+// @Accept       json
+// @Produce      json
+// @Param        id     path string              true "Tender ID"
+// @Param        review body models.CreateReview true "Review"
+// @Success      201 {object} models.Review
+// @Failure      400 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      409 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/clients/tenders/{id}/review [post]
+func (h *ReputationHandler) ReviewContractor(c *gin.Context) {
+	var req models.CreateReview
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Rating must be between 1 and 5"})
+		return
+	}
+
+	review, err := h.ser.ReviewAwardedContractor(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"), &req)
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to review contractor", "error", err)
+		switch {
+		case errors.Is(err, service.ErrForbidden):
+			c.JSON(http.StatusForbidden, ErrorResponse{Error: "Only the tender owner can review"})
+		case errors.Is(err, service.ErrNotAwarded):
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Tender has not been awarded"})
+		case errors.Is(err, service.ErrWinnerRequired), errors.Is(err, service.ErrNotWinner):
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, service.ErrAlreadyReviewed):
+			c.JSON(http.StatusConflict, ErrorResponse{Error: "Contractor has already been reviewed for this tender"})
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Tender not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to create review"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, review)
+}
+
+// GetReputation godoc
+// @Summary      Get a contractor's reputation
+// @Description  Public aggregate rating, on-time delivery rate and evaluation score
+// @Tags         reputation
+// @Produce      json
+// @Param        id path string true "Contractor user ID"
+// @Success      200 {object} models.Reputation
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/contractors/{id}/reputation [get]
+func (h *ReputationHandler) GetReputation(c *gin.Context) {
+	reputation, err := h.ser.GetReputation(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to get reputation", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to get reputation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, reputation)
+}
+
+// ListReviews godoc
+// @Summary      List a contractor's reviews
+// @Tags         reputation
+// @Produce      json
+// @Param        id path string true "Contractor user ID"
+// @Success      200 {array}  models.Review
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/contractors/{id}/reviews [get]
+func (h *ReputationHandler) ListReviews(c *gin.Context) {
+	reviews, err := h.ser.ListReviews(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to list reviews", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to list reviews"})
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zohirovs/internal/config"
+	"github.com/zohirovs/internal/middleware"
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/service"
+)
+
+type SavedSearchHandler struct {
+	ser    *service.SavedSearchService
+	logger *slog.Logger
+	cfg    *config.Config
+}
+
+func NewSavedSearchHandler(logger *slog.Logger, ser *service.SavedSearchService, cfg *config.Config) *SavedSearchHandler {
+	return &SavedSearchHandler{
+		ser:    ser,
+		logger: logger,
+		cfg:    cfg,
+	}
+}
+
+// CreateSavedSearch godoc
+// @Summary      Save a tender search
+// @Description  Contractors are notified of new tenders matching the search, optionally by email digest
+// @Tags         saved-searches
+// @Accept       json
+// @Produce      json
+// @Param        search body     models.CreateSavedSearch true "Search criteria"
+// @Success      201 {object} models.SavedSearch
+// @Failure      400 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/contractors/searches [post]
+func (h *SavedSearchHandler) CreateSavedSearch(c *gin.Context) {
+	if middleware.GetUserRole(c, h.cfg) != string(models.Contractor) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Only contractors can save searches"})
+		return
+	}
+
+	var req models.CreateSavedSearch
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+
+	search, err := h.ser.CreateSavedSearch(c.Request.Context(), middleware.GetUserId(c, h.cfg), &req)
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to create saved search", "error", err)
+		if strings.Contains(err.Error(), "must not exceed") {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "min_budget must not exceed max_budget"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to create saved search"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, search)
+}
+
+// ListSavedSearches godoc
+// @Summary      List my saved searches
+// @Tags         saved-searches
+// @Produce      json
+// @Success      200 {array}  models.SavedSearch
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/contractors/searches [get]
+func (h *SavedSearchHandler) ListSavedSearches(c *gin.Context) {
+	searches, err := h.ser.ListSavedSearches(c.Request.Context(), middleware.GetUserId(c, h.cfg))
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to list saved searches", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to list saved searches"})
+		return
+	}
+
+	c.JSON(http.StatusOK, searches)
+}
+
+// DeleteSavedSearch godoc
+// @Summary      Delete a saved search
+// @Tags         saved-searches
+// @Produce      json
+// @Param        id path string true "Saved search ID"
+// @Success      204
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/contractors/searches/{id} [delete]
+func (h *SavedSearchHandler) DeleteSavedSearch(c *gin.Context) {
+	err := h.ser.DeleteSavedSearch(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"))
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to delete saved search", "error", err)
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Saved search not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to delete saved search"})
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
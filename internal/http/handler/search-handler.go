@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zohirovs/internal/config"
+	"github.com/zohirovs/internal/middleware"
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/service"
+)
+
+type SearchHandler struct {
+	ser    *service.SearchService
+	logger *slog.Logger
+	cfg    *config.Config
+}
+
+func NewSearchHandler(logger *slog.Logger, ser *service.SearchService, cfg *config.Config) *SearchHandler {
+	return &SearchHandler{
+		ser:    ser,
+		logger: logger,
+		cfg:    cfg,
+	}
+}
+
+// SearchTenders godoc
+// @Summary      Full-text tender search
+// @Description  Searches tender titles and descriptions, ranked by relevance (title matches weigh more). Returns highlighted snippets and facet counts by status, category and budget band. Invite-only tenders appear only for their client and invitees.
+// @Tags         tenders
+// @Produce      json
+// @Param        q           query string true  "Search terms; quote phrases, prefix with - to exclude"
+// @Param        status      query string false "Filter by status"
+// @Param        category_id query string false "Filter by category, including subcategories"
+// @Param        min_budget  query int    false "Minimum budget"
+// @Param        max_budget  query int    false "Maximum budget"
+// @Param        language    query string false "Stemming language, e.g. english or russian"
+// @Param        limit       query int    false "Page size (default 20, max 100)"
+// @Param        offset      query int    false "Results to skip"
+// @Success      200 {object} models.TenderSearchResult
+// @Failure      400 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/tenders/search [get]
+func (h *SearchHandler) SearchTenders(c *gin.Context) {
+	var query models.TenderSearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to bind query", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid search parameters"})
+		return
+	}
+
+	query.ViewerId = middleware.GetUserId(c, h.cfg)
+
+	result, err := h.ser.SearchTenders(c.Request.Context(), &query)
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to search tenders", "error", err)
+		switch {
+		case strings.Contains(err.Error(), "must not exceed"):
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "min_budget must not exceed max_budget"})
+		case strings.Contains(err.Error(), "language"):
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Unsupported search language"})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to search tenders"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,241 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zohirovs/internal/config"
+	"github.com/zohirovs/internal/middleware"
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/service"
+)
+
+type TemplateHandler struct {
+	ser    *service.TemplateService
+	logger *slog.Logger
+	cfg    *config.Config
+}
+
+func NewTemplateHandler(logger *slog.Logger, ser *service.TemplateService, cfg *config.Config) *TemplateHandler {
+	return &TemplateHandler{
+		ser:    ser,
+		logger: logger,
+		cfg:    cfg,
+	}
+}
+
+// CreateTemplate godoc
+// @Summary      Save a tender template
+// @Tags         templates
+// @Accept       json
+// @Produce      json
+// @Param        template body     models.SaveTemplate true "Template"
+// @Success      201 {object} models.TenderTemplate
+// @Failure      400 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/clients/templates [post]
+func (h *TemplateHandler) CreateTemplate(c *gin.Context) {
+	req, ok := h.bindTemplate(c)
+	if !ok {
+		return
+	}
+
+	template, err := h.ser.CreateTemplate(c.Request.Context(), middleware.GetUserId(c, h.cfg), req)
+	if err != nil {
+		h.respondError(c, "failed to create template", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// ListTemplates godoc
+// @Summary      List my tender templates
+// @Tags         templates
+// @Produce      json
+// @Success      200 {array}  models.TenderTemplate
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/clients/templates [get]
+func (h *TemplateHandler) ListTemplates(c *gin.Context) {
+	templates, err := h.ser.ListTemplates(c.Request.Context(), middleware.GetUserId(c, h.cfg))
+	if err != nil {
+		h.respondError(c, "failed to list templates", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// GetTemplate godoc
+// @Summary      Get one of my tender templates
+// @Tags         templates
+// @Produce      json
+// @Param        id path string true "Template ID"
+// @Success      200 {object} models.TenderTemplate
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/clients/templates/{id} [get]
+func (h *TemplateHandler) GetTemplate(c *gin.Context) {
+	template, err := h.ser.GetTemplate(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"))
+	if err != nil {
+		h.respondError(c, "failed to get template", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// UpdateTemplate godoc
+// @Summary      Replace one of my tender templates
+// @Tags         templates
+// @Accept       json
+// @Produce      json
+// @Param        id       path     string              true "Template ID"
+// @Param        template body     models.SaveTemplate true "Template"
+// @Success      200 {object} models.TenderTemplate
+// @Failure      400 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/clients/templates/{id} [put]
+func (h *TemplateHandler) UpdateTemplate(c *gin.Context) {
+	req, ok := h.bindTemplate(c)
+	if !ok {
+		return
+	}
+
+	template, err := h.ser.UpdateTemplate(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"), req)
+	if err != nil {
+		h.respondError(c, "failed to update template", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// DeleteTemplate godoc
+// @Summary      Delete one of my tender templates
+// @Tags         templates
+// @Produce      json
+// @Param        id path string true "Template ID"
+// @Success      204
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/clients/templates/{id} [delete]
+func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
+	if err := h.ser.DeleteTemplate(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id")); err != nil {
+		h.respondError(c, "failed to delete template", err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// CreateFromTemplate godoc
+// @Summary      Create a draft tender from a template
+// @Description  The draft is published by setting its status to OPEN. Budget defaults to the template's.
+// @Tags         templates
+// @Accept       json
+// @Produce      json
+// @Param        id    path     string          true "Template ID"
+// @Param        draft body     models.NewDraft true "Deadline and budget"
+// @Success      201 {object} models.Tender
+// @Failure      400 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/clients/templates/{id}/tenders [post]
+func (h *TemplateHandler) CreateFromTemplate(c *gin.Context) {
+	var req models.NewDraft
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+
+	deadline, err := models.ParseDeadline(req.Deadline, req.TimeZone)
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "invalid deadline", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid deadline or time zone"})
+		return
+	}
+
+	tender, err := h.ser.CreateFromTemplate(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"), deadline, req.TimeZone, req.Budget)
+	if err != nil {
+		h.respondError(c, "failed to create tender from template", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, tender)
+}
+
+// CloneTender godoc
+// @Summary      Clone one of my tenders into a new draft
+// @Description  Copies everything except bids, awards, invitations and attachments. Lot deadlines move with the tender deadline.
+// @Tags         templates
+// @Accept       json
+// @Produce      json
+// @Param        id    path     string          true "Tender ID"
+// @Param        draft body     models.NewDraft true "New deadline, optional time zone and budget"
+// @Success      201 {object} models.Tender
+// @Failure      400 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/clients/tenders/{id}/clone [post]
+func (h *TemplateHandler) CloneTender(c *gin.Context) {
+	var req models.NewDraft
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+
+	deadline, err := models.ParseDeadline(req.Deadline, req.TimeZone)
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "invalid deadline", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid deadline or time zone"})
+		return
+	}
+
+	tender, err := h.ser.CloneTender(c.Request.Context(), middleware.GetUserId(c, h.cfg), c.Param("id"), deadline, req.TimeZone, req.Budget)
+	if err != nil {
+		h.respondError(c, "failed to clone tender", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, tender)
+}
+
+func (h *TemplateHandler) bindTemplate(c *gin.Context) (*models.SaveTemplate, bool) {
+	var req models.SaveTemplate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to bind JSON", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return nil, false
+	}
+	if err := models.ValidateCriteria(req.EvaluationCriteria); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return nil, false
+	}
+	return &req, true
+}
+
+func (h *TemplateHandler) respondError(c *gin.Context, msg string, err error) {
+	h.logger.ErrorContext(c.Request.Context(), msg, "error", err)
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Only the tender owner can clone it"})
+	case errors.Is(err, service.ErrBudgetRequired):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Budget is required"})
+	case strings.Contains(err.Error(), "deadline must be in the future"):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Deadline must be in the future"})
+	case strings.Contains(err.Error(), "category not found"):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Unknown category"})
+	case strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Template or tender not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to process template"})
+	}
+}
@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 
 	"log/slog"
 
@@ -45,29 +47,89 @@ type SuccessResponse struct {
 // @Param        tender body     models.CreateTender true "Tender object"
 // @Success      201 {object} models.Tender
 // @Failure      400 {object} ErrorResponse
+// @Failure      403 {object} ErrorResponse
 // @Failure      500 {object} ErrorResponse
 // @Router       /api/clients/tenders [post]
 func (h *TenderHandler) CreateTender(c *gin.Context) {
 	var createTender models.CreateTender
 
 	if err := c.ShouldBindJSON(&createTender); err != nil {
-		h.logger.Error("failed to bind JSON", "error", err)
+		h.logger.ErrorContext(c.Request.Context(), "failed to bind JSON", "error", err)
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request"})
 		return
 	}
 
+	deadline, err := models.ParseDeadline(createTender.Deadline, createTender.TimeZone)
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "invalid deadline", "error", err)
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid deadline or time zone"})
+		return
+	}
+
 	tender := models.Tender{
 		ClientId:      middleware.GetUserId(c, h.cfg),
 		Title:         createTender.Title,
 		Description:   createTender.Description,
-		Deadline:      createTender.Deadline,
+		Deadline:      deadline,
+		TimeZone:      createTender.TimeZone,
 		Budget:        createTender.Budget,
 		AttachmentUrl: createTender.AttachmentUrl,
+		CategoryId:    createTender.CategoryId,
+		Tags:          createTender.Tags,
+
+		RequiresVerified:       createTender.RequiresVerified,
+		RequiredCertifications: createTender.RequiredCertifications,
+		Visibility:             createTender.Visibility,
+		EvaluationCriteria:     createTender.EvaluationCriteria,
+
+		OrganizationId: createTender.OrganizationId,
+	}
+
+	tender.LineItems = models.NewLineItems(createTender.LineItems)
+	for _, lot := range createTender.Lots {
+		l := models.Lot{
+			Title:    lot.Title,
+			Quantity: lot.Quantity,
+			Unit:     lot.Unit,
+			Budget:   lot.Budget,
+
+			LineItems: models.NewLineItems(lot.LineItems),
+		}
+		if lot.Deadline != "" {
+			lotDeadline, err := models.ParseDeadline(lot.Deadline, createTender.TimeZone)
+			if err != nil {
+				h.logger.ErrorContext(c.Request.Context(), "invalid lot deadline", "error", err)
+				c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid lot deadline"})
+				return
+			}
+			l.Deadline = &lotDeadline
+		}
+		tender.Lots = append(tender.Lots, l)
+	}
+	if err := tender.ValidateLots(); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err := models.ValidateCriteria(tender.EvaluationCriteria); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
 	}
 
 	createdTender, err := h.ser.CreateTender(c.Request.Context(), &tender)
 	if err != nil {
-		h.logger.Error("failed to create tender", "error", err)
+		h.logger.ErrorContext(c.Request.Context(), "failed to create tender", "error", err)
+		if strings.Contains(err.Error(), "category not found") {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Unknown category"})
+			return
+		}
+		if strings.Contains(err.Error(), "organization not found") {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Unknown organization"})
+			return
+		}
+		if errors.Is(err, service.ErrForbidden) || errors.Is(err, service.ErrAccountKind) {
+			c.JSON(http.StatusForbidden, ErrorResponse{Error: "You cannot create tenders for this organization"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to create tender"})
 		return
 	}
@@ -89,9 +151,14 @@ func (h *TenderHandler) CreateTender(c *gin.Context) {
 func (h *TenderHandler) GetTender(c *gin.Context) {
 	id := c.Param("id")
 
-	tender, err := h.ser.GetTender(c.Request.Context(), id)
+	tender, err := h.ser.GetTenderForUser(c.Request.Context(), middleware.GetUserId(c, h.cfg), id)
 	if err != nil {
-		h.logger.Error("failed to get tender", "error", err)
+		h.logger.ErrorContext(c.Request.Context(), "failed to get tender", "error", err)
+		// Invite-only tenders are reported as missing to non-invitees.
+		if errors.Is(err, service.ErrNotInvited) || strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Tender not found"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to get tender"})
 		return
 	}
@@ -118,7 +185,9 @@ type StatusUpdateRequest struct {
 // @Param        status  body      models.Status      true "Tender Status"
 // @Success      200     {object}  SuccessResponse
 // @Failure      400     {object}  ErrorResponse
+// @Failure      403     {object}  ErrorResponse
 // @Failure      404     {object}  ErrorResponse
+// @Failure      409     {object}  ErrorResponse
 // @Failure      500     {object}  ErrorResponse
 // @Router       /api/clients/tenders/{id}/status [put]
 func (h *TenderHandler) UpdateTenderStatus(c *gin.Context) {
@@ -126,7 +195,7 @@ func (h *TenderHandler) UpdateTenderStatus(c *gin.Context) {
 
 	var req StatusUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Error("failed to bind JSON", "error", err)
+		h.logger.ErrorContext(c.Request.Context(), "failed to bind JSON", "error", err)
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
 		return
 	}
@@ -137,15 +206,19 @@ func (h *TenderHandler) UpdateTenderStatus(c *gin.Context) {
 		models.AWARDED: true,
 	}
 	if !validStatuses[req.Status] {
-		h.logger.Error("invalid status provided", "status", req.Status)
+		h.logger.ErrorContext(c.Request.Context(), "invalid status provided", "status", req.Status)
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid status value"})
 		return
 	}
 
 	// Call the service to update the tender status
-	if err := h.ser.UpdateTenderStatus(c.Request.Context(), id, req.Status); err != nil {
-		h.logger.Error("failed to update tender status", "error", err)
-		if err.Error() == "not found" {
+	if err := h.ser.UpdateTenderStatus(c.Request.Context(), middleware.GetUserId(c, h.cfg), id, req.Status); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to update tender status", "error", err)
+		if errors.Is(err, service.ErrLotsUnresolved) || errors.Is(err, service.ErrDeadlinePassed) {
+			c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+		} else if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, ErrorResponse{Error: "You cannot manage this tender"})
+		} else if strings.Contains(err.Error(), "not found") {
 			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Tender not found"})
 		} else {
 			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to update tender status"})
@@ -165,18 +238,56 @@ func (h *TenderHandler) UpdateTenderStatus(c *gin.Context) {
 // @Produce      json
 // @Param        id path     string true "Tender ID"
 // @Success      204
+// @Failure      403 {object} ErrorResponse
 // @Failure      404 {object} ErrorResponse
 // @Failure      500 {object} ErrorResponse
 // @Router       /api/clients/tenders/{id} [delete]
 func (h *TenderHandler) DeleteTender(c *gin.Context) {
 	id := c.Param("id")
 
-	err := h.ser.DeleteTender(c.Request.Context(), id)
+	err := h.ser.DeleteTender(c.Request.Context(), middleware.GetUserId(c, h.cfg), id)
 	if err != nil {
-		h.logger.Error("failed to delete tender", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to delete tender"})
+		h.logger.ErrorContext(c.Request.Context(), "failed to delete tender", "error", err)
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, ErrorResponse{Error: "You cannot manage this tender"})
+		} else if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Tender not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to delete tender"})
+		}
 		return
 	}
 
 	c.JSON(http.StatusNoContent, nil)
 }
+
+// RestoreTender godoc
+// @Summary      Restore a deleted tender
+// @Description  Admin only. Brings back a soft-deleted tender together with the bids deleted with it.
+// @Tags         admin
+// @Produce      json
+// @Param        id path     string true "Tender ID"
+// @Success      200 {object} models.Tender
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/admin/tenders/{id}/restore [post]
+func (h *TenderHandler) RestoreTender(c *gin.Context) {
+	if middleware.GetUserRole(c, h.cfg) != string(models.Admin) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Admin access required"})
+		return
+	}
+
+	tender, err := h.ser.RestoreTender(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to restore tender", "error", err)
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Deleted tender not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to restore tender"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, tender)
+}
@@ -1,12 +1,16 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"regexp"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/zohirovs/internal/config"
+	"github.com/zohirovs/internal/middleware"
 	"github.com/zohirovs/internal/models"
 	"github.com/zohirovs/internal/service"
 	"golang.org/x/crypto/bcrypt"
@@ -15,12 +19,14 @@ import (
 type UserHandler struct {
 	logger      *slog.Logger
 	userService *service.UserService
+	cfg         *config.Config
 }
 
-func NewUserHandler(logger *slog.Logger, user *service.UserService) *UserHandler {
+func NewUserHandler(logger *slog.Logger, user *service.UserService, cfg *config.Config) *UserHandler {
 	return &UserHandler{
 		logger:      logger,
 		userService: user,
+		cfg:         cfg,
 	}
 }
 
@@ -33,51 +39,53 @@ func NewUserHandler(logger *slog.Logger, user *service.UserService) *UserHandler
 // @Param        user body     models.RegisterUser true "User registration details"
 // @Success      201 {object} gin.H               "Successfully registered user"
 // @Failure      400 {object} gin.H               "Invalid request"
+// @Failure      409 {object} gin.H               "Email or username already exists"
+// @Failure      429 {object} gin.H               "Too many attempts; see Retry-After"
 // @Failure      500 {object} gin.H               "Internal server error"
 // @Router       /register [post]
 func (h *UserHandler) RegisterUser(c *gin.Context) {
-	h.logger.Info("Register user")
+	h.logger.InfoContext(c.Request.Context(), "Register user")
 
 	var user models.RegisterUser
 	if err := c.ShouldBindJSON(&user); err != nil {
-		h.logger.Error("failed to bind JSON", "error", err)
+		h.logger.ErrorContext(c.Request.Context(), "failed to bind JSON", "error", err)
 		c.JSON(401, gin.H{"error": "Invalid request"})
 		return
 	}
 
 	if user.Role != "client" && user.Role != "contractor" {
-		h.logger.Error("invalid role")
+		h.logger.ErrorContext(c.Request.Context(), "invalid role")
 		c.JSON(400, gin.H{"message": "invalid role"})
 		return
 	}
 
 	if user.Email == "" || user.Username == "" {
-		h.logger.Error("username or email cannot be empty")
+		h.logger.ErrorContext(c.Request.Context(), "username or email cannot be empty")
 		c.JSON(400, gin.H{"message": "username or email cannot be empty"})
 		return
 	}
 
 	isValid := h.isValidEmail(user.Email)
 	if isValid == false {
-		h.logger.Error("invalid email format")
+		h.logger.ErrorContext(c.Request.Context(), "invalid email format")
 		c.JSON(400, gin.H{"message": "invalid email format"})
 		return
 	}
 
 	token, err := h.userService.RegisterUser(c.Request.Context(), &user)
-	if token == "Duplicate" {
-		c.JSON(400, gin.H{"message": "Email already exists"})
+	if errors.Is(err, service.ErrUserExists) {
+		c.JSON(http.StatusConflict, gin.H{"message": "Email or username already exists"})
 		return
 	}
 
 	if err != nil {
-		h.logger.Error("failed to register user", "error", err)
+		h.logger.ErrorContext(c.Request.Context(), "failed to register user", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to register user"})
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{"token": token})
-	h.logger.Info("User registered successfully", "email", user.Email)
+	h.logger.InfoContext(c.Request.Context(), "User registered successfully", "email", user.Email)
 }
 
 // LoginUser godoc
@@ -89,39 +97,107 @@ func (h *UserHandler) RegisterUser(c *gin.Context) {
 // @Param        user body     models.LoginRequest true "User login credentials"
 // @Success      200 {object} gin.H               "Successfully logged in with token"
 // @Failure      400 {object} gin.H               "Invalid request"
+// @Failure      401 {object} gin.H               "Invalid username or password"
+// @Failure      429 {object} gin.H               "Too many attempts; see Retry-After"
 // @Failure      500 {object} gin.H               "Internal server error"
+// @Failure      503 {object} gin.H               "Redis unavailable"
 // @Router       /login [post]
 func (h *UserHandler) LoginUser(c *gin.Context) {
-	h.logger.Info("Login user")
+	h.logger.InfoContext(c.Request.Context(), "Login user")
 
 	var user models.LoginRequest
 	if err := c.ShouldBindJSON(&user); err != nil {
-		h.logger.Error("failed to bind JSON", "error", err)
+		h.logger.ErrorContext(c.Request.Context(), "failed to bind JSON", "error", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
 		return
 	}
 
 	if user.Username == "" || user.Password == "" {
-		h.logger.Error("username or password cannot be empty")
+		h.logger.ErrorContext(c.Request.Context(), "username or password cannot be empty")
 		c.JSON(400, gin.H{"message": "Username and password are required"})
 		return
 	}
 
 	token, err := h.userService.Login(c.Request.Context(), &user)
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to login user", "error", err)
+		var locked *service.LoginLockedError
+		switch {
+		case errors.As(err, &locked):
+			middleware.SetRetryAfter(c, locked.RetryAfter)
+			c.JSON(http.StatusTooManyRequests, gin.H{"message": "Too many failed login attempts, try again later"})
+		case errors.Is(err, service.ErrInvalidCredentials):
+			c.JSON(401, gin.H{"message": "Invalid username or password"})
+		case errors.Is(err, service.ErrUnavailable):
+			c.JSON(http.StatusServiceUnavailable, gin.H{"message": "Login is temporarily unavailable"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to login"})
+		}
+		return
+	}
 
-	if token == "not found" {
-		c.JSON(404, gin.H{"message": "User not found"})
+	c.JSON(200, gin.H{"token": token})
+	h.logger.InfoContext(c.Request.Context(), "User logged in successfully", "username", user.Username)
+}
+
+// DeleteUser godoc
+// @Summary      Delete a user
+// @Description  Admin only. Soft-deletes the account; it can be restored until the retention period ends.
+// @Tags         admin
+// @Produce      json
+// @Param        id path     string true "User ID"
+// @Success      204
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/admin/users/{id} [delete]
+func (h *UserHandler) DeleteUser(c *gin.Context) {
+	if middleware.GetUserRole(c, h.cfg) != string(models.Admin) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Admin access required"})
 		return
 	}
 
-	if err != nil {
-		h.logger.Error("failed to login user", "error", err)
-		c.JSON(401, gin.H{"message": "Invalid username or password"})
+	if err := h.userService.DeleteUser(c.Request.Context(), c.Param("id")); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to delete user", "error", err)
+		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "invalid user ID") {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to delete user"})
+		}
 		return
 	}
 
-	c.JSON(200, gin.H{"token": token})
-	h.logger.Info("User logged in successfully", "username", user.Username)
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// RestoreUser godoc
+// @Summary      Restore a deleted user
+// @Description  Admin only.
+// @Tags         admin
+// @Produce      json
+// @Param        id path     string true "User ID"
+// @Success      200 {object} SuccessResponse
+// @Failure      403 {object} ErrorResponse
+// @Failure      404 {object} ErrorResponse
+// @Failure      500 {object} ErrorResponse
+// @Router       /api/admin/users/{id}/restore [post]
+func (h *UserHandler) RestoreUser(c *gin.Context) {
+	if middleware.GetUserRole(c, h.cfg) != string(models.Admin) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Admin access required"})
+		return
+	}
+
+	if err := h.userService.RestoreUser(c.Request.Context(), c.Param("id")); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to restore user", "error", err)
+		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "invalid user ID") {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Deleted user not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to restore user"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{Message: "User restored successfully"})
 }
 
 func (h *UserHandler) isValidEmail(email string) bool {
@@ -1,11 +1,15 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+	"github.com/zohirovs/internal/middleware"
+	"github.com/zohirovs/internal/service"
 	ws "github.com/zohirovs/internal/ws"
 )
 
@@ -17,6 +21,8 @@ var upgrader = websocket.Upgrader{
 	},
 }
 
+// HandleWebSocket joins the tender's room. Only users who may see the
+// tender can join, so Q&A of drafts and invite-only tenders stays private.
 func (h *Handler) HandleWebSocket(c *gin.Context) {
 	tenderID := c.Query("tender_id")
 	if tenderID == "" {
@@ -24,6 +30,23 @@ func (h *Handler) HandleWebSocket(c *gin.Context) {
 		return
 	}
 
+	// Browsers cannot set headers on the handshake, so the token may also
+	// be passed as ?token=.
+	if c.GetHeader("Authorization") == "" && c.Query("token") != "" {
+		c.Request.Header.Set("Authorization", c.Query("token"))
+	}
+	userID := middleware.GetUserId(c, h.cfg)
+	if _, err := h.tenders.GetTenderForUser(c.Request.Context(), userID, tenderID); err != nil {
+		h.logger.WarnContext(c.Request.Context(), "websocket join refused", "error", err, "tender_id", tenderID)
+		// Invite-only tenders are reported as missing to non-invitees.
+		if errors.Is(err, service.ErrNotInvited) || strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Tender not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get tender"})
+		return
+	}
+
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
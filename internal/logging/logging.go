@@ -0,0 +1,42 @@
+// Package logging builds the application's slog logger: JSON records with
+// secrets and personal data redacted, written to stdout or a rotated file,
+// carrying the request attributes and trace IDs found in the context.
+package logging
+
+import (
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/zohirovs/internal/config"
+	"github.com/zohirovs/internal/tracing"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// New returns the logger described by cfg and the output to close on
+// shutdown. Request attributes and trace IDs only reach records logged
+// through the logger's *Context methods.
+func New(cfg config.LogConfig) (*slog.Logger, io.Closer) {
+	var out io.WriteCloser = nopCloser{os.Stdout}
+	if cfg.Output == "file" {
+		out = &lumberjack.Logger{
+			Filename:   cfg.File,
+			MaxSize:    cfg.MaxSizeMB,
+			MaxBackups: cfg.MaxBackups,
+			MaxAge:     cfg.MaxAgeDays,
+		}
+	}
+
+	var handler slog.Handler = slog.NewJSONHandler(out, &slog.HandlerOptions{Level: cfg.Level})
+	handler = RedactHandler(handler)
+	handler = contextHandler{next: handler}
+	handler = tracing.LogHandler(handler)
+
+	return slog.New(handler), out
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
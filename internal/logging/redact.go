@@ -0,0 +1,105 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+var (
+	// sensitiveKeys are attribute key fragments whose values are dropped
+	// whatever they contain.
+	sensitiveKeys = []string{"password", "passwd", "token", "secret", "authorization", "api_key", "apikey", "signature"}
+
+	emailPattern  = regexp.MustCompile(`([A-Za-z0-9._%+\-])[A-Za-z0-9._%+\-]*@([A-Za-z0-9.\-]+\.[A-Za-z]{2,})`)
+	jwtPattern    = regexp.MustCompile(`eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]*`)
+	bearerPattern = regexp.MustCompile(`(?i)(bearer\s+)\S+`)
+)
+
+// RedactHandler masks personal data and credentials before next sees a
+// record: values under sensitive keys such as password or token are
+// replaced, and emails, JWTs and bearer tokens are masked wherever they
+// appear in the message, string values and errors.
+func RedactHandler(next slog.Handler) slog.Handler {
+	return redactHandler{next: next}
+}
+
+type redactHandler struct {
+	next slog.Handler
+}
+
+func (h redactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h redactHandler) Handle(ctx context.Context, record slog.Record) error {
+	clean := slog.NewRecord(record.Time, record.Level, redactString(record.Message), record.PC)
+	record.Attrs(func(a slog.Attr) bool {
+		clean.AddAttrs(redactAttr(a))
+		return true
+	})
+	return h.next.Handle(ctx, clean)
+}
+
+func (h redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clean := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		clean[i] = redactAttr(a)
+	}
+	return redactHandler{next: h.next.WithAttrs(clean)}
+}
+
+func (h redactHandler) WithGroup(name string) slog.Handler {
+	return redactHandler{next: h.next.WithGroup(name)}
+}
+
+func redactAttr(a slog.Attr) slog.Attr {
+	if isSensitiveKey(a.Key) {
+		return slog.String(a.Key, redacted)
+	}
+
+	value := a.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, redactString(value.String()))
+	case slog.KindGroup:
+		group := value.Group()
+		clean := make([]any, len(group))
+		for i, member := range group {
+			clean[i] = redactAttr(member)
+		}
+		return slog.Group(a.Key, clean...)
+	case slog.KindAny:
+		// Errors often quote the input that caused them.
+		if err, ok := value.Any().(error); ok {
+			if msg := err.Error(); redactString(msg) != msg {
+				return slog.String(a.Key, redactString(msg))
+			}
+		}
+	}
+	return slog.Attr{Key: a.Key, Value: value}
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, fragment := range sensitiveKeys {
+		if strings.Contains(key, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactString keeps the first character and domain of emails, so log lines
+// about the same address can still be told apart, and drops tokens.
+func redactString(s string) string {
+	if !strings.ContainsAny(s, "@.") && !strings.Contains(strings.ToLower(s), "bearer") {
+		return s
+	}
+	s = jwtPattern.ReplaceAllString(s, redacted)
+	s = bearerPattern.ReplaceAllString(s, "${1}"+redacted)
+	return emailPattern.ReplaceAllString(s, "${1}***@${2}")
+}
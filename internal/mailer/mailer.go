@@ -0,0 +1,26 @@
+// Package mailer sends plain-text email through the configured SMTP server.
+package mailer
+
+import (
+	"github.com/zohirovs/internal/config"
+	"gopkg.in/gomail.v2"
+)
+
+type Mailer struct {
+	cfg config.EmailConfig
+}
+
+func New(cfg config.EmailConfig) *Mailer {
+	return &Mailer{cfg: cfg}
+}
+
+func (m *Mailer) Send(to, subject, body string) error {
+	msg := gomail.NewMessage()
+	msg.SetHeader("From", m.cfg.SmtpUser)
+	msg.SetHeader("To", to)
+	msg.SetHeader("Subject", subject)
+	msg.SetBody("text/plain", body)
+
+	d := gomail.NewDialer(m.cfg.SmtpHost, m.cfg.SmtpPort, m.cfg.SmtpUser, m.cfg.SmtpPass)
+	return d.DialAndSend(msg)
+}
@@ -0,0 +1,110 @@
+// Package metrics defines the Prometheus metrics served at /metrics.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "tender"
+
+var (
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by route template and status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route template and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	MongoDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "mongodb_operation_duration_seconds",
+		Help:      "MongoDB command latency by collection, command and outcome.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"collection", "command", "outcome"})
+
+	// CacheRequests counts cache lookups; result is hit, miss or error.
+	CacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_requests_total",
+		Help:      "Redis cache lookups by cache and result.",
+	}, []string{"cache", "result"})
+
+	// BidCacheEvents counts bid cache work other than lookups.
+	BidCacheEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "bid_cache_events_total",
+		Help:      "Bid cache invalidations, coalesced loads, skipped stale writes and errors outside lookups.",
+	}, []string{"event"})
+
+	TendersCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tenders_created_total",
+		Help:      "Tenders created.",
+	})
+
+	BidsSubmitted = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "bids_submitted_total",
+		Help:      "Bids submitted.",
+	})
+
+	TendersAwarded = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tenders_awarded_total",
+		Help:      "Tenders moved to awarded.",
+	})
+)
+
+// Cache lookup results.
+const (
+	Hit   = "hit"
+	Miss  = "miss"
+	Error = "error"
+)
+
+// Bid cache events besides Error.
+const (
+	Invalidation = "invalidation"
+	Coalesced    = "coalesced"
+	StaleSet     = "stale_set"
+)
+
+// WebSocketStats reports live WebSocket connections.
+type WebSocketStats interface {
+	Stats() (clients, rooms int)
+}
+
+// RegisterWebSocket exposes gauges read from ws on every scrape.
+func RegisterWebSocket(ws WebSocketStats) {
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "websocket_connections",
+		Help:      "Open WebSocket connections.",
+	}, func() float64 {
+		clients, _ := ws.Stats()
+		return float64(clients)
+	})
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "websocket_rooms",
+		Help:      "Tenders with at least one WebSocket subscriber.",
+	}, func() float64 {
+		_, rooms := ws.Stats()
+		return float64(rooms)
+	})
+}
+
+// Handler serves every registered metric in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
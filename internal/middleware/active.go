@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zohirovs/internal/config"
+)
+
+// ActiveUsers reports whether a user still exists and has not been deleted.
+type ActiveUsers interface {
+	IsActive(ctx context.Context, userID string) (bool, error)
+}
+
+// ActiveUser rejects requests whose token belongs to a user deleted after the
+// token was issued. Requests without a valid token are left to the routes'
+// own authorization.
+func ActiveUser(users ActiveUsers, cfg *config.Config, logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := GetUserId(c, cfg)
+		if userID == "" {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		active, err := users.IsActive(ctx, userID)
+		if err != nil {
+			logger.ErrorContext(ctx, "failed to check user", "error", err, "user_id", userID)
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "Service unavailable"})
+			return
+		}
+		if !active {
+			logger.WarnContext(ctx, "token of deleted user rejected", "user_id", userID)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Account no longer exists"})
+			return
+		}
+		c.Next()
+	}
+}
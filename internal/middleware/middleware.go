@@ -144,6 +144,10 @@ func GetUserEmail(c *gin.Context, config *config.Config) string {
 	return username
 }
 
+func GetUserRole(c *gin.Context, config *config.Config) string {
+	return getRole(c, config)
+}
+
 func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zohirovs/internal/config"
+	"github.com/zohirovs/internal/models"
+)
+
+// QuotaResolver returns the quota in force for a user.
+type QuotaResolver interface {
+	QuotaFor(ctx context.Context, userID, role string) (models.Quota, error)
+}
+
+// QuotaRule counts one of a user's quotas.
+type QuotaRule struct {
+	Name   string
+	Window time.Duration
+	Limit  func(q models.Quota) int
+	// Scope, if set, counts separately per value, e.g. per tender; requests
+	// it returns "" for are not counted.
+	Scope func(c *gin.Context) string
+}
+
+var (
+	RequestsPerMinute = QuotaRule{
+		Name:   "requests",
+		Window: time.Minute,
+		Limit:  func(q models.Quota) int { return q.RequestsPerMinute },
+	}
+	TendersPerDay = QuotaRule{
+		Name:   "tenders",
+		Window: 24 * time.Hour,
+		Limit:  func(q models.Quota) int { return q.TendersPerDay },
+	}
+	BidsPerTenderPerHour = QuotaRule{
+		Name:   "bids",
+		Window: time.Hour,
+		Limit:  func(q models.Quota) int { return q.BidsPerTenderPerHour },
+		Scope:  BodyField("tender_id"),
+	}
+)
+
+// Quota enforces per-user quotas on authenticated requests, answering 429
+// with RateLimit-* and Retry-After headers once one is used up. Unlike the
+// login limits, quotas fail open: if Redis or the quota lookup is down the
+// request goes through, so an outage does not take the whole API with it.
+func Quota(limiter RateLimiter, quotas QuotaResolver, cfg *config.Config, logger *slog.Logger, rules ...QuotaRule) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := GetUserId(c, cfg)
+		if userID == "" {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		quota, err := quotas.QuotaFor(ctx, userID, GetUserRole(c, cfg))
+		if err != nil {
+			logger.WarnContext(ctx, "failed to resolve quota; not enforcing it", "error", err, "user_id", userID)
+			c.Next()
+			return
+		}
+
+		var tightest *models.RateLimitStatus
+		for _, rule := range rules {
+			limit := rule.Limit(quota)
+			if limit <= 0 {
+				continue
+			}
+			key := "quota:" + rule.Name + ":" + userID
+			if rule.Scope != nil {
+				scope := rule.Scope(c)
+				if scope == "" {
+					continue
+				}
+				key += ":" + scope
+			}
+
+			status, err := limiter.Allow(ctx, key, limit, rule.Window)
+			if err != nil {
+				logger.WarnContext(ctx, "failed to check quota; not enforcing it", "error", err, "rule", rule.Name)
+				continue
+			}
+			if !status.Allowed {
+				rejectRateLimited(c, status, "Quota exceeded: "+rule.Name)
+				return
+			}
+			tightest = tighter(tightest, status)
+		}
+
+		setRateLimitHeaders(c, tightest)
+		c.Next()
+	}
+}
@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zohirovs/internal/config"
+	"github.com/zohirovs/internal/models"
+)
+
+// RateLimiter is a sliding-window counter; Allow counts a request under key
+// unless key has used up its limit.
+type RateLimiter interface {
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (*models.RateLimitStatus, error)
+}
+
+// RateRule limits one route by one key, e.g. logins per client IP.
+type RateRule struct {
+	// Name distinguishes the rule's counters from other rules'.
+	Name string
+	config.RateLimit
+	// Key picks what is counted; requests with an empty key are not limited.
+	Key func(c *gin.Context) string
+}
+
+// RateLimit rejects requests over any of the rules with 429 and a
+// Retry-After header. Without Redis the limits cannot be checked, so it
+// answers 503 rather than letting requests through.
+func RateLimit(limiter RateLimiter, logger *slog.Logger, rules ...RateRule) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var tightest *models.RateLimitStatus
+		for _, rule := range rules {
+			if rule.Limit <= 0 {
+				continue
+			}
+			key := rule.Key(c)
+			if key == "" {
+				continue
+			}
+
+			status, err := limiter.Allow(c.Request.Context(), rule.Name+":"+key, rule.Limit, rule.Window)
+			if err != nil {
+				logger.ErrorContext(c.Request.Context(), "failed to check rate limit", "error", err, "rule", rule.Name)
+				c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "Service temporarily unavailable"})
+				return
+			}
+			if !status.Allowed {
+				rejectRateLimited(c, status, "Too many requests")
+				return
+			}
+			tightest = tighter(tightest, status)
+		}
+
+		setRateLimitHeaders(c, tightest)
+		c.Next()
+	}
+}
+
+// setRateLimitHeaders reports the limit closest to running out in the
+// RateLimit-* headers.
+func setRateLimitHeaders(c *gin.Context, status *models.RateLimitStatus) {
+	if status == nil {
+		return
+	}
+	c.Header("RateLimit-Limit", strconv.Itoa(status.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(status.Remaining))
+	c.Header("RateLimit-Reset", seconds(status.Reset))
+}
+
+func rejectRateLimited(c *gin.Context, status *models.RateLimitStatus, message string) {
+	setRateLimitHeaders(c, status)
+	SetRetryAfter(c, status.Reset)
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": message})
+}
+
+func tighter(a, b *models.RateLimitStatus) *models.RateLimitStatus {
+	if a == nil || b.Remaining < a.Remaining {
+		return b
+	}
+	return a
+}
+
+// SetRetryAfter sets the Retry-After header in whole seconds, rounded up.
+func SetRetryAfter(c *gin.Context, wait time.Duration) {
+	c.Header("Retry-After", seconds(wait))
+}
+
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+// ByIP counts requests per client IP.
+func ByIP(c *gin.Context) string {
+	return c.ClientIP()
+}
+
+// ByUserID counts requests per authenticated user.
+func ByUserID(cfg *config.Config) func(c *gin.Context) string {
+	return func(c *gin.Context) string {
+		return GetUserId(c, cfg)
+	}
+}
+
+// ByUsername counts requests per username in a JSON body, lowercased so
+// case variants share a limit.
+func ByUsername(c *gin.Context) string {
+	return strings.ToLower(BodyField("username")(c))
+}
+
+// maxKeyBodySize caps how much of a body BodyField buffers.
+const maxKeyBodySize = 1 << 20
+
+// BodyField reads a string field of a JSON request body. The body is
+// restored for the handler; bodies over maxKeyBodySize give "".
+func BodyField(field string) func(c *gin.Context) string {
+	return func(c *gin.Context) string {
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxKeyBodySize+1))
+		// Whatever was read goes back in front of the rest of the body.
+		c.Request.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), c.Request.Body), c.Request.Body}
+		if err != nil || len(body) > maxKeyBodySize {
+			return ""
+		}
+
+		var fields map[string]any
+		if err := json.Unmarshal(body, &fields); err != nil {
+			return ""
+		}
+		value, _ := fields[field].(string)
+		return value
+	}
+}
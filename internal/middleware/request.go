@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zohirovs/internal/config"
+	"github.com/zohirovs/internal/logging"
+	"github.com/zohirovs/internal/models"
+)
+
+// RequestIDHeader carries the request ID in both directions; a client may
+// supply its own to correlate logs and audit entries.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestMeta assigns every request an ID and stores it, the client IP and
+// the authenticated user in the request context for the audit log. The ID,
+// user and route are also attached to every log line logged with that
+// context.
+func RequestMeta(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		userID := GetUserId(c, cfg)
+		ctx := models.WithRequestMeta(c.Request.Context(), models.RequestMeta{
+			RequestId: requestID,
+			IP:        c.ClientIP(),
+			ActorId:   userID,
+		})
+		attrs := []slog.Attr{
+			slog.String("request_id", requestID),
+			slog.String("route", route(c)),
+		}
+		if userID != "" {
+			attrs = append(attrs, slog.String("user_id", userID))
+		}
+		ctx = logging.WithAttrs(ctx, attrs...)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// validRequestID accepts client-supplied IDs made of printable ASCII
+// without spaces, so they cannot forge log lines or headers.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+type Attachment struct {
+	AttachmentId string    `json:"attachment_id" bson:"attachment_id"`
+	TenderId     string    `json:"tender_id" bson:"tender_id"`
+	BidId        string    `json:"bid_id,omitempty" bson:"bid_id,omitempty"` // set for bid (technical proposal) attachments
+	OwnerId      string    `json:"owner_id" bson:"owner_id"`
+	FileName     string    `json:"file_name" bson:"file_name"`
+	ContentType  string    `json:"content_type" bson:"content_type"`
+	Size         int64     `json:"size" bson:"size"`
+	Checksum     string    `json:"checksum" bson:"checksum"` // hex SHA-256
+	StorageKey   string    `json:"-" bson:"storage_key"`
+	CreatedAt    time.Time `json:"created_at" bson:"created_at"`
+}
+
+type AttachmentURL struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
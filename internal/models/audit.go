@@ -0,0 +1,165 @@
+package models
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+type AuditAction string
+
+var (
+	AuditUserRegistered      AuditAction = "user.registered"
+	AuditUserRoleChanged     AuditAction = "user.role_changed"
+	AuditUserPasswordChanged AuditAction = "user.password_changed"
+	AuditUserDeleted         AuditAction = "user.deleted"
+	AuditUserRestored        AuditAction = "user.restored"
+
+	AuditTenderCreated       AuditAction = "tender.created"
+	AuditTenderUpdated       AuditAction = "tender.updated"
+	AuditTenderDeleted       AuditAction = "tender.deleted"
+	AuditTenderRestored      AuditAction = "tender.restored"
+	AuditTenderStatusChanged AuditAction = "tender.status_changed"
+	AuditLotAwarded          AuditAction = "lot.awarded"
+	AuditLotCancelled        AuditAction = "lot.cancelled"
+
+	AuditBidCreated       AuditAction = "bid.created"
+	AuditBidStatusChanged AuditAction = "bid.status_changed"
+	AuditBidWithdrawn     AuditAction = "bid.withdrawn"
+	AuditBidRestored      AuditAction = "bid.restored"
+
+	AuditAttachmentUploaded AuditAction = "attachment.uploaded"
+
+	AuditCategoryCreated AuditAction = "category.created"
+	AuditCategoryDeleted AuditAction = "category.deleted"
+
+	AuditContractorProfileSaved AuditAction = "contractor.profile_saved"
+	AuditContractorVerified     AuditAction = "contractor.verified"
+	AuditReviewCreated          AuditAction = "review.created"
+
+	AuditInvitationCreated   AuditAction = "invitation.created"
+	AuditInvitationResponded AuditAction = "invitation.responded"
+
+	AuditQuestionAsked    AuditAction = "question.asked"
+	AuditQuestionAnswered AuditAction = "question.answered"
+
+	AuditSavedSearchCreated AuditAction = "saved_search.created"
+	AuditSavedSearchDeleted AuditAction = "saved_search.deleted"
+
+	AuditTemplateCreated AuditAction = "template.created"
+	AuditTemplateUpdated AuditAction = "template.updated"
+	AuditTemplateDeleted AuditAction = "template.deleted"
+
+	AuditOrganizationCreated AuditAction = "organization.created"
+	AuditMemberInvited       AuditAction = "organization.member_invited"
+	AuditMemberInviteAnswer  AuditAction = "organization.invite_responded"
+	AuditMemberRoleChanged   AuditAction = "organization.member_role_changed"
+	AuditMemberRemoved       AuditAction = "organization.member_removed"
+
+	AuditNotificationRead AuditAction = "notification.read"
+
+	// AuditRecordsPurged is recorded by the retention job, without an actor.
+	AuditRecordsPurged AuditAction = "retention.purged"
+
+	AuditQuotaOverridden    AuditAction = "quota.overridden"
+	AuditQuotaOverrideReset AuditAction = "quota.override_removed"
+)
+
+type (
+	// AuditEntry records one state-changing action. Entries form a hash
+	// chain: Hash covers the entry's content and PrevHash, the Hash of the
+	// entry with the previous Seq, so editing or deleting an entry breaks
+	// every hash after it.
+	AuditEntry struct {
+		Seq          int64           `json:"seq" bson:"seq"`
+		ActorId      string          `json:"actor_id,omitempty" bson:"actor_id"`
+		Action       AuditAction     `json:"action" bson:"action"`
+		ResourceType string          `json:"resource_type" bson:"resource_type"`
+		ResourceId   string          `json:"resource_id" bson:"resource_id"`
+		Before       json.RawMessage `json:"before,omitempty" bson:"before"`
+		After        json.RawMessage `json:"after,omitempty" bson:"after"`
+		RequestId    string          `json:"request_id,omitempty" bson:"request_id"`
+		IP           string          `json:"ip,omitempty" bson:"ip"`
+		At           time.Time       `json:"at" bson:"at"`
+		PrevHash     string          `json:"prev_hash" bson:"prev_hash"`
+		Hash         string          `json:"hash" bson:"hash"`
+	}
+
+	AuditQuery struct {
+		ActorId      string    `form:"actor_id"`
+		Action       string    `form:"action"`
+		ResourceType string    `form:"resource_type"`
+		ResourceId   string    `form:"resource_id"`
+		RequestId    string    `form:"request_id"`
+		From         time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+		To           time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+		// BeforeSeq pages backwards: pass the smallest Seq of the last page.
+		BeforeSeq int64 `form:"before_seq" binding:"min=0"`
+		Limit     int   `form:"limit" binding:"min=0,max=500"`
+	}
+
+	// AuditVerification is the result of checking the hash chain. BrokenSeq
+	// is the first entry that failed, and Reason says why.
+	AuditVerification struct {
+		Checked   int64  `json:"checked"`
+		Valid     bool   `json:"valid"`
+		BrokenSeq int64  `json:"broken_seq,omitempty"`
+		Reason    string `json:"reason,omitempty"`
+	}
+
+	// RequestMeta describes the HTTP request a service call is made for.
+	RequestMeta struct {
+		RequestId string
+		IP        string
+		ActorId   string
+	}
+)
+
+// ComputeHash returns the hex SHA-256 of the entry's content and PrevHash.
+// At must already be truncated to the stored (millisecond) precision.
+func (e *AuditEntry) ComputeHash() string {
+	payload, _ := json.Marshal(struct {
+		Seq          int64           `json:"seq"`
+		ActorId      string          `json:"actor_id"`
+		Action       AuditAction     `json:"action"`
+		ResourceType string          `json:"resource_type"`
+		ResourceId   string          `json:"resource_id"`
+		Before       json.RawMessage `json:"before"`
+		After        json.RawMessage `json:"after"`
+		RequestId    string          `json:"request_id"`
+		IP           string          `json:"ip"`
+		At           string          `json:"at"`
+		PrevHash     string          `json:"prev_hash"`
+	}{
+		Seq:          e.Seq,
+		ActorId:      e.ActorId,
+		Action:       e.Action,
+		ResourceType: e.ResourceType,
+		ResourceId:   e.ResourceId,
+		Before:       e.Before,
+		After:        e.After,
+		RequestId:    e.RequestId,
+		IP:           e.IP,
+		At:           e.At.UTC().Format(time.RFC3339Nano),
+		PrevHash:     e.PrevHash,
+	})
+
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+type requestMetaKey struct{}
+
+// WithRequestMeta attaches request details to the context for auditing.
+func WithRequestMeta(ctx context.Context, meta RequestMeta) context.Context {
+	return context.WithValue(ctx, requestMetaKey{}, meta)
+}
+
+// RequestMetaFrom returns the request details attached to the context, or
+// the zero value for calls made outside a request.
+func RequestMetaFrom(ctx context.Context) RequestMeta {
+	meta, _ := ctx.Value(requestMetaKey{}).(RequestMeta)
+	return meta
+}
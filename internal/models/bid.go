@@ -11,11 +11,64 @@ type Bid struct {
 	Comments     string    `json:"comments" bson:"comments"`
 	CreatedAt    time.Time `json:"created_at" bson:"created_at"`
 	Status       string    `json:"status" bson:"status"` // pending, accepted, rejected
+
+	// OrganizationId, when set, makes the bid the contractor organization's;
+	// ContractorId is then the member who submitted it.
+	OrganizationId string `json:"organization_id,omitempty" bson:"organization_id,omitempty"`
+
+	// LotPrices prices individual lots of a multi-lot tender; Price is then
+	// their total.
+	LotPrices []LotPrice `json:"lot_prices,omitempty" bson:"lot_prices,omitempty"`
+
+	// Lines prices the tender's line items. When present, Price (and each lot
+	// price) is the sum of the line totals.
+	Lines []BidLine `json:"lines,omitempty" bson:"lines,omitempty"`
+
+	// DeletedAt is set while the bid is soft-deleted, either withdrawn by
+	// the contractor or deleted together with its tender.
+	DeletedAt *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+
+	// ContractorReputation is attached when listing bids for evaluation. It is never stored.
+	ContractorReputation *Reputation `json:"contractor_reputation,omitempty" bson:"-"`
 }
 
 type CreateBid struct {
 	TenderId     string  `json:"tender_id" binding:"required"`
-	Price        float64 `json:"price" binding:"required"`
+	Price        float64 `json:"price" binding:"required_without_all=LotPrices Lines"`
 	DeliveryTime int     `json:"delivery_time" binding:"required"`
 	Comments     string  `json:"comments"`
+
+	// OrganizationId submits the bid on behalf of a contractor organization
+	// the caller manages bids for.
+	OrganizationId string `json:"organization_id"`
+
+	// LotPrices is required for multi-lot tenders and prices one or more lots.
+	LotPrices []LotPrice `json:"lot_prices" binding:"dive"`
+
+	// Lines is required for itemized tenders; totals are computed by the server.
+	Lines []BidLine `json:"lines" binding:"dive"`
+}
+
+// BidSummary is the number of live bids on a tender and the lowest price
+// among them.
+type BidSummary struct {
+	TenderId    string   `json:"tender_id"`
+	Count       int      `json:"count"`
+	Pending     int      `json:"pending"`
+	LowestPrice *float64 `json:"lowest_price,omitempty"`
+}
+
+// SummarizeBids builds the BidSummary of a tender's bids.
+func SummarizeBids(tenderId string, bids []*Bid) *BidSummary {
+	summary := &BidSummary{TenderId: tenderId, Count: len(bids)}
+	for _, bid := range bids {
+		if bid.Status == "pending" {
+			summary.Pending++
+		}
+		if summary.LowestPrice == nil || bid.Price < *summary.LowestPrice {
+			price := bid.Price
+			summary.LowestPrice = &price
+		}
+	}
+	return summary
 }
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type (
+	// Category is a node in the admin-managed tender taxonomy. Path holds the
+	// IDs of all ancestors from the root, so subtree queries need no recursion.
+	Category struct {
+		CategoryId string    `json:"category_id" bson:"category_id"`
+		Name       string    `json:"name" bson:"name"`
+		Slug       string    `json:"slug" bson:"slug"`
+		ParentId   string    `json:"parent_id,omitempty" bson:"parent_id,omitempty"`
+		Path       []string  `json:"path" bson:"path"`
+		CreatedAt  time.Time `json:"created_at" bson:"created_at"`
+	}
+
+	CreateCategory struct {
+		Name     string `json:"name" binding:"required"`
+		Slug     string `json:"slug" binding:"required"`
+		ParentId string `json:"parent_id"`
+	}
+)
+
+// Lineage returns the category's ancestors followed by the category itself.
+func (c *Category) Lineage() []string {
+	return append(append([]string(nil), c.Path...), c.CategoryId)
+}
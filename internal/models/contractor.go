@@ -0,0 +1,62 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+type VerificationStatus string
+
+var (
+	VerificationPending  VerificationStatus = "pending"
+	VerificationVerified VerificationStatus = "verified"
+	VerificationRejected VerificationStatus = "rejected"
+)
+
+type (
+	ContractorProfile struct {
+		UserId             string             `json:"user_id" bson:"user_id"`
+		CompanyName        string             `json:"company_name" bson:"company_name"`
+		RegistrationNumber string             `json:"registration_number" bson:"registration_number"`
+		Categories         []string           `json:"categories" bson:"categories"`
+		Regions            []string           `json:"regions" bson:"regions"`
+		Certifications     []Certification    `json:"certifications" bson:"certifications"`
+		VerificationStatus VerificationStatus `json:"verification_status" bson:"verification_status"`
+		VerificationNote   string             `json:"verification_note,omitempty" bson:"verification_note,omitempty"`
+		VerifiedBy         string             `json:"verified_by,omitempty" bson:"verified_by,omitempty"`
+		VerifiedAt         *time.Time         `json:"verified_at,omitempty" bson:"verified_at,omitempty"`
+		CreatedAt          time.Time          `json:"created_at" bson:"created_at"`
+		UpdatedAt          time.Time          `json:"updated_at" bson:"updated_at"`
+	}
+
+	Certification struct {
+		Name      string    `json:"name" bson:"name" binding:"required"`
+		Issuer    string    `json:"issuer" bson:"issuer"`
+		Number    string    `json:"number" bson:"number"`
+		ExpiresAt time.Time `json:"expires_at" bson:"expires_at" binding:"required"`
+	}
+
+	UpsertContractorProfile struct {
+		CompanyName        string          `json:"company_name" binding:"required"`
+		RegistrationNumber string          `json:"registration_number" binding:"required"`
+		Categories         []string        `json:"categories"`
+		Regions            []string        `json:"regions"`
+		Certifications     []Certification `json:"certifications" binding:"dive"`
+	}
+
+	VerifyContractor struct {
+		Status VerificationStatus `json:"status" binding:"required"`
+		Note   string             `json:"note"`
+	}
+)
+
+// HasValidCertification reports whether the profile holds a certification with
+// the given name (case-insensitive) that has not expired at t.
+func (p *ContractorProfile) HasValidCertification(name string, t time.Time) bool {
+	for _, cert := range p.Certifications {
+		if strings.EqualFold(cert.Name, name) && cert.ExpiresAt.After(t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,16 @@
+package models
+
+type HealthStatus string
+
+var (
+	HealthOK          HealthStatus = "ok"
+	HealthDegraded    HealthStatus = "degraded"
+	HealthUnavailable HealthStatus = "unavailable"
+)
+
+// Readiness reports whether the instance should receive traffic, with the
+// result of each dependency check.
+type Readiness struct {
+	Status HealthStatus      `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
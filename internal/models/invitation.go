@@ -0,0 +1,66 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+type Visibility string
+
+var (
+	// VisibilityPublic tenders are open to every contractor. Tenders stored
+	// before visibility existed have an empty value and are treated as public.
+	VisibilityPublic     Visibility = "public"
+	VisibilityInviteOnly Visibility = "invite_only"
+)
+
+type InvitationStatus string
+
+var (
+	InvitationPending  InvitationStatus = "pending"
+	InvitationAccepted InvitationStatus = "accepted"
+	InvitationDeclined InvitationStatus = "declined"
+)
+
+type (
+	// Invitation grants a contractor access to an invite-only tender. Either
+	// ContractorId or Email identifies the invitee. Account emails are not
+	// verified, so an invitation by email is claimed with the single-use token
+	// mailed to that address rather than by matching the account's email.
+	Invitation struct {
+		InvitationId string           `json:"invitation_id" bson:"invitation_id"`
+		TenderId     string           `json:"tender_id" bson:"tender_id"`
+		ContractorId string           `json:"contractor_id,omitempty" bson:"contractor_id"`
+		Email        string           `json:"email,omitempty" bson:"email"`
+		Status       InvitationStatus `json:"status" bson:"status"`
+		CreatedAt    time.Time        `json:"created_at" bson:"created_at"`
+		RespondedAt  *time.Time       `json:"responded_at,omitempty" bson:"responded_at,omitempty"`
+		// TokenHash is the SHA-256 of the claim token of an email invitation;
+		// it is cleared once the invitation is claimed.
+		TokenHash string `json:"-" bson:"token_hash,omitempty"`
+	}
+
+	// InviteContractors lists invitees by user ID, email, or both.
+	InviteContractors struct {
+		ContractorIds []string `json:"contractor_ids"`
+		Emails        []string `json:"emails"`
+	}
+
+	RespondInvitation struct {
+		Accept bool `json:"accept"`
+		// Token is the claim token from the invitation email; it is required
+		// to respond to an invitation sent by email.
+		Token string `json:"token,omitempty"`
+	}
+)
+
+// IsInviteOnly reports whether access to the tender is restricted to invitees.
+func (t *Tender) IsInviteOnly() bool {
+	return Visibility(t.Visibility) == VisibilityInviteOnly
+}
+
+// NormalizeEmail lowercases and trims an address so invitations match
+// regardless of how the client typed it.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,90 @@
+package models
+
+import "math"
+
+type (
+	// LineItem is one row of a tender's bill of quantities. LotId is set for
+	// items listed under a lot.
+	LineItem struct {
+		ItemId      string  `json:"item_id"`
+		LotId       string  `json:"lot_id,omitempty"`
+		Description string  `json:"description"`
+		Unit        string  `json:"unit"`
+		Quantity    float64 `json:"quantity"`
+	}
+
+	CreateLineItem struct {
+		Description string  `json:"description" binding:"required"`
+		Unit        string  `json:"unit" binding:"required"`
+		Quantity    float64 `json:"quantity" binding:"required,gt=0"`
+	}
+
+	// BidLine prices one line item. Total is computed by the server.
+	BidLine struct {
+		ItemId    string  `json:"item_id" bson:"item_id" binding:"required"`
+		UnitPrice float64 `json:"unit_price" bson:"unit_price" binding:"required,gt=0"`
+		Total     float64 `json:"total" bson:"total"`
+	}
+
+	// BidComparison lays bids side by side, one row per line item. Cells in
+	// each row follow the order of Bids.
+	BidComparison struct {
+		TenderId string          `json:"tender_id"`
+		Bids     []ComparedBid   `json:"bids"`
+		Rows     []ComparisonRow `json:"rows"`
+	}
+
+	ComparedBid struct {
+		BidId        string  `json:"bid_id"`
+		ContractorId string  `json:"contractor_id"`
+		Price        float64 `json:"price"`
+		Status       string  `json:"status"`
+	}
+
+	ComparisonRow struct {
+		Item  LineItem         `json:"item"`
+		Cells []ComparisonCell `json:"cells"`
+		// LowestBidId is the bid with the lowest unit price for the item.
+		LowestBidId string `json:"lowest_bid_id,omitempty"`
+	}
+
+	// ComparisonCell is empty when the bid does not price the item, e.g. it
+	// bid on other lots only.
+	ComparisonCell struct {
+		UnitPrice *float64 `json:"unit_price,omitempty"`
+		Total     *float64 `json:"total,omitempty"`
+	}
+)
+
+// RoundMoney rounds an amount to cents.
+func RoundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// NewLineItems converts requested line items; IDs are assigned when the
+// tender is stored.
+func NewLineItems(items []CreateLineItem) []LineItem {
+	var result []LineItem
+	for _, item := range items {
+		result = append(result, LineItem{
+			Description: item.Description,
+			Unit:        item.Unit,
+			Quantity:    item.Quantity,
+		})
+	}
+	return result
+}
+
+// AllLineItems returns the tender's own line items followed by those of each lot.
+func (t *Tender) AllLineItems() []LineItem {
+	items := append([]LineItem{}, t.LineItems...)
+	for _, lot := range t.Lots {
+		items = append(items, lot.LineItems...)
+	}
+	return items
+}
+
+// Itemized reports whether bids on the tender must be priced per line item.
+func (t *Tender) Itemized() bool {
+	return len(t.AllLineItems()) > 0
+}
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+type LotStatus string
+
+var (
+	LotOpen      LotStatus = "open"
+	LotAwarded   LotStatus = "awarded"
+	LotCancelled LotStatus = "cancelled"
+)
+
+type (
+	// Lot is an independently bid and awarded part of a tender. A lot without
+	// its own deadline uses the tender's.
+	Lot struct {
+		LotId    string     `json:"lot_id"`
+		Title    string     `json:"title"`
+		Quantity int        `json:"quantity"`
+		Unit     string     `json:"unit,omitempty"`
+		Budget   int        `json:"budget"`
+		Deadline *time.Time `json:"deadline,omitempty"`
+		Status   LotStatus  `json:"status"`
+
+		LineItems []LineItem `json:"line_items,omitempty"`
+
+		AwardedBidId        string     `json:"awarded_bid_id,omitempty"`
+		AwardedContractorId string     `json:"awarded_contractor_id,omitempty"`
+		ResolvedAt          *time.Time `json:"resolved_at,omitempty"`
+	}
+
+	CreateLot struct {
+		Title    string `json:"title" binding:"required"`
+		Quantity int    `json:"quantity" binding:"required,min=1"`
+		Unit     string `json:"unit"`
+		Budget   int    `json:"budget" binding:"required,min=1"`
+		// Deadline uses the same formats as the tender deadline.
+		Deadline  string           `json:"deadline"`
+		LineItems []CreateLineItem `json:"line_items" binding:"dive"`
+	}
+
+	// LotPrice is a bid's price for one lot.
+	LotPrice struct {
+		LotId string  `json:"lot_id" bson:"lot_id" binding:"required"`
+		Price float64 `json:"price" bson:"price" binding:"required,gt=0"`
+	}
+
+	AwardLot struct {
+		BidId string `json:"bid_id" binding:"required"`
+	}
+)
+
+// Lot returns the tender's lot with the given ID, or nil.
+func (t *Tender) Lot(id string) *Lot {
+	for i := range t.Lots {
+		if t.Lots[i].LotId == id {
+			return &t.Lots[i]
+		}
+	}
+	return nil
+}
+
+// LotDeadline is the lot's own deadline, or the tender's when it has none.
+func (t *Tender) LotDeadline(lot *Lot) time.Time {
+	if lot.Deadline != nil {
+		return *lot.Deadline
+	}
+	return t.Deadline
+}
+
+// LotsResolved reports whether every lot has been awarded or cancelled, and
+// whether at least one was awarded.
+func (t *Tender) LotsResolved() (resolved bool, anyAwarded bool) {
+	for _, lot := range t.Lots {
+		switch lot.Status {
+		case LotAwarded:
+			anyAwarded = true
+		case LotCancelled:
+		default:
+			return false, anyAwarded
+		}
+	}
+	return true, anyAwarded
+}
+
+// ValidateLots checks that lot deadlines do not extend past the tender's and
+// that a multi-lot tender is itemized per lot, for all lots or none.
+func (t *Tender) ValidateLots() error {
+	if len(t.Lots) == 0 {
+		return nil
+	}
+	if len(t.LineItems) > 0 {
+		return fmt.Errorf("line items of a multi-lot tender belong to its lots")
+	}
+
+	itemized := len(t.Lots[0].LineItems) > 0
+	for _, lot := range t.Lots {
+		if lot.Deadline != nil && lot.Deadline.After(t.Deadline) {
+			return fmt.Errorf("lot %q deadline is after the tender deadline", lot.Title)
+		}
+		if (len(lot.LineItems) > 0) != itemized {
+			return fmt.Errorf("either every lot or no lot must have line items")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+type NotificationType string
+
+var (
+	NotificationTenderMatch     NotificationType = "tender_match"
+	NotificationQuestionAsked   NotificationType = "question_asked"
+	NotificationQuestionAnswer  NotificationType = "question_answered"
+	NotificationAnswerPublished NotificationType = "answer_published"
+	NotificationInvited         NotificationType = "tender_invitation"
+	NotificationInviteResponse  NotificationType = "invitation_response"
+	NotificationLotAwarded      NotificationType = "lot_awarded"
+	NotificationMemberInvite    NotificationType = "organization_invitation"
+	NotificationTenderDeleted   NotificationType = "tender_deleted"
+	NotificationTenderRestored  NotificationType = "tender_restored"
+)
+
+type Notification struct {
+	NotificationId string           `json:"notification_id" bson:"notification_id"`
+	UserId         string           `json:"user_id" bson:"user_id"`
+	Type           NotificationType `json:"type" bson:"type"`
+	Title          string           `json:"title" bson:"title"`
+	Message        string           `json:"message" bson:"message"`
+	TenderId       string           `json:"tender_id,omitempty" bson:"tender_id,omitempty"`
+	Read           bool             `json:"read" bson:"read"`
+	CreatedAt      time.Time        `json:"created_at" bson:"created_at"`
+
+	// Digest marks notifications that should also go out in the periodic
+	// email digest; EmailedAt is set once they have.
+	Digest    bool       `json:"-" bson:"digest"`
+	EmailedAt *time.Time `json:"-" bson:"emailed_at,omitempty"`
+}
@@ -0,0 +1,93 @@
+package models
+
+import "time"
+
+// OrgRole is a member's role within an organization, independent of the
+// account's global Role.
+type OrgRole string
+
+var (
+	// OrgOwner members manage the organization's members as well as its
+	// tenders or bids.
+	OrgOwner OrgRole = "owner"
+	// OrgManager members create and manage tenders or bids on its behalf.
+	OrgManager OrgRole = "manager"
+	// OrgViewer members can see the organization's tenders or bids but not
+	// change them.
+	OrgViewer OrgRole = "viewer"
+)
+
+// CanManage reports whether the role may act on the organization's tenders
+// and bids.
+func (r OrgRole) CanManage() bool {
+	return r == OrgOwner || r == OrgManager
+}
+
+type MemberInviteStatus string
+
+var (
+	MemberInvitePending  MemberInviteStatus = "pending"
+	MemberInviteAccepted MemberInviteStatus = "accepted"
+	MemberInviteDeclined MemberInviteStatus = "declined"
+)
+
+type (
+	// Organization is a company that owns tenders (Kind client) or bids (Kind
+	// contractor) together with its members. Only accounts of the same kind
+	// can join it.
+	Organization struct {
+		OrganizationId string    `json:"organization_id" bson:"organization_id"`
+		Name           string    `json:"name" bson:"name"`
+		Kind           Role      `json:"kind" bson:"kind"`
+		CreatedBy      string    `json:"created_by" bson:"created_by"`
+		CreatedAt      time.Time `json:"created_at" bson:"created_at"`
+
+		// Role is the viewer's own role. It is never stored.
+		Role OrgRole `json:"role,omitempty" bson:"-"`
+	}
+
+	OrganizationMember struct {
+		OrganizationId string    `json:"organization_id" bson:"organization_id"`
+		UserId         string    `json:"user_id" bson:"user_id"`
+		Role           OrgRole   `json:"role" bson:"role"`
+		JoinedAt       time.Time `json:"joined_at" bson:"joined_at"`
+	}
+
+	// MemberInvite invites someone by email to join an organization with a
+	// given role. Account emails are not verified, so it is accepted with the
+	// single-use token mailed to that address rather than by matching the
+	// account's email. UserId is the account that answered it.
+	MemberInvite struct {
+		InviteId       string             `json:"invite_id" bson:"invite_id"`
+		OrganizationId string             `json:"organization_id" bson:"organization_id"`
+		Email          string             `json:"email" bson:"email"`
+		Role           OrgRole            `json:"role" bson:"role"`
+		Status         MemberInviteStatus `json:"status" bson:"status"`
+		InvitedBy      string             `json:"invited_by" bson:"invited_by"`
+		CreatedAt      time.Time          `json:"created_at" bson:"created_at"`
+		RespondedAt    *time.Time         `json:"responded_at,omitempty" bson:"responded_at,omitempty"`
+		UserId         string             `json:"user_id,omitempty" bson:"user_id,omitempty"`
+		// TokenHash is the SHA-256 of the invite's token; it is cleared once
+		// the invite is answered.
+		TokenHash string `json:"-" bson:"token_hash,omitempty"`
+	}
+
+	CreateOrganization struct {
+		Name string `json:"name" binding:"required"`
+	}
+
+	InviteMember struct {
+		Email string  `json:"email" binding:"required,email"`
+		Role  OrgRole `json:"role" binding:"required,oneof=owner manager viewer"`
+	}
+
+	ChangeMemberRole struct {
+		Role OrgRole `json:"role" binding:"required,oneof=owner manager viewer"`
+	}
+
+	RespondMemberInvite struct {
+		Accept bool `json:"accept"`
+		// Token is the token from the invitation email.
+		Token string `json:"token" binding:"required"`
+	}
+)
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+type (
+	// Question is a contractor's clarification request on a tender. Anonymous
+	// hides the asker from other bidders, not from the tender's client.
+	// Published answers are visible to everyone who can see the tender.
+	Question struct {
+		QuestionId   string     `json:"question_id" bson:"question_id"`
+		TenderId     string     `json:"tender_id" bson:"tender_id"`
+		ContractorId string     `json:"contractor_id,omitempty" bson:"contractor_id"`
+		Anonymous    bool       `json:"anonymous" bson:"anonymous"`
+		Body         string     `json:"body" bson:"body"`
+		Answer       string     `json:"answer,omitempty" bson:"answer,omitempty"`
+		Published    bool       `json:"published" bson:"published"`
+		CreatedAt    time.Time  `json:"created_at" bson:"created_at"`
+		AnsweredAt   *time.Time `json:"answered_at,omitempty" bson:"answered_at,omitempty"`
+	}
+
+	CreateQuestion struct {
+		Body      string `json:"body" binding:"required,max=2000"`
+		Anonymous bool   `json:"anonymous"`
+	}
+
+	// AnswerQuestion answers or re-answers a question. Once published an
+	// answer stays published.
+	AnswerQuestion struct {
+		Answer  string `json:"answer" binding:"required,max=5000"`
+		Publish bool   `json:"publish"`
+	}
+)
+
+// Redacted returns a copy safe to show to bidders other than the asker.
+func (q *Question) Redacted() *Question {
+	redacted := *q
+	if redacted.Anonymous {
+		redacted.ContractorId = ""
+	}
+	if !redacted.Published {
+		redacted.Answer = ""
+		redacted.AnsweredAt = nil
+	}
+	return &redacted
+}
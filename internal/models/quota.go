@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+type (
+	// Quota bounds how much one user may do. Zero means unlimited.
+	Quota struct {
+		RequestsPerMinute    int `json:"requests_per_minute"`
+		TendersPerDay        int `json:"tenders_per_day"`
+		BidsPerTenderPerHour int `json:"bids_per_tender_per_hour"`
+	}
+
+	// QuotaOverride replaces some of a user's role quotas; nil fields keep
+	// the role's value.
+	QuotaOverride struct {
+		UserId               string    `json:"user_id" bson:"user_id"`
+		RequestsPerMinute    *int      `json:"requests_per_minute,omitempty" bson:"requests_per_minute,omitempty"`
+		TendersPerDay        *int      `json:"tenders_per_day,omitempty" bson:"tenders_per_day,omitempty"`
+		BidsPerTenderPerHour *int      `json:"bids_per_tender_per_hour,omitempty" bson:"bids_per_tender_per_hour,omitempty"`
+		Note                 string    `json:"note,omitempty" bson:"note,omitempty"`
+		UpdatedBy            string    `json:"updated_by" bson:"updated_by"`
+		UpdatedAt            time.Time `json:"updated_at" bson:"updated_at"`
+	}
+
+	SetQuotaOverride struct {
+		RequestsPerMinute    *int   `json:"requests_per_minute" binding:"omitempty,min=0"`
+		TendersPerDay        *int   `json:"tenders_per_day" binding:"omitempty,min=0"`
+		BidsPerTenderPerHour *int   `json:"bids_per_tender_per_hour" binding:"omitempty,min=0"`
+		Note                 string `json:"note"`
+	}
+
+	// UserQuota is the quota in force for a user and the override, if any,
+	// that shaped it.
+	UserQuota struct {
+		UserId   string         `json:"user_id"`
+		Role     Role           `json:"role"`
+		Quota    Quota          `json:"quota"`
+		Override *QuotaOverride `json:"override,omitempty"`
+	}
+
+	// RateLimitStatus is the outcome of counting one request against a limit.
+	RateLimitStatus struct {
+		Limit     int
+		Remaining int
+		// Reset is how long until the window frees up room again.
+		Reset   time.Duration
+		Allowed bool
+	}
+)
+
+// Apply returns q with the override's fields in place of the role's.
+func (o *QuotaOverride) Apply(q Quota) Quota {
+	if o == nil {
+		return q
+	}
+	if o.RequestsPerMinute != nil {
+		q.RequestsPerMinute = *o.RequestsPerMinute
+	}
+	if o.TendersPerDay != nil {
+		q.TendersPerDay = *o.TendersPerDay
+	}
+	if o.BidsPerTenderPerHour != nil {
+		q.BidsPerTenderPerHour = *o.BidsPerTenderPerHour
+	}
+	return q
+}
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// Reputation prior used for the Bayesian score: a contractor with few reviews
+// is pulled towards an average rating of reputationPriorMean.
+const (
+	reputationPriorMean   = 3.0
+	reputationPriorWeight = 5.0
+)
+
+type (
+	Review struct {
+		ReviewId        string    `json:"review_id" bson:"review_id"`
+		TenderId        string    `json:"tender_id" bson:"tender_id"`
+		BidId           string    `json:"bid_id" bson:"bid_id"`
+		ContractorId    string    `json:"contractor_id" bson:"contractor_id"`
+		ClientId        string    `json:"client_id" bson:"client_id"`
+		Rating          int       `json:"rating" bson:"rating"` // 1-5
+		Comment         string    `json:"comment" bson:"comment"`
+		DeliveredOnTime bool      `json:"delivered_on_time" bson:"delivered_on_time"`
+		CreatedAt       time.Time `json:"created_at" bson:"created_at"`
+	}
+
+	CreateReview struct {
+		// ContractorId names the winner to review; it may be left out when
+		// the tender was awarded to a single contractor.
+		ContractorId    string `json:"contractor_id"`
+		Rating          int    `json:"rating" binding:"required,min=1,max=5"`
+		Comment         string `json:"comment"`
+		DeliveredOnTime bool   `json:"delivered_on_time"`
+	}
+
+	// Reputation is the per-contractor aggregate, updated incrementally as reviews arrive.
+	Reputation struct {
+		ContractorId string    `json:"contractor_id" bson:"contractor_id"`
+		ReviewCount  int       `json:"review_count" bson:"review_count"`
+		RatingSum    int       `json:"-" bson:"rating_sum"`
+		OnTimeCount  int       `json:"on_time_count" bson:"on_time_count"`
+		UpdatedAt    time.Time `json:"updated_at" bson:"updated_at"`
+
+		AverageRating float64 `json:"average_rating" bson:"-"`
+		OnTimeRate    float64 `json:"on_time_rate" bson:"-"`
+		Score         float64 `json:"score" bson:"-"` // Bayesian average used for bid evaluation
+	}
+)
+
+// Compute fills the derived fields from the stored counters.
+func (r *Reputation) Compute() {
+	r.AverageRating, r.OnTimeRate = 0, 0
+	if r.ReviewCount > 0 {
+		r.AverageRating = float64(r.RatingSum) / float64(r.ReviewCount)
+		r.OnTimeRate = float64(r.OnTimeCount) / float64(r.ReviewCount)
+	}
+	r.Score = (reputationPriorMean*reputationPriorWeight + float64(r.RatingSum)) /
+		(reputationPriorWeight + float64(r.ReviewCount))
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+type (
+	// SavedSearch describes tenders a contractor wants to hear about. Empty
+	// criteria match everything; Tags match if any tag overlaps; every keyword
+	// must appear in the title or description.
+	SavedSearch struct {
+		SearchId    string    `json:"search_id" bson:"search_id"`
+		UserId      string    `json:"user_id" bson:"user_id"`
+		Name        string    `json:"name" bson:"name"`
+		CategoryId  string    `json:"category_id,omitempty" bson:"category_id"`
+		Tags        []string  `json:"tags,omitempty" bson:"tags"`
+		Keywords    []string  `json:"keywords,omitempty" bson:"keywords"`
+		MinBudget   int       `json:"min_budget,omitempty" bson:"min_budget"`
+		MaxBudget   int       `json:"max_budget,omitempty" bson:"max_budget"`
+		EmailDigest bool      `json:"email_digest" bson:"email_digest"`
+		CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	}
+
+	CreateSavedSearch struct {
+		Name        string   `json:"name" binding:"required"`
+		CategoryId  string   `json:"category_id"`
+		Tags        []string `json:"tags"`
+		Keywords    []string `json:"keywords"`
+		MinBudget   int      `json:"min_budget" binding:"min=0"`
+		MaxBudget   int      `json:"max_budget" binding:"min=0"`
+		EmailDigest bool     `json:"email_digest"`
+	}
+)
+
+// Matches reports whether the tender satisfies every criterion of the search.
+func (s *SavedSearch) Matches(t *Tender) bool {
+	if s.CategoryId != "" && !containsFold(t.CategoryPath, s.CategoryId) {
+		return false
+	}
+	if s.MinBudget > 0 && t.Budget < s.MinBudget {
+		return false
+	}
+	if s.MaxBudget > 0 && t.Budget > s.MaxBudget {
+		return false
+	}
+
+	if len(s.Tags) > 0 {
+		matched := false
+		for _, tag := range s.Tags {
+			if containsFold(t.Tags, tag) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	text := strings.ToLower(t.Title + " " + t.Description)
+	for _, keyword := range s.Keywords {
+		if !strings.Contains(text, strings.ToLower(keyword)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NormalizeTags lower-cases, trims and de-duplicates tags.
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
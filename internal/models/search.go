@@ -0,0 +1,76 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultSearchLanguage is the stemming language used when none is configured.
+// It must be one of the languages supported by MongoDB text indexes.
+const DefaultSearchLanguage = "english"
+
+// BudgetBands are the facet buckets for tender budgets. Each value is the
+// inclusive lower bound of a band; the last band is open-ended.
+var BudgetBands = []int{0, 10000, 50000, 250000, 1000000}
+
+type (
+	TenderSearchQuery struct {
+		Query      string `form:"q" binding:"required"`
+		Status     string `form:"status"`
+		CategoryId string `form:"category_id"`
+		MinBudget  int    `form:"min_budget" binding:"min=0"`
+		MaxBudget  int    `form:"max_budget" binding:"min=0"`
+		// Language selects the stemmer for the query; defaults to the
+		// configured search language.
+		Language string `form:"language"`
+		Limit    int    `form:"limit" binding:"min=0,max=100"`
+		Offset   int    `form:"offset" binding:"min=0"`
+
+		// ViewerId is the searching user, set by the handler; it decides which
+		// invite-only tenders are visible.
+		ViewerId string `form:"-"`
+	}
+
+	TenderSearchHit struct {
+		TenderId    string    `json:"tender_id" bson:"tender_id"`
+		Title       string    `json:"title" bson:"title"`
+		Description string    `json:"description" bson:"description"`
+		Status      string    `json:"status" bson:"status"`
+		Budget      int       `json:"budget" bson:"budget"`
+		Deadline    time.Time `json:"deadline" bson:"deadline"`
+		CategoryId  string    `json:"category_id,omitempty" bson:"category_id"`
+		Tags        []string  `json:"tags,omitempty" bson:"tags"`
+		Score       float64   `json:"score" bson:"score"`
+		// Highlights maps a field name to HTML snippets: the field's text,
+		// escaped, with matches wrapped in <em>.
+		Highlights map[string][]string `json:"highlights,omitempty" bson:"-"`
+	}
+
+	FacetCount struct {
+		Value string `json:"value" bson:"_id"`
+		Count int    `json:"count" bson:"count"`
+	}
+
+	TenderSearchFacets struct {
+		Status   []FacetCount `json:"status" bson:"status"`
+		Category []FacetCount `json:"category" bson:"category"`
+		Budget   []FacetCount `json:"budget" bson:"budget"`
+	}
+
+	TenderSearchResult struct {
+		Total  int                `json:"total"`
+		Hits   []*TenderSearchHit `json:"hits"`
+		Facets TenderSearchFacets `json:"facets"`
+	}
+)
+
+// BudgetBandLabel names the band starting at lower, e.g. "10000-49999" or
+// "1000000+".
+func BudgetBandLabel(lower int) string {
+	for i, bound := range BudgetBands {
+		if bound == lower && i+1 < len(BudgetBands) {
+			return fmt.Sprintf("%d-%d", lower, BudgetBands[i+1]-1)
+		}
+	}
+	return fmt.Sprintf("%d+", lower)
+}
@@ -3,6 +3,8 @@ package models
 type Status string
 
 var (
+	// DRAFT tenders are visible only to their client until published (OPEN).
+	DRAFT   Status = "DRAFT"
 	OPEN    Status = "OPEN"
 	CLOSED  Status = "CLOSED"
 	AWARDED Status = "AWARDED"
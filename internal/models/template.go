@@ -0,0 +1,77 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+type (
+	// EvaluationCriterion is one weighted factor the client scores bids on.
+	// Weights are percentages and must add up to 100.
+	EvaluationCriterion struct {
+		Name        string `json:"name" bson:"name" binding:"required"`
+		Description string `json:"description,omitempty" bson:"description"`
+		Weight      int    `json:"weight" bson:"weight" binding:"required,min=1,max=100"`
+	}
+
+	// TenderTemplate holds the reusable parts of a tender. Deadlines are set
+	// when a tender is created from it.
+	TenderTemplate struct {
+		TemplateId  string   `json:"template_id" bson:"template_id"`
+		ClientId    string   `json:"client_id" bson:"client_id"`
+		Name        string   `json:"name" bson:"name"`
+		Title       string   `json:"title" bson:"title"`
+		Description string   `json:"description" bson:"description"`
+		Budget      int      `json:"budget,omitempty" bson:"budget"`
+		CategoryId  string   `json:"category_id,omitempty" bson:"category_id"`
+		Tags        []string `json:"tags,omitempty" bson:"tags"`
+
+		LineItems          []CreateLineItem      `json:"line_items,omitempty" bson:"line_items"`
+		EvaluationCriteria []EvaluationCriterion `json:"evaluation_criteria,omitempty" bson:"evaluation_criteria"`
+
+		RequiresVerified       bool     `json:"requires_verified" bson:"requires_verified"`
+		RequiredCertifications []string `json:"required_certifications,omitempty" bson:"required_certifications"`
+
+		CreatedAt time.Time `json:"created_at" bson:"created_at"`
+		UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+	}
+
+	SaveTemplate struct {
+		Name        string   `json:"name" binding:"required"`
+		Title       string   `json:"title" binding:"required"`
+		Description string   `json:"description" binding:"required"`
+		Budget      int      `json:"budget" binding:"min=0"`
+		CategoryId  string   `json:"category_id"`
+		Tags        []string `json:"tags"`
+
+		LineItems          []CreateLineItem      `json:"line_items" binding:"dive"`
+		EvaluationCriteria []EvaluationCriterion `json:"evaluation_criteria" binding:"dive"`
+
+		RequiresVerified       bool     `json:"requires_verified"`
+		RequiredCertifications []string `json:"required_certifications"`
+	}
+
+	// NewDraft carries what a copied tender or template lacks: a new deadline
+	// and, optionally, a different budget.
+	NewDraft struct {
+		// Deadline is RFC3339, or "2006-01-02T15:04:05" interpreted in TimeZone.
+		Deadline string `json:"deadline" binding:"required"`
+		TimeZone string `json:"time_zone"`
+		Budget   int    `json:"budget" binding:"min=0"`
+	}
+)
+
+// ValidateCriteria checks that evaluation criteria weights total 100%.
+func ValidateCriteria(criteria []EvaluationCriterion) error {
+	if len(criteria) == 0 {
+		return nil
+	}
+	total := 0
+	for _, criterion := range criteria {
+		total += criterion.Weight
+	}
+	if total != 100 {
+		return fmt.Errorf("evaluation criteria weights must total 100, got %d", total)
+	}
+	return nil
+}
@@ -1,22 +1,94 @@
 package models
 
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultTimeZone is used when a tender does not specify its own time zone.
+const DefaultTimeZone = "UTC"
+
+// localDeadlineLayout is accepted for deadlines without an explicit UTC offset;
+// such values are interpreted in the tender's time zone.
+const localDeadlineLayout = "2006-01-02T15:04:05"
+
 type (
 	Tender struct {
-		TenderId      string `json:"tender_id,omitempty"`
-		ClientId      string `json:"client_id,omitempty"`
-		Title         string `json:"title" binding:"required"`
-		Description   string `json:"description" binding:"required"` // Fix typo here
-		Budget        int    `json:"budget" binding:"required"`
-		Status        string `json:"status,omitempty"` // Optional
-		Deadline      string `json:"deadline" binding:"required"`
-		AttachmentUrl string `json:"attachment_url" binding:"required"`
+		TenderId      string     `json:"tender_id,omitempty"`
+		ClientId      string     `json:"client_id,omitempty"`
+		Title         string     `json:"title" binding:"required"`
+		Description   string     `json:"description" binding:"required"` // Fix typo here
+		Budget        int        `json:"budget" binding:"required"`
+		Status        string     `json:"status,omitempty"` // Optional
+		Deadline      time.Time  `json:"deadline" binding:"required"`
+		TimeZone      string     `json:"time_zone"`                // IANA name, used for display only
+		AttachmentUrl string     `json:"attachment_url,omitempty"` // legacy external link; see Attachment
+		CreatedAt     time.Time  `json:"created_at"`
+		UpdatedAt     time.Time  `json:"updated_at"`
+		PublishedAt   *time.Time `json:"published_at,omitempty"`
+		ClosedAt      *time.Time `json:"closed_at,omitempty"`
+
+		// CategoryPath is the category and its ancestors, used for subtree matching.
+		CategoryId   string   `json:"category_id,omitempty"`
+		CategoryPath []string `json:"category_path,omitempty"`
+		Tags         []string `json:"tags,omitempty"`
+
+		// Qualification requirements checked when a contractor submits a bid.
+		RequiresVerified       bool     `json:"requires_verified"`
+		RequiredCertifications []string `json:"required_certifications,omitempty"`
+
+		// Visibility is "public" or "invite_only"; see Invitation.
+		Visibility string `json:"visibility"`
+
+		// OrganizationId, when set, makes the tender the organization's: its
+		// owners and managers manage it and all its members can see it.
+		// ClientId is then the member who created it.
+		OrganizationId string `json:"organization_id,omitempty"`
+
+		// Lots split the tender into separately awarded parts. A tender without
+		// lots is bid on and awarded as a whole.
+		Lots []Lot `json:"lots,omitempty"`
+
+		// LineItems is the bill of quantities of a tender without lots; lots
+		// carry their own. Bids must price every item they cover.
+		LineItems []LineItem `json:"line_items,omitempty"`
+
+		EvaluationCriteria []EvaluationCriterion `json:"evaluation_criteria,omitempty"`
+
+		// DeletedAt is set while the tender is soft-deleted; its bids are
+		// deleted with it and share the same time.
+		DeletedAt *time.Time `json:"deleted_at,omitempty"`
+
+		// DeadlineLocal is the deadline rendered in TimeZone. It is never stored.
+		DeadlineLocal string `json:"deadline_local,omitempty" bson:"-"`
 	}
 	CreateTender struct {
-		Title         string `json:"title" binding:"required"`
-		Description   string `json:"description" binding:"required"`
-		Budget        int    `json:"budget" binding:"required"`
+		Title       string `json:"title" binding:"required"`
+		Description string `json:"description" binding:"required"`
+		Budget      int    `json:"budget" binding:"required"`
+		// Deadline is RFC3339, or "2006-01-02T15:04:05" interpreted in TimeZone.
 		Deadline      string `json:"deadline" binding:"required"`
-		AttachmentUrl string `json:"attachment_url" binding:"required"`
+		TimeZone      string `json:"time_zone"`
+		AttachmentUrl string `json:"attachment_url"` // optional; upload files via the attachments endpoint
+
+		CategoryId string   `json:"category_id"`
+		Tags       []string `json:"tags"`
+
+		RequiresVerified       bool     `json:"requires_verified"`
+		RequiredCertifications []string `json:"required_certifications"`
+
+		Visibility string `json:"visibility" binding:"omitempty,oneof=public invite_only"`
+
+		// OrganizationId creates the tender on behalf of a client organization
+		// the caller manages tenders for.
+		OrganizationId string `json:"organization_id"`
+
+		Lots []CreateLot `json:"lots" binding:"dive"`
+		// LineItems is the bill of quantities for tenders without lots; lots
+		// carry their own items.
+		LineItems []CreateLineItem `json:"line_items" binding:"dive"`
+
+		EvaluationCriteria []EvaluationCriterion `json:"evaluation_criteria" binding:"dive"`
 	}
 
 	UpdateTender struct {
@@ -25,8 +97,52 @@ type (
 		Description   string `json:"desription"`
 		Budget        int    `json:"budget"`
 		Deadline      string `json:"deadline"`
+		TimeZone      string `json:"time_zone"`
 		AttachmentUrl string `json:"attachment_url"`
 	}
 )
 
+// LoadTimeZone resolves an IANA time zone name, defaulting to UTC when empty.
+func LoadTimeZone(name string) (*time.Location, error) {
+	if name == "" {
+		name = DefaultTimeZone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q: %w", name, err)
+	}
+	return loc, nil
+}
+
+// ParseDeadline parses a deadline given either as RFC3339 (with offset) or as a
+// local date-time in the named time zone. The result is always in UTC.
+func ParseDeadline(value, timeZone string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+
+	loc, err := LoadTimeZone(timeZone)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	t, err := time.ParseInLocation(localDeadlineLayout, value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid deadline format: %w", err)
+	}
+	return t.UTC(), nil
+}
+
+// Localize fills DeadlineLocal from Deadline and TimeZone.
+func (t *Tender) Localize() {
+	if t.Deadline.IsZero() {
+		return
+	}
+	loc, err := LoadTimeZone(t.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	t.DeadlineLocal = t.Deadline.In(loc).Format(time.RFC3339)
+}
+
 // Tender (id, client_id, title, description, deadline, budget, status)
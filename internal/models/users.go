@@ -7,11 +7,12 @@ type Role string
 var (
 	Client     Role = "client"
 	Contractor Role = "contractor"
+	Admin      Role = "admin"
 )
 
 type (
 	User struct {
-		ID        string    `json:"id"`
+		ID        string    `json:"id" bson:"-"` // stored as _id
 		Username  string    `json:"username"`
 		Email     string    `json:"email"`
 		Password  string    `json:"password"`
@@ -0,0 +1,21 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/zohirovs/internal/models"
+)
+
+type AttachmentRepo interface {
+	CreateAttachment(ctx context.Context, attachment *models.Attachment) (*models.Attachment, error)
+	GetAttachment(ctx context.Context, id string) (*models.Attachment, error)
+	ListTenderAttachments(ctx context.Context, tenderId string) ([]*models.Attachment, error)
+	ListBidAttachments(ctx context.Context, bidId string) ([]*models.Attachment, error)
+	DeleteAttachment(ctx context.Context, id string) error
+	// ListAttachmentsFor returns the attachments of any of the tenders or
+	// bids, including bid attachments of the tenders.
+	ListAttachmentsFor(ctx context.Context, tenderIds []string, bidIds []string) ([]*models.Attachment, error)
+	// PurgeAttachments removes attachment records; their files are the
+	// caller's to remove.
+	PurgeAttachments(ctx context.Context, ids []string) (int64, error)
+}
@@ -0,0 +1,18 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/zohirovs/internal/models"
+)
+
+// AuditRepo is append-only: entries cannot be changed or removed through it.
+type AuditRepo interface {
+	// Append assigns the entry the next Seq, links it to the previous entry
+	// and stores it.
+	Append(ctx context.Context, entry *models.AuditEntry) (*models.AuditEntry, error)
+	// Query returns matching entries, newest first.
+	Query(ctx context.Context, query *models.AuditQuery) ([]*models.AuditEntry, error)
+	// Walk calls fn for every entry in Seq order until fn returns an error.
+	Walk(ctx context.Context, fn func(*models.AuditEntry) error) error
+}
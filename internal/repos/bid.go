@@ -2,6 +2,7 @@ package repos
 
 import (
 	"context"
+	"time"
 
 	"github.com/zohirovs/internal/models"
 )
@@ -10,6 +11,17 @@ type BidRepo interface {
 	CreateBid(ctx context.Context, bid *models.Bid) (*models.Bid, error)
 	GetBid(ctx context.Context, id string) (*models.Bid, error)
 	ListBidsForTender(ctx context.Context, tenderId string, filter map[string]interface{}) ([]*models.Bid, error)
+	GetBidSummary(ctx context.Context, tenderId string) (*models.BidSummary, error)
 	UpdateBidStatus(ctx context.Context, bidId string, status string) error
 	ListBidsByContractor(ctx context.Context, contractorId string) ([]*models.Bid, error)
+	ListBidsByOrganization(ctx context.Context, orgId string) ([]*models.Bid, error)
+
+	// Soft deletion. Deleted bids are left out of every other method.
+	DeleteBid(ctx context.Context, bidId string, at time.Time) error
+	DeleteBidsForTender(ctx context.Context, tenderId string, at time.Time) error
+	GetDeletedBid(ctx context.Context, id string) (*models.Bid, error)
+	RestoreBid(ctx context.Context, bidId string) error
+	RestoreBidsForTender(ctx context.Context, tenderId string, deletedAt time.Time) (int64, error)
+	ListPurgeableBids(ctx context.Context, cutoff time.Time) ([]string, error)
+	PurgeDeletedBids(ctx context.Context, cutoff time.Time) (int64, error)
 }
@@ -0,0 +1,15 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/zohirovs/internal/models"
+)
+
+type CategoryRepo interface {
+	CreateCategory(ctx context.Context, category *models.Category) (*models.Category, error)
+	GetCategory(ctx context.Context, id string) (*models.Category, error)
+	ListCategories(ctx context.Context) ([]*models.Category, error)
+	DeleteCategory(ctx context.Context, id string) error
+	HasChildren(ctx context.Context, id string) (bool, error)
+}
@@ -0,0 +1,14 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/zohirovs/internal/models"
+)
+
+type ContractorRepo interface {
+	UpsertProfile(ctx context.Context, profile *models.ContractorProfile) (*models.ContractorProfile, error)
+	GetProfile(ctx context.Context, userId string) (*models.ContractorProfile, error)
+	ListProfiles(ctx context.Context, status models.VerificationStatus) ([]*models.ContractorProfile, error)
+	UpdateVerification(ctx context.Context, userId string, status models.VerificationStatus, note string, verifiedBy string) (*models.ContractorProfile, error)
+}
@@ -0,0 +1,27 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/zohirovs/internal/models"
+)
+
+type InvitationRepo interface {
+	// CreateInvitation is idempotent per tender and invitee: inviting the same
+	// contractor or email again returns the existing invitation. A TokenHash
+	// replaces the stored one while the invitation is unclaimed.
+	CreateInvitation(ctx context.Context, invitation *models.Invitation) (*models.Invitation, error)
+	GetInvitation(ctx context.Context, id string) (*models.Invitation, error)
+	// FindInvitation returns the contractor's invitation to the tender, or nil
+	// if there is none.
+	FindInvitation(ctx context.Context, tenderId string, contractorId string) (*models.Invitation, error)
+	ListTenderInvitations(ctx context.Context, tenderId string) ([]*models.Invitation, error)
+	ListContractorInvitations(ctx context.Context, contractorId string) ([]*models.Invitation, error)
+	// RespondInvitation records the answer and binds email-only invitations to
+	// the responding contractor, consuming their token. It reports not found
+	// if the invitation was claimed by someone else meanwhile.
+	RespondInvitation(ctx context.Context, id string, contractorId string, status models.InvitationStatus) (*models.Invitation, error)
+	// PurgeInvitations removes invitations to any of the tenders or of any
+	// of the contractors.
+	PurgeInvitations(ctx context.Context, tenderIds []string, contractorIds []string) (int64, error)
+}
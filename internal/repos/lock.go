@@ -0,0 +1,19 @@
+package repos
+
+import (
+	"context"
+	"time"
+)
+
+// LockRepo hands out named locks shared by every instance, so periodic jobs
+// run on one instance at a time.
+type LockRepo interface {
+	// AcquireLock takes the named lock for owner, taking it over if its
+	// holder has not refreshed it for staleAfter. It reports false when
+	// someone else holds the lock.
+	AcquireLock(ctx context.Context, name string, owner string, staleAfter time.Duration) (bool, error)
+	// RefreshLock marks the lock as still in use; it reports false if owner
+	// no longer holds it.
+	RefreshLock(ctx context.Context, name string, owner string) (bool, error)
+	ReleaseLock(ctx context.Context, name string, owner string) error
+}
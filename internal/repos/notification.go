@@ -1,4 +1,18 @@
 package repos
 
+import (
+	"context"
+
+	"github.com/zohirovs/internal/models"
+)
+
 type NotificationRepo interface {
+	CreateNotifications(ctx context.Context, notifications []*models.Notification) error
+	ListNotifications(ctx context.Context, userId string, unreadOnly bool) ([]*models.Notification, error)
+	MarkRead(ctx context.Context, userId string, notificationId string) error
+	ListPendingDigest(ctx context.Context) ([]*models.Notification, error)
+	MarkEmailed(ctx context.Context, notificationIds []string) error
+	// PurgeNotifications removes notifications about any of the tenders or
+	// addressed to any of the users.
+	PurgeNotifications(ctx context.Context, tenderIds []string, userIds []string) (int64, error)
 }
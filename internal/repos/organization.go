@@ -0,0 +1,35 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/zohirovs/internal/models"
+)
+
+type OrganizationRepo interface {
+	CreateOrganization(ctx context.Context, org *models.Organization) (*models.Organization, error)
+	GetOrganization(ctx context.Context, id string) (*models.Organization, error)
+	ListOrganizations(ctx context.Context, ids []string) ([]*models.Organization, error)
+
+	// AddMember adds the user to the organization, or changes their role if
+	// they already are a member.
+	AddMember(ctx context.Context, member *models.OrganizationMember) error
+	// GetMember returns the user's membership, or nil if they are not a member.
+	GetMember(ctx context.Context, orgId string, userId string) (*models.OrganizationMember, error)
+	ListMembers(ctx context.Context, orgId string) ([]*models.OrganizationMember, error)
+	ListMemberships(ctx context.Context, userId string) ([]*models.OrganizationMember, error)
+	UpdateMemberRole(ctx context.Context, orgId string, userId string, role models.OrgRole) error
+	RemoveMember(ctx context.Context, orgId string, userId string) error
+	CountOwners(ctx context.Context, orgId string) (int64, error)
+
+	// CreateMemberInvite is idempotent per organization and email while the
+	// invite is pending; inviting again updates its role.
+	CreateMemberInvite(ctx context.Context, invite *models.MemberInvite) (*models.MemberInvite, error)
+	GetMemberInvite(ctx context.Context, id string) (*models.MemberInvite, error)
+	ListMemberInvites(ctx context.Context, orgId string) ([]*models.MemberInvite, error)
+	// ListUserInvites lists the invites the user has answered.
+	ListUserInvites(ctx context.Context, userId string) ([]*models.MemberInvite, error)
+	// RespondMemberInvite records the user's answer to a pending invite and
+	// clears its token.
+	RespondMemberInvite(ctx context.Context, id string, userId string, status models.MemberInviteStatus) (*models.MemberInvite, error)
+}
@@ -0,0 +1,18 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/zohirovs/internal/models"
+)
+
+type QuestionRepo interface {
+	CreateQuestion(ctx context.Context, question *models.Question) (*models.Question, error)
+	GetQuestion(ctx context.Context, id string) (*models.Question, error)
+	// ListQuestions returns a tender's questions, oldest first.
+	ListQuestions(ctx context.Context, tenderId string) ([]*models.Question, error)
+	// AnswerQuestion sets the answer and publishes it when publish is true.
+	AnswerQuestion(ctx context.Context, id string, answer string, publish bool) (*models.Question, error)
+	// PurgeQuestions removes every question on the tenders.
+	PurgeQuestions(ctx context.Context, tenderIds []string) (int64, error)
+}
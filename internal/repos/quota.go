@@ -0,0 +1,15 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/zohirovs/internal/models"
+)
+
+type QuotaRepo interface {
+	// GetOverride returns nil without an error when the user has no override.
+	GetOverride(ctx context.Context, userID string) (*models.QuotaOverride, error)
+	SetOverride(ctx context.Context, override *models.QuotaOverride) (*models.QuotaOverride, error)
+	DeleteOverride(ctx context.Context, userID string) error
+	ListOverrides(ctx context.Context) ([]*models.QuotaOverride, error)
+}
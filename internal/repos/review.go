@@ -0,0 +1,22 @@
+package repos
+
+import (
+	"context"
+	"errors"
+
+	"github.com/zohirovs/internal/models"
+)
+
+// ErrDuplicateReview is returned when the contractor already has a review for
+// the tender.
+var ErrDuplicateReview = errors.New("contractor has already been reviewed for this tender")
+
+type ReviewRepo interface {
+	CreateReview(ctx context.Context, review *models.Review) (*models.Review, error)
+	ListReviewsByContractor(ctx context.Context, contractorId string) ([]*models.Review, error)
+	GetReputation(ctx context.Context, contractorId string) (*models.Reputation, error)
+	GetReputations(ctx context.Context, contractorIds []string) (map[string]*models.Reputation, error)
+	// PurgeReviews removes the tenders' reviews and rebuilds the reviewed
+	// contractors' reputations without them.
+	PurgeReviews(ctx context.Context, tenderIds []string) (int64, error)
+}
@@ -0,0 +1,18 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/zohirovs/internal/models"
+)
+
+type SavedSearchRepo interface {
+	CreateSavedSearch(ctx context.Context, search *models.SavedSearch) (*models.SavedSearch, error)
+	ListSavedSearches(ctx context.Context, userId string) ([]*models.SavedSearch, error)
+	DeleteSavedSearch(ctx context.Context, userId string, searchId string) error
+	// FindCandidates narrows searches by category and budget; callers apply
+	// SavedSearch.Matches for the remaining criteria.
+	FindCandidates(ctx context.Context, tender *models.Tender) ([]*models.SavedSearch, error)
+	// PurgeSavedSearches removes every saved search of the users.
+	PurgeSavedSearches(ctx context.Context, userIds []string) (int64, error)
+}
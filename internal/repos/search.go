@@ -0,0 +1,20 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/zohirovs/internal/models"
+)
+
+// TenderSearchIndex is a full-text index of tenders. It is kept separate from
+// TenderRepo so the MongoDB implementation can be replaced by a dedicated
+// search engine.
+type TenderSearchIndex interface {
+	IndexTender(ctx context.Context, tender *models.Tender) error
+	// SetTenderAccess records the contractors allowed to find an invite-only tender.
+	SetTenderAccess(ctx context.Context, id string, contractorIds []string) error
+	RemoveTender(ctx context.Context, id string) error
+	// SearchTenders only returns invite-only tenders the query's viewer owns
+	// or is invited to.
+	SearchTenders(ctx context.Context, query *models.TenderSearchQuery) (*models.TenderSearchResult, error)
+}
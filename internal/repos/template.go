@@ -0,0 +1,17 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/zohirovs/internal/models"
+)
+
+// TemplateRepo stores tender templates. Reads and writes are scoped to the
+// owning client.
+type TemplateRepo interface {
+	CreateTemplate(ctx context.Context, template *models.TenderTemplate) (*models.TenderTemplate, error)
+	GetTemplate(ctx context.Context, clientId string, id string) (*models.TenderTemplate, error)
+	ListTemplates(ctx context.Context, clientId string) ([]*models.TenderTemplate, error)
+	UpdateTemplate(ctx context.Context, template *models.TenderTemplate) (*models.TenderTemplate, error)
+	DeleteTemplate(ctx context.Context, clientId string, id string) error
+}
@@ -3,6 +3,7 @@ package repos
 
 import (
 	"context"
+	"time"
 
 	"github.com/zohirovs/internal/models"
 )
@@ -11,6 +12,18 @@ type TenderRepo interface {
 	CreateTender(ctx context.Context, tender *models.Tender) (*models.Tender, error)
 	GetTender(ctx context.Context, id string) (*models.Tender, error)
 	UpdateTender(ctx context.Context, tender *models.Tender) (*models.Tender, error)
-	DeleteTender(ctx context.Context, id string) error
+	// DeleteTender soft-deletes a tender; GetTender and the other methods
+	// then treat it as missing until it is restored.
+	DeleteTender(ctx context.Context, id string, at time.Time) error
+	GetDeletedTender(ctx context.Context, id string) (*models.Tender, error)
+	RestoreTender(ctx context.Context, id string) error
+	// ListPurgeableTenders returns the IDs of tenders soft-deleted at or
+	// before cutoff, so their dependents can be purged first.
+	ListPurgeableTenders(ctx context.Context, cutoff time.Time) ([]string, error)
+	// PurgeDeletedTenders removes tenders soft-deleted at or before cutoff.
+	PurgeDeletedTenders(ctx context.Context, cutoff time.Time) (int64, error)
 	UpdateStatus(ctx context.Context, id string, status models.Status) error
+	ListTendersByOrganization(ctx context.Context, orgId string) ([]*models.Tender, error)
+	// ResolveLot sets the outcome of a lot that is still open.
+	ResolveLot(ctx context.Context, tenderId string, lot *models.Lot) error
 }
@@ -2,10 +2,16 @@ package repos
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/zohirovs/internal/models"
 )
 
+// ErrDuplicateUser is returned when registering an email or username that
+// another user, possibly a soft-deleted one, already has.
+var ErrDuplicateUser = errors.New("email or username is already taken")
+
 type UserRepo interface {
 	// 1
 	RegisterUser(ctx context.Context, user *models.User) (string, error)
@@ -23,4 +29,14 @@ type UserRepo interface {
 	Login(ctx context.Context, login *models.LoginRequest) (string, error)
 
 	GetUserByUsername(ctx context.Context, username string) (*models.User, error)
+	// GetCredentials is GetUserByUsername with the password hash, which
+	// cached users never carry.
+	GetCredentials(ctx context.Context, username string) (*models.User, error)
+
+	// DeleteUser soft-deletes a user; the lookups above then treat it as
+	// missing until it is restored.
+	DeleteUser(ctx context.Context, userID string) error
+	RestoreUser(ctx context.Context, userID string) error
+	ListPurgeableUsers(ctx context.Context, cutoff time.Time) ([]string, error)
+	PurgeDeletedUsers(ctx context.Context, cutoff time.Time) (int64, error)
 }
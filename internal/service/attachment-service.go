@@ -0,0 +1,290 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zohirovs/internal/config"
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/repos"
+	"github.com/zohirovs/internal/storage/blob"
+	"github.com/zohirovs/internal/tracing"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// allowedContentTypes lists the sniffed content types accepted for upload.
+// Office documents (docx, xlsx) are zip containers and sniff as application/zip.
+var allowedContentTypes = map[string]bool{
+	"application/pdf":           true,
+	"application/zip":           true,
+	"image/png":                 true,
+	"image/jpeg":                true,
+	"text/plain; charset=utf-8": true,
+}
+
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+type AttachmentService struct {
+	attachmentRepo repos.AttachmentRepo
+	tenderRepo     repos.TenderRepo
+	bidRepo        repos.BidRepo
+	files          blob.Store
+	orgs           *OrganizationService
+	audit          *AuditService
+	cfg            config.AttachmentConfig
+	logger         *slog.Logger
+}
+
+func NewAttachmentService(attachmentRepo repos.AttachmentRepo, tenderRepo repos.TenderRepo, bidRepo repos.BidRepo, files blob.Store, orgs *OrganizationService, audit *AuditService, cfg config.AttachmentConfig, logger *slog.Logger) *AttachmentService {
+	return &AttachmentService{
+		attachmentRepo: attachmentRepo,
+		tenderRepo:     tenderRepo,
+		bidRepo:        bidRepo,
+		files:          files,
+		orgs:           orgs,
+		audit:          audit,
+		cfg:            cfg,
+		logger:         logger,
+	}
+}
+
+// MaxSize is the largest accepted upload in bytes.
+func (s *AttachmentService) MaxSize() int64 {
+	return s.cfg.MaxSize
+}
+
+// UploadTenderAttachment stores a document on a tender. Only those who manage the tender may upload.
+func (s *AttachmentService) UploadTenderAttachment(ctx context.Context, userID, tenderID, fileName string, size int64, content io.Reader) (_ *models.Attachment, err error) {
+	ctx, span := tracing.Start(ctx, "AttachmentService.UploadTenderAttachment")
+	defer tracing.End(span, &err)
+
+	tender, err := s.tenderRepo.GetTender(ctx, tenderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tender: %w", err)
+	}
+	if err := s.orgs.CanManageTender(ctx, userID, tender); err != nil {
+		return nil, err
+	}
+
+	attachment := &models.Attachment{
+		TenderId: tenderID,
+		OwnerId:  userID,
+	}
+	return s.store(ctx, attachment, fileName, size, content)
+}
+
+// UploadBidAttachment stores a technical proposal on a bid. Only those who manage the bid may upload.
+func (s *AttachmentService) UploadBidAttachment(ctx context.Context, userID, bidID, fileName string, size int64, content io.Reader) (_ *models.Attachment, err error) {
+	ctx, span := tracing.Start(ctx, "AttachmentService.UploadBidAttachment")
+	defer tracing.End(span, &err)
+
+	bid, err := s.bidRepo.GetBid(ctx, bidID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bid: %w", err)
+	}
+	if err := s.orgs.CanManageBid(ctx, userID, bid); err != nil {
+		return nil, err
+	}
+
+	attachment := &models.Attachment{
+		TenderId: bid.TenderId,
+		BidId:    bidID,
+		OwnerId:  userID,
+	}
+	return s.store(ctx, attachment, fileName, size, content)
+}
+
+// ListTenderAttachments lists a tender's documents for its owner and bidders.
+func (s *AttachmentService) ListTenderAttachments(ctx context.Context, userID, tenderID string) (_ []*models.Attachment, err error) {
+	ctx, span := tracing.Start(ctx, "AttachmentService.ListTenderAttachments")
+	defer tracing.End(span, &err)
+
+	tender, err := s.tenderRepo.GetTender(ctx, tenderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tender: %w", err)
+	}
+	if err := s.authorizeTender(ctx, userID, tender); err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.attachmentRepo.ListTenderAttachments(ctx, tenderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+	return attachments, nil
+}
+
+// SignedURL returns a short-lived download link for an attachment the user may read.
+func (s *AttachmentService) SignedURL(ctx context.Context, userID, attachmentID string) (_ *models.AttachmentURL, err error) {
+	ctx, span := tracing.Start(ctx, "AttachmentService.SignedURL")
+	defer tracing.End(span, &err)
+
+	attachment, err := s.attachmentRepo.GetAttachment(ctx, attachmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	tender, err := s.tenderRepo.GetTender(ctx, attachment.TenderId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tender: %w", err)
+	}
+
+	if attachment.BidId != "" {
+		// Technical proposals are visible to the tender's and the bid's owners only.
+		if err := s.authorizeBid(ctx, userID, tender, attachment.BidId); err != nil {
+			return nil, err
+		}
+	} else if err := s.authorizeTender(ctx, userID, tender); err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(s.cfg.URLTTL).UTC().Truncate(time.Second)
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	return &models.AttachmentURL{
+		URL:       fmt.Sprintf("/api/attachments/%s/download?expires=%s&signature=%s", attachmentID, expires, s.sign(attachmentID, expires)),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// Open verifies a signed download link and returns the attachment content.
+// The caller must close the returned reader.
+func (s *AttachmentService) Open(ctx context.Context, attachmentID, expires, signature string) (_ *models.Attachment, _ io.ReadCloser, err error) {
+	ctx, span := tracing.Start(ctx, "AttachmentService.Open")
+	defer tracing.End(span, &err)
+
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return nil, nil, ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(attachmentID, expires))) {
+		return nil, nil, ErrInvalidSignature
+	}
+
+	attachment, err := s.attachmentRepo.GetAttachment(ctx, attachmentID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	content, err := s.files.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	return attachment, content, nil
+}
+
+// authorizeTender allows the tender's owners and any contractor who has bid on it.
+func (s *AttachmentService) authorizeTender(ctx context.Context, userID string, tender *models.Tender) error {
+	if userID == "" {
+		return ErrForbidden
+	}
+	if err := s.orgs.CanViewTender(ctx, userID, tender); err == nil {
+		return nil
+	} else if !errors.Is(err, ErrForbidden) {
+		return err
+	}
+
+	bids, err := s.bidRepo.ListBidsForTender(ctx, tender.TenderId, map[string]interface{}{"contractor_id": userID})
+	if err != nil {
+		return fmt.Errorf("failed to check bids: %w", err)
+	}
+	if len(bids) == 0 {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// authorizeBid allows the tender's owners and the bid's owners.
+func (s *AttachmentService) authorizeBid(ctx context.Context, userID string, tender *models.Tender, bidID string) error {
+	if err := s.orgs.CanViewTender(ctx, userID, tender); err == nil {
+		return nil
+	} else if !errors.Is(err, ErrForbidden) {
+		return err
+	}
+
+	bid, err := s.bidRepo.GetBid(ctx, bidID)
+	if err != nil {
+		return fmt.Errorf("failed to get bid: %w", err)
+	}
+	return s.orgs.CanViewBid(ctx, userID, bid)
+}
+
+// store sniffs, checksums and writes the upload, then records its metadata.
+func (s *AttachmentService) store(ctx context.Context, attachment *models.Attachment, fileName string, size int64, content io.Reader) (*models.Attachment, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("%w: empty file", ErrUnsupportedFileType)
+	}
+	if size > s.cfg.MaxSize {
+		return nil, ErrFileTooLarge
+	}
+
+	reader := bufio.NewReaderSize(content, 512)
+	head, err := reader.Peek(512)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	contentType := http.DetectContentType(head)
+	if !allowedContentTypes[contentType] {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFileType, contentType)
+	}
+
+	attachment.AttachmentId = primitive.NewObjectID().Hex()
+	attachment.FileName = path.Base(fileName)
+	attachment.ContentType = contentType
+	attachment.Size = size
+	attachment.StorageKey = s.storageKey(attachment)
+
+	hash := sha256.New()
+	body := io.TeeReader(io.LimitReader(reader, size), hash)
+	if err := s.files.Put(ctx, attachment.StorageKey, body, size, contentType); err != nil {
+		s.logger.ErrorContext(ctx, "failed to store attachment",
+			"error", err,
+			"attachment_id", attachment.AttachmentId)
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+	attachment.Checksum = hex.EncodeToString(hash.Sum(nil))
+
+	created, err := s.attachmentRepo.CreateAttachment(ctx, attachment)
+	if err != nil {
+		if delErr := s.files.Delete(ctx, attachment.StorageKey); delErr != nil {
+			s.logger.WarnContext(ctx, "failed to clean up orphaned attachment",
+				"error", delErr,
+				"storage_key", attachment.StorageKey)
+		}
+		return nil, fmt.Errorf("failed to save attachment: %w", err)
+	}
+
+	s.audit.Record(ctx, models.AuditAttachmentUploaded, "attachment", created.AttachmentId, nil, created)
+	return created, nil
+}
+
+func (s *AttachmentService) storageKey(attachment *models.Attachment) string {
+	name := strings.Trim(unsafeFileNameChars.ReplaceAllString(attachment.FileName, "_"), "._")
+	if name == "" {
+		name = "file"
+	}
+
+	if attachment.BidId != "" {
+		return path.Join("bids", attachment.BidId, attachment.AttachmentId, name)
+	}
+	return path.Join("tenders", attachment.TenderId, attachment.AttachmentId, name)
+}
+
+func (s *AttachmentService) sign(attachmentID, expires string) string {
+	mac := hmac.New(sha256.New, []byte(s.cfg.SigningKey))
+	mac.Write([]byte(attachmentID + ":" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zohirovs/internal/config"
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/repos"
+	"github.com/zohirovs/internal/storage/blob"
+)
+
+// attachmentRepoStub serves a fixed set of attachments.
+type attachmentRepoStub struct {
+	repos.AttachmentRepo
+	attachments map[string]*models.Attachment
+}
+
+func (r *attachmentRepoStub) GetAttachment(ctx context.Context, id string) (*models.Attachment, error) {
+	if a, ok := r.attachments[id]; ok {
+		return a, nil
+	}
+	return nil, fmt.Errorf("attachment not found: %s", id)
+}
+
+type tenderRepoStub struct {
+	repos.TenderRepo
+	tender *models.Tender
+}
+
+func (r *tenderRepoStub) GetTender(ctx context.Context, id string) (*models.Tender, error) {
+	if id != r.tender.TenderId {
+		return nil, fmt.Errorf("tender not found: %s", id)
+	}
+	return r.tender, nil
+}
+
+// bidRepoStub has no bids.
+type bidRepoStub struct {
+	repos.BidRepo
+}
+
+func (bidRepoStub) ListBidsForTender(ctx context.Context, tenderId string, filter map[string]interface{}) ([]*models.Bid, error) {
+	return nil, nil
+}
+
+func newSignedURLService(t *testing.T) *AttachmentService {
+	t.Helper()
+	files, err := blob.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"a1", "a2"} {
+		if err := files.Put(context.Background(), "tenders/t1/"+id, strings.NewReader("content of "+id), 0, "text/plain"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	repo := &attachmentRepoStub{attachments: map[string]*models.Attachment{
+		"a1": {AttachmentId: "a1", TenderId: "t1", StorageKey: "tenders/t1/a1"},
+		"a2": {AttachmentId: "a2", TenderId: "t1", StorageKey: "tenders/t1/a2"},
+	}}
+	cfg := config.AttachmentConfig{SigningKey: "signing-key", URLTTL: 15 * time.Minute}
+	tenders := &tenderRepoStub{tender: &models.Tender{TenderId: "t1", ClientId: "owner"}}
+	return NewAttachmentService(repo, tenders, bidRepoStub{}, files, &OrganizationService{}, nil, cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+// link returns the expires and signature parameters of a signed URL for id
+// that expires at the given time.
+func link(s *AttachmentService, id string, expiresAt time.Time) (string, string) {
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	return expires, s.sign(id, expires)
+}
+
+func TestAttachmentSignedURL(t *testing.T) {
+	s := newSignedURLService(t)
+	before := time.Now()
+
+	signed, err := s.SignedURL(context.Background(), "owner", "a1")
+	if err != nil {
+		t.Fatalf("SignedURL: %v", err)
+	}
+	if ttl := signed.ExpiresAt.Sub(before); ttl < s.cfg.URLTTL-time.Second || ttl > s.cfg.URLTTL {
+		t.Errorf("link expires in %v, want %v", ttl, s.cfg.URLTTL)
+	}
+
+	u, err := url.Parse(signed.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Path != "/api/attachments/a1/download" {
+		t.Errorf("path = %q", u.Path)
+	}
+	query := u.Query()
+	if query.Get("expires") != strconv.FormatInt(signed.ExpiresAt.Unix(), 10) {
+		t.Errorf("expires = %q, want %d", query.Get("expires"), signed.ExpiresAt.Unix())
+	}
+
+	_, content, err := s.Open(context.Background(), "a1", query.Get("expires"), query.Get("signature"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	content.Close()
+
+	if _, err := s.SignedURL(context.Background(), "stranger", "a1"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("SignedURL for a stranger = %v, want ErrForbidden", err)
+	}
+	if _, err := s.SignedURL(context.Background(), "", "a1"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("SignedURL without a user = %v, want ErrForbidden", err)
+	}
+}
+
+func TestAttachmentOpenValidLink(t *testing.T) {
+	s := newSignedURLService(t)
+	expires, signature := link(s, "a1", time.Now().Add(time.Minute))
+
+	attachment, content, err := s.Open(context.Background(), "a1", expires, signature)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer content.Close()
+	if attachment.AttachmentId != "a1" {
+		t.Errorf("attachment = %q", attachment.AttachmentId)
+	}
+	if got, _ := io.ReadAll(content); string(got) != "content of a1" {
+		t.Errorf("content = %q", got)
+	}
+}
+
+func TestAttachmentOpenExpired(t *testing.T) {
+	s := newSignedURLService(t)
+	expires, signature := link(s, "a1", time.Now().Add(-time.Second))
+
+	if _, _, err := s.Open(context.Background(), "a1", expires, signature); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Open of an expired link = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestAttachmentOpenTampered(t *testing.T) {
+	s := newSignedURLService(t)
+	expiresAt := time.Now().Add(time.Minute)
+	expires, signature := link(s, "a1", expiresAt)
+	later := strconv.FormatInt(expiresAt.Add(24*time.Hour).Unix(), 10)
+
+	other := NewAttachmentService(s.attachmentRepo, nil, nil, s.files, nil, nil,
+		config.AttachmentConfig{SigningKey: "other-key", URLTTL: time.Minute}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	_, forged := link(other, "a1", expiresAt)
+
+	for _, tc := range []struct {
+		name, id, expires, signature string
+	}{
+		{"other attachment", "a2", expires, signature},
+		{"extended expiry", "a1", later, signature},
+		{"flipped signature", "a1", expires, flip(signature)},
+		{"truncated signature", "a1", expires, signature[:len(signature)-2]},
+		{"empty signature", "a1", expires, ""},
+		{"other key", "a1", expires, forged},
+		{"malformed expiry", "a1", expires + "x", signature},
+		{"empty expiry", "a1", "", signature},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, content, err := s.Open(context.Background(), tc.id, tc.expires, tc.signature)
+			if content != nil {
+				content.Close()
+			}
+			if !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("Open = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+}
+
+// flip changes the last hex digit of a signature.
+func flip(signature string) string {
+	last := signature[len(signature)-1]
+	replacement := byte('0')
+	if last == '0' {
+		replacement = '1'
+	}
+	return signature[:len(signature)-1] + string(replacement)
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/repos"
+	"github.com/zohirovs/internal/tracing"
+)
+
+// errChainBroken stops the verification walk at the first bad entry.
+var errChainBroken = errors.New("audit chain broken")
+
+type AuditService struct {
+	auditRepo repos.AuditRepo
+	logger    *slog.Logger
+}
+
+func NewAuditService(auditRepo repos.AuditRepo, logger *slog.Logger) *AuditService {
+	return &AuditService{
+		auditRepo: auditRepo,
+		logger:    logger,
+	}
+}
+
+// Record appends an entry for a completed state change. The actor, request
+// ID and IP come from the request context. Before and After are snapshots
+// of the resource and may be nil. The change has already happened, so a
+// failure to record it is logged rather than returned.
+func (s *AuditService) Record(ctx context.Context, action models.AuditAction, resourceType, resourceID string, before, after any) {
+	ctx, span := tracing.Start(ctx, "AuditService.Record")
+	defer span.End()
+
+	meta := models.RequestMetaFrom(ctx)
+	entry := &models.AuditEntry{
+		ActorId:      meta.ActorId,
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceId:   resourceID,
+		Before:       s.snapshot(before),
+		After:        s.snapshot(after),
+		RequestId:    meta.RequestId,
+		IP:           meta.IP,
+		At:           time.Now(),
+	}
+
+	// Finish recording even if the client has gone away.
+	if _, err := s.auditRepo.Append(context.WithoutCancel(ctx), entry); err != nil {
+		s.logger.ErrorContext(ctx, "failed to record audit entry",
+			"error", err,
+			"action", action,
+			"resource_type", resourceType,
+			"resource_id", resourceID,
+			"request_id", meta.RequestId)
+	}
+}
+
+func (s *AuditService) Query(ctx context.Context, query *models.AuditQuery) (_ []*models.AuditEntry, err error) {
+	ctx, span := tracing.Start(ctx, "AuditService.Query")
+	defer tracing.End(span, &err)
+
+	entries, err := s.auditRepo.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// Verify walks the chain from the first entry and reports the first one
+// whose hash, link to its predecessor or sequence number is wrong.
+// Dropping the newest entries leaves a valid shorter chain, so compare
+// Checked with earlier runs to detect truncation.
+func (s *AuditService) Verify(ctx context.Context) (_ *models.AuditVerification, err error) {
+	ctx, span := tracing.Start(ctx, "AuditService.Verify")
+	defer tracing.End(span, &err)
+
+	result := &models.AuditVerification{Valid: true}
+	var prev *models.AuditEntry
+
+	err = s.auditRepo.Walk(ctx, func(entry *models.AuditEntry) error {
+		var reason string
+		switch {
+		case prev == nil && entry.Seq != 1:
+			reason = fmt.Sprintf("chain starts at seq %d", entry.Seq)
+		case prev != nil && entry.Seq != prev.Seq+1:
+			reason = fmt.Sprintf("entries %d to %d are missing", prev.Seq+1, entry.Seq-1)
+		case prev == nil && entry.PrevHash != "":
+			reason = "first entry links to a predecessor"
+		case prev != nil && entry.PrevHash != prev.Hash:
+			reason = "link to previous entry does not match its hash"
+		case entry.Hash != entry.ComputeHash():
+			reason = "content does not match its hash"
+		}
+		if reason != "" {
+			result.Valid = false
+			result.BrokenSeq = entry.Seq
+			result.Reason = reason
+			return errChainBroken
+		}
+
+		result.Checked++
+		prev = entry
+		return nil
+	})
+	if err != nil && !errors.Is(err, errChainBroken) {
+		return nil, fmt.Errorf("failed to verify audit log: %w", err)
+	}
+
+	return result, nil
+}
+
+func (s *AuditService) snapshot(v any) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		s.logger.Warn("failed to snapshot resource for audit",
+			"error", err)
+		return nil
+	}
+	return data
+}
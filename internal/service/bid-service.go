@@ -4,27 +4,43 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"math"
 	"time"
 
+	"github.com/zohirovs/internal/metrics"
 	"github.com/zohirovs/internal/models"
 	"github.com/zohirovs/internal/repos"
+	"github.com/zohirovs/internal/tracing"
 )
 
 type BidService struct {
-	bidRepo    repos.BidRepo
-	tenderRepo repos.TenderRepo
-	logger     *slog.Logger
+	bidRepo     repos.BidRepo
+	tenderRepo  repos.TenderRepo
+	contractors *ContractorService
+	reputation  *ReputationService
+	invitations *InvitationService
+	orgs        *OrganizationService
+	audit       *AuditService
+	logger      *slog.Logger
 }
 
-func NewBidService(bidRepo repos.BidRepo, tenderRepo repos.TenderRepo, logger *slog.Logger) *BidService {
+func NewBidService(bidRepo repos.BidRepo, tenderRepo repos.TenderRepo, contractors *ContractorService, reputation *ReputationService, invitations *InvitationService, orgs *OrganizationService, audit *AuditService, logger *slog.Logger) *BidService {
 	return &BidService{
-		bidRepo:    bidRepo,
-		tenderRepo: tenderRepo,
-		logger:     logger,
+		bidRepo:     bidRepo,
+		tenderRepo:  tenderRepo,
+		contractors: contractors,
+		reputation:  reputation,
+		invitations: invitations,
+		orgs:        orgs,
+		audit:       audit,
+		logger:      logger,
 	}
 }
 
-func (s *BidService) CreateBid(ctx context.Context, bid *models.Bid) (*models.Bid, error) {
+func (s *BidService) CreateBid(ctx context.Context, bid *models.Bid) (_ *models.Bid, err error) {
+	ctx, span := tracing.Start(ctx, "BidService.CreateBid")
+	defer tracing.End(span, &err)
+
 	// Validate tender exists and is open
 	tender, err := s.tenderRepo.GetTender(ctx, bid.TenderId)
 	if err != nil {
@@ -35,13 +51,30 @@ func (s *BidService) CreateBid(ctx context.Context, bid *models.Bid) (*models.Bi
 		return nil, fmt.Errorf("tender is not open for bidding")
 	}
 
-	deadline, err := time.Parse(time.RFC3339, tender.Deadline)
-	if err != nil {
-		return nil, fmt.Errorf("invalid tender deadline format: %w", err)
+	if time.Now().After(tender.Deadline) {
+		return nil, fmt.Errorf("tender deadline has passed")
 	}
 
-	if time.Now().After(deadline) {
-		return nil, fmt.Errorf("tender deadline has passed")
+	if err := priceLines(tender, bid); err != nil {
+		return nil, err
+	}
+
+	if err := priceLots(tender, bid); err != nil {
+		return nil, err
+	}
+
+	if bid.OrganizationId != "" {
+		if err := s.orgs.CheckActingFor(ctx, bid.ContractorId, bid.OrganizationId, models.Contractor); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.invitations.CheckCanBid(ctx, tender, bid.ContractorId); err != nil {
+		return nil, err
+	}
+
+	if err := s.contractors.CheckQualification(ctx, bid.ContractorId, tender); err != nil {
+		return nil, err
 	}
 
 	// Create the bid
@@ -49,11 +82,16 @@ func (s *BidService) CreateBid(ctx context.Context, bid *models.Bid) (*models.Bi
 	if err != nil {
 		return nil, fmt.Errorf("failed to create bid: %w", err)
 	}
+	s.audit.Record(ctx, models.AuditBidCreated, "bid", createdBid.BidId, nil, createdBid)
+	metrics.BidsSubmitted.Inc()
 
 	return createdBid, nil
 }
 
-func (s *BidService) GetBid(ctx context.Context, id string) (*models.Bid, error) {
+func (s *BidService) GetBid(ctx context.Context, id string) (_ *models.Bid, err error) {
+	ctx, span := tracing.Start(ctx, "BidService.GetBid")
+	defer tracing.End(span, &err)
+
 	bid, err := s.bidRepo.GetBid(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get bid: %w", err)
@@ -61,26 +99,322 @@ func (s *BidService) GetBid(ctx context.Context, id string) (*models.Bid, error)
 	return bid, nil
 }
 
-func (s *BidService) ListBidsForTender(ctx context.Context, tenderId string, filter map[string]interface{}) ([]*models.Bid, error) {
+// ListBidsForTender lists a tender's bids for its owners.
+func (s *BidService) ListBidsForTender(ctx context.Context, clientID, tenderId string, filter map[string]interface{}) (_ []*models.Bid, err error) {
+	ctx, span := tracing.Start(ctx, "BidService.ListBidsForTender")
+	defer tracing.End(span, &err)
+
+	tender, err := s.tenderRepo.GetTender(ctx, tenderId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tender: %w", err)
+	}
+	if err := s.orgs.CanViewTender(ctx, clientID, tender); err != nil {
+		return nil, err
+	}
+
 	bids, err := s.bidRepo.ListBidsForTender(ctx, tenderId, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list bids: %w", err)
 	}
+
+	if err := s.reputation.AttachToBids(ctx, bids); err != nil {
+		// Reputation is supplementary; evaluation can proceed without it.
+		s.logger.WarnContext(ctx, "failed to attach contractor reputation to bids",
+			"error", err,
+			"tender_id", tenderId)
+	}
+
 	return bids, nil
 }
 
-func (s *BidService) UpdateBidStatus(ctx context.Context, bidId string, status string) error {
-	err := s.bidRepo.UpdateBidStatus(ctx, bidId, status)
+// SummarizeBids returns the bid count and lowest price of a tender for its
+// owners.
+func (s *BidService) SummarizeBids(ctx context.Context, clientID, tenderID string) (_ *models.BidSummary, err error) {
+	ctx, span := tracing.Start(ctx, "BidService.SummarizeBids")
+	defer tracing.End(span, &err)
+
+	tender, err := s.tenderRepo.GetTender(ctx, tenderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tender: %w", err)
+	}
+	if err := s.orgs.CanViewTender(ctx, clientID, tender); err != nil {
+		return nil, err
+	}
+
+	summary, err := s.bidRepo.GetBidSummary(ctx, tenderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize bids: %w", err)
+	}
+	return summary, nil
+}
+
+// CompareBids lays the tender's bids side by side per line item for its owners.
+func (s *BidService) CompareBids(ctx context.Context, clientID, tenderID string) (_ *models.BidComparison, err error) {
+	ctx, span := tracing.Start(ctx, "BidService.CompareBids")
+	defer tracing.End(span, &err)
+
+	tender, err := s.tenderRepo.GetTender(ctx, tenderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tender: %w", err)
+	}
+	if err := s.orgs.CanViewTender(ctx, clientID, tender); err != nil {
+		return nil, err
+	}
+
+	bids, err := s.bidRepo.ListBidsForTender(ctx, tenderID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bids: %w", err)
+	}
+
+	comparison := &models.BidComparison{
+		TenderId: tenderID,
+		Bids:     make([]models.ComparedBid, 0, len(bids)),
+		Rows:     []models.ComparisonRow{},
+	}
+
+	lines := make([]map[string]models.BidLine, len(bids))
+	for i, bid := range bids {
+		comparison.Bids = append(comparison.Bids, models.ComparedBid{
+			BidId:        bid.BidId,
+			ContractorId: bid.ContractorId,
+			Price:        bid.Price,
+			Status:       bid.Status,
+		})
+		lines[i] = make(map[string]models.BidLine, len(bid.Lines))
+		for _, line := range bid.Lines {
+			lines[i][line.ItemId] = line
+		}
+	}
+
+	for _, item := range tender.AllLineItems() {
+		row := models.ComparisonRow{
+			Item:  item,
+			Cells: make([]models.ComparisonCell, len(bids)),
+		}
+		lowest := math.Inf(1)
+		for i, bid := range bids {
+			line, ok := lines[i][item.ItemId]
+			if !ok {
+				continue
+			}
+			row.Cells[i] = models.ComparisonCell{UnitPrice: &line.UnitPrice, Total: &line.Total}
+			if line.UnitPrice < lowest {
+				lowest = line.UnitPrice
+				row.LowestBidId = bid.BidId
+			}
+		}
+		comparison.Rows = append(comparison.Rows, row)
+	}
+
+	return comparison, nil
+}
+
+func (s *BidService) UpdateBidStatus(ctx context.Context, bidId string, status string) (err error) {
+	ctx, span := tracing.Start(ctx, "BidService.UpdateBidStatus")
+	defer tracing.End(span, &err)
+
+	before, err := s.bidRepo.GetBid(ctx, bidId)
+	if err != nil {
+		return fmt.Errorf("failed to get bid: %w", err)
+	}
+
+	err = s.bidRepo.UpdateBidStatus(ctx, bidId, status)
 	if err != nil {
 		return fmt.Errorf("failed to update bid status: %w", err)
 	}
+
+	s.audit.Record(ctx, models.AuditBidStatusChanged, "bid", bidId,
+		map[string]string{"status": before.Status},
+		map[string]string{"status": status})
 	return nil
 }
 
-func (s *BidService) ListBidsByContractor(ctx context.Context, contractorId string) ([]*models.Bid, error) {
+func (s *BidService) ListBidsByContractor(ctx context.Context, contractorId string) (_ []*models.Bid, err error) {
+	ctx, span := tracing.Start(ctx, "BidService.ListBidsByContractor")
+	defer tracing.End(span, &err)
+
 	bids, err := s.bidRepo.ListBidsByContractor(ctx, contractorId)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list contractor bids: %w", err)
 	}
 	return bids, nil
 }
+
+// WithdrawBid soft-deletes a pending bid on an open tender at the request of
+// the contractor who manages it.
+func (s *BidService) WithdrawBid(ctx context.Context, userID, bidID string) (err error) {
+	ctx, span := tracing.Start(ctx, "BidService.WithdrawBid")
+	defer tracing.End(span, &err)
+
+	bid, err := s.bidRepo.GetBid(ctx, bidID)
+	if err != nil {
+		return fmt.Errorf("failed to get bid: %w", err)
+	}
+	if err := s.orgs.CanManageBid(ctx, userID, bid); err != nil {
+		return err
+	}
+
+	tender, err := s.tenderRepo.GetTender(ctx, bid.TenderId)
+	if err != nil {
+		return fmt.Errorf("failed to get tender: %w", err)
+	}
+	if bid.Status != "pending" || tender.Status != string(models.OPEN) {
+		return ErrBidNotWithdrawable
+	}
+
+	if err := s.bidRepo.DeleteBid(ctx, bidID, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to withdraw bid: %w", err)
+	}
+
+	s.audit.Record(ctx, models.AuditBidWithdrawn, "bid", bidID, bid, nil)
+	return nil
+}
+
+// RestoreBid undoes a withdrawal on an admin's request. Bids deleted with
+// their tender come back by restoring the tender.
+func (s *BidService) RestoreBid(ctx context.Context, bidID string) (_ *models.Bid, err error) {
+	ctx, span := tracing.Start(ctx, "BidService.RestoreBid")
+	defer tracing.End(span, &err)
+
+	deleted, err := s.bidRepo.GetDeletedBid(ctx, bidID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted bid: %w", err)
+	}
+	if _, err := s.tenderRepo.GetTender(ctx, deleted.TenderId); err != nil {
+		if _, derr := s.tenderRepo.GetDeletedTender(ctx, deleted.TenderId); derr == nil {
+			return nil, ErrTenderDeleted
+		}
+		return nil, fmt.Errorf("failed to get tender: %w", err)
+	}
+
+	if err := s.bidRepo.RestoreBid(ctx, bidID); err != nil {
+		return nil, fmt.Errorf("failed to restore bid: %w", err)
+	}
+
+	bid, err := s.bidRepo.GetBid(ctx, bidID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bid: %w", err)
+	}
+
+	s.audit.Record(ctx, models.AuditBidRestored, "bid", bidID, deleted, bid)
+	return bid, nil
+}
+
+// priceLots checks a bid's lot prices against the tender and sets the bid's
+// Price to their total. Tenders without lots take a single overall price.
+func priceLots(tender *models.Tender, bid *models.Bid) error {
+	if len(tender.Lots) == 0 {
+		if len(bid.LotPrices) > 0 {
+			return fmt.Errorf("%w: tender has no lots", ErrInvalidLots)
+		}
+		return nil
+	}
+	if len(bid.LotPrices) == 0 {
+		return fmt.Errorf("%w: bid must price at least one lot", ErrInvalidLots)
+	}
+
+	now := time.Now()
+	seen := make(map[string]bool)
+	total := 0.0
+	for _, price := range bid.LotPrices {
+		lot := tender.Lot(price.LotId)
+		switch {
+		case lot == nil:
+			return fmt.Errorf("%w: unknown lot %s", ErrInvalidLots, price.LotId)
+		case seen[price.LotId]:
+			return fmt.Errorf("%w: lot %s priced twice", ErrInvalidLots, price.LotId)
+		case lot.Status != models.LotOpen:
+			return fmt.Errorf("%w: lot %s is no longer open", ErrInvalidLots, price.LotId)
+		case now.After(tender.LotDeadline(lot)):
+			return fmt.Errorf("%w: lot %s deadline has passed", ErrInvalidLots, price.LotId)
+		}
+		seen[price.LotId] = true
+		total += price.Price
+	}
+
+	bid.Price = models.RoundMoney(total)
+	return nil
+}
+
+// moneyTolerance absorbs rounding differences between client and server totals.
+const moneyTolerance = 0.005
+
+// priceLines computes the totals of an itemized bid. Every item of the tender,
+// or of each lot the bid covers, must be priced. Totals the client supplied
+// must agree with the computed ones; the computed values are then stored.
+func priceLines(tender *models.Tender, bid *models.Bid) error {
+	if !tender.Itemized() {
+		if len(bid.Lines) > 0 {
+			return fmt.Errorf("%w: tender has no line items", ErrInvalidLines)
+		}
+		return nil
+	}
+	if len(bid.Lines) == 0 {
+		return fmt.Errorf("%w: tender requires an itemized bid", ErrInvalidLines)
+	}
+
+	items := make(map[string]models.LineItem)
+	for _, item := range tender.AllLineItems() {
+		items[item.ItemId] = item
+	}
+
+	priced := make(map[string]bool)
+	lotTotals := make(map[string]float64)
+	for i := range bid.Lines {
+		line := &bid.Lines[i]
+		item, ok := items[line.ItemId]
+		switch {
+		case !ok:
+			return fmt.Errorf("%w: unknown line item %s", ErrInvalidLines, line.ItemId)
+		case priced[line.ItemId]:
+			return fmt.Errorf("%w: line item %s priced twice", ErrInvalidLines, line.ItemId)
+		}
+		priced[line.ItemId] = true
+		line.Total = models.RoundMoney(line.UnitPrice * item.Quantity)
+		lotTotals[item.LotId] += line.Total
+	}
+
+	// An item must be priced if the bid covers its lot; items outside lots
+	// are always covered.
+	for _, item := range items {
+		if _, covered := lotTotals[item.LotId]; (covered || item.LotId == "") && !priced[item.ItemId] {
+			return fmt.Errorf("%w: line item %q is not priced", ErrInvalidLines, item.Description)
+		}
+	}
+
+	total := 0.0
+	for _, lotTotal := range lotTotals {
+		total += lotTotal
+	}
+	total = models.RoundMoney(total)
+	if bid.Price != 0 && math.Abs(bid.Price-total) > moneyTolerance {
+		return fmt.Errorf("%w: price %.2f does not equal the sum of lines %.2f", ErrInvalidLines, bid.Price, total)
+	}
+	bid.Price = total
+
+	if len(tender.Lots) == 0 {
+		return nil
+	}
+
+	given := make(map[string]float64)
+	for _, price := range bid.LotPrices {
+		given[price.LotId] = price.Price
+	}
+	if len(given) > 0 && len(given) != len(lotTotals) {
+		return fmt.Errorf("%w: lot prices do not match the priced lines", ErrInvalidLines)
+	}
+
+	bid.LotPrices = nil
+	for _, lot := range tender.Lots {
+		lotTotal, covered := lotTotals[lot.LotId]
+		if !covered {
+			continue
+		}
+		lotTotal = models.RoundMoney(lotTotal)
+		if price, ok := given[lot.LotId]; len(given) > 0 && (!ok || math.Abs(price-lotTotal) > moneyTolerance) {
+			return fmt.Errorf("%w: price for lot %q does not equal the sum of its lines", ErrInvalidLines, lot.Title)
+		}
+		bid.LotPrices = append(bid.LotPrices, models.LotPrice{LotId: lot.LotId, Price: lotTotal})
+	}
+	return nil
+}
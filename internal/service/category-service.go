@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/repos"
+	"github.com/zohirovs/internal/tracing"
+)
+
+type CategoryService struct {
+	categoryRepo repos.CategoryRepo
+	audit        *AuditService
+	logger       *slog.Logger
+}
+
+func NewCategoryService(categoryRepo repos.CategoryRepo, audit *AuditService, logger *slog.Logger) *CategoryService {
+	return &CategoryService{
+		categoryRepo: categoryRepo,
+		audit:        audit,
+		logger:       logger,
+	}
+}
+
+func (s *CategoryService) CreateCategory(ctx context.Context, req *models.CreateCategory) (_ *models.Category, err error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.CreateCategory")
+	defer tracing.End(span, &err)
+
+	category := &models.Category{
+		Name:     strings.TrimSpace(req.Name),
+		Slug:     strings.ToLower(strings.TrimSpace(req.Slug)),
+		ParentId: req.ParentId,
+		Path:     []string{},
+	}
+
+	if req.ParentId != "" {
+		parent, err := s.categoryRepo.GetCategory(ctx, req.ParentId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get parent category: %w", err)
+		}
+		category.Path = parent.Lineage()
+	}
+
+	created, err := s.categoryRepo.CreateCategory(ctx, category)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create category: %w", err)
+	}
+	s.audit.Record(ctx, models.AuditCategoryCreated, "category", created.CategoryId, nil, created)
+	return created, nil
+}
+
+func (s *CategoryService) ListCategories(ctx context.Context) (_ []*models.Category, err error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.ListCategories")
+	defer tracing.End(span, &err)
+
+	categories, err := s.categoryRepo.ListCategories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	return categories, nil
+}
+
+// DeleteCategory removes a leaf category. Categories with children must be
+// emptied first so the taxonomy never has dangling parents.
+func (s *CategoryService) DeleteCategory(ctx context.Context, id string) (err error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.DeleteCategory")
+	defer tracing.End(span, &err)
+
+	hasChildren, err := s.categoryRepo.HasChildren(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+	if hasChildren {
+		return ErrCategoryHasChildren
+	}
+
+	before, err := s.categoryRepo.GetCategory(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+
+	if err := s.categoryRepo.DeleteCategory(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+	s.audit.Record(ctx, models.AuditCategoryDeleted, "category", id, before, nil)
+	return nil
+}
+
+// Lineage resolves a category ID to the category and its ancestors.
+func (s *CategoryService) Lineage(ctx context.Context, id string) (_ []string, err error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.Lineage")
+	defer tracing.End(span, &err)
+
+	category, err := s.categoryRepo.GetCategory(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+	return category.Lineage(), nil
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/repos"
+	"github.com/zohirovs/internal/storage/redis"
+	"github.com/zohirovs/internal/tracing"
+)
+
+type ContractorService struct {
+	contractorRepo  repos.ContractorRepo
+	contractorCache *redis.ContractorCaching
+	audit           *AuditService
+	logger          *slog.Logger
+}
+
+func NewContractorService(contractorRepo repos.ContractorRepo, cache *redis.ContractorCaching, audit *AuditService, logger *slog.Logger) *ContractorService {
+	return &ContractorService{
+		contractorRepo:  contractorRepo,
+		contractorCache: cache,
+		audit:           audit,
+		logger:          logger,
+	}
+}
+
+// SaveProfile creates or updates the contractor's own profile. Saving resets
+// the verification status to pending until an admin reviews it again.
+func (s *ContractorService) SaveProfile(ctx context.Context, userID string, req *models.UpsertContractorProfile) (_ *models.ContractorProfile, err error) {
+	ctx, span := tracing.Start(ctx, "ContractorService.SaveProfile")
+	defer tracing.End(span, &err)
+
+	profile := &models.ContractorProfile{
+		UserId:             userID,
+		CompanyName:        strings.TrimSpace(req.CompanyName),
+		RegistrationNumber: strings.TrimSpace(req.RegistrationNumber),
+		Categories:         req.Categories,
+		Regions:            req.Regions,
+		Certifications:     req.Certifications,
+	}
+
+	before, _ := s.contractorRepo.GetProfile(ctx, userID)
+
+	saved, err := s.contractorRepo.UpsertProfile(ctx, profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save contractor profile: %w", err)
+	}
+	s.audit.Record(ctx, models.AuditContractorProfileSaved, "contractor_profile", userID, before, saved)
+
+	s.cache(ctx, saved)
+	return saved, nil
+}
+
+func (s *ContractorService) GetProfile(ctx context.Context, userID string) (_ *models.ContractorProfile, err error) {
+	ctx, span := tracing.Start(ctx, "ContractorService.GetProfile")
+	defer tracing.End(span, &err)
+
+	if s.contractorCache != nil {
+		profile, err := s.contractorCache.Get(ctx, userID)
+		if err == nil {
+			return profile, nil
+		}
+		s.logger.DebugContext(ctx, "cache miss for contractor profile",
+			"user_id", userID,
+			"error", err)
+	}
+
+	profile, err := s.contractorRepo.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contractor profile: %w", err)
+	}
+
+	s.cache(ctx, profile)
+	return profile, nil
+}
+
+func (s *ContractorService) ListProfiles(ctx context.Context, status models.VerificationStatus) (_ []*models.ContractorProfile, err error) {
+	ctx, span := tracing.Start(ctx, "ContractorService.ListProfiles")
+	defer tracing.End(span, &err)
+
+	profiles, err := s.contractorRepo.ListProfiles(ctx, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contractor profiles: %w", err)
+	}
+	return profiles, nil
+}
+
+// Verify records an admin's verification decision.
+func (s *ContractorService) Verify(ctx context.Context, adminID, userID string, req *models.VerifyContractor) (_ *models.ContractorProfile, err error) {
+	ctx, span := tracing.Start(ctx, "ContractorService.Verify")
+	defer tracing.End(span, &err)
+
+	switch req.Status {
+	case models.VerificationPending, models.VerificationVerified, models.VerificationRejected:
+	default:
+		return nil, fmt.Errorf("invalid verification status: %s", req.Status)
+	}
+
+	before, err := s.contractorRepo.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify contractor: %w", err)
+	}
+
+	profile, err := s.contractorRepo.UpdateVerification(ctx, userID, req.Status, req.Note, adminID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify contractor: %w", err)
+	}
+	s.audit.Record(ctx, models.AuditContractorVerified, "contractor_profile", userID, before, profile)
+
+	s.cache(ctx, profile)
+	return profile, nil
+}
+
+// CheckQualification returns an ErrNotQualified error describing why the
+// contractor may not bid on the tender, or nil if they may.
+func (s *ContractorService) CheckQualification(ctx context.Context, contractorID string, tender *models.Tender) (err error) {
+	ctx, span := tracing.Start(ctx, "ContractorService.CheckQualification")
+	defer tracing.End(span, &err)
+
+	if !tender.RequiresVerified && len(tender.RequiredCertifications) == 0 {
+		return nil
+	}
+
+	profile, err := s.GetProfile(ctx, contractorID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return fmt.Errorf("%w: a contractor profile is required", ErrNotQualified)
+		}
+		return err
+	}
+
+	if tender.RequiresVerified && profile.VerificationStatus != models.VerificationVerified {
+		return fmt.Errorf("%w: tender requires a verified contractor", ErrNotQualified)
+	}
+
+	now := time.Now()
+	var missing []string
+	for _, cert := range tender.RequiredCertifications {
+		if !profile.HasValidCertification(cert, now) {
+			missing = append(missing, cert)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: missing or expired certifications: %s", ErrNotQualified, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+func (s *ContractorService) cache(ctx context.Context, profile *models.ContractorProfile) {
+	if s.contractorCache == nil {
+		return
+	}
+	if err := s.contractorCache.Set(ctx, profile); err != nil {
+		s.logger.WarnContext(ctx, "failed to cache contractor profile",
+			"error", err,
+			"user_id", profile.UserId)
+	}
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/zohirovs/internal/storage/redis"
+)
+
+var (
+	// ErrForbidden is returned when the caller is not allowed to act on a resource.
+	ErrForbidden = errors.New("forbidden")
+
+	// ErrUserExists is returned when registering an email or username that is
+	// taken, including by a soft-deleted user who may still be restored.
+	ErrUserExists = errors.New("email or username is already taken")
+
+	ErrFileTooLarge        = errors.New("file is too large")
+	ErrUnsupportedFileType = errors.New("unsupported file type")
+	ErrInvalidSignature    = errors.New("invalid or expired download link")
+
+	// ErrNotQualified is returned when a contractor does not meet a tender's requirements.
+	ErrNotQualified = errors.New("contractor does not meet tender requirements")
+
+	ErrNotAwarded      = errors.New("tender has not been awarded")
+	ErrAlreadyReviewed = errors.New("contractor has already been reviewed for this tender")
+	// ErrWinnerRequired is returned when reviewing a tender awarded to several
+	// contractors without naming one; ErrNotWinner when the named one has no
+	// accepted bid.
+	ErrWinnerRequired = errors.New("tender has several winners; name the contractor to review")
+	ErrNotWinner      = errors.New("contractor has no accepted bid on this tender")
+
+	ErrCategoryHasChildren = errors.New("category has subcategories")
+
+	// ErrQuestionsClosed is returned once the tender is no longer open or the
+	// question cutoff before its deadline has passed.
+	ErrQuestionsClosed = errors.New("questions are closed for this tender")
+
+	// ErrNotInvited is returned when a contractor has no usable invitation to an
+	// invite-only tender; ErrInvitationNotAccepted when they have not yet
+	// accepted the one they have.
+	ErrNotInvited            = errors.New("contractor is not invited to this tender")
+	ErrInvitationNotAccepted = errors.New("invitation has not been accepted")
+	ErrTenderNotInviteOnly   = errors.New("tender is not invite-only")
+
+	// ErrInvalidLots wraps problems with the lots a bid prices or an award names.
+	ErrInvalidLots = errors.New("invalid lots")
+	// ErrLotsUnresolved is returned when awarding a tender that still has open lots.
+	ErrLotsUnresolved = errors.New("tender has lots that are neither awarded nor cancelled")
+	ErrLotResolved    = errors.New("lot has already been awarded or cancelled")
+
+	// ErrDeadlinePassed is returned when publishing a draft whose deadline is over.
+	ErrDeadlinePassed = errors.New("tender deadline has passed")
+
+	// ErrBudgetRequired is returned when a template without a budget is used
+	// without supplying one.
+	ErrBudgetRequired = errors.New("budget is required")
+
+	// ErrInvalidLines wraps problems with the line items a bid prices.
+	ErrInvalidLines = errors.New("invalid bid lines")
+
+	// ErrLastOwner is returned when removing or demoting an organization's
+	// only owner.
+	ErrLastOwner = errors.New("organization must keep at least one owner")
+	// ErrAccountKind is returned when a client account tries to join or act
+	// for a contractor organization, or the other way round.
+	ErrAccountKind = errors.New("account type does not match the organization")
+
+	// ErrBidNotWithdrawable is returned when withdrawing a bid that has been
+	// decided or whose tender no longer takes bids.
+	ErrBidNotWithdrawable = errors.New("only pending bids on open tenders can be withdrawn")
+	// ErrTenderDeleted is returned when restoring a bid whose tender is
+	// still deleted; restore the tender instead.
+	ErrTenderDeleted = errors.New("tender is deleted")
+
+	// ErrUnavailable is returned by features that cannot work while Redis is
+	// unreachable, such as verification codes; handlers answer 503.
+	ErrUnavailable = redis.ErrUnavailable
+
+	// ErrInvalidCredentials is returned by Login for an unknown username or a
+	// wrong password alike.
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	// ErrLoginLocked matches a *LoginLockedError.
+	ErrLoginLocked = errors.New("too many failed logins")
+)
+
+// LoginLockedError is returned by Login while an account is locked out after
+// repeated failed attempts.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return ErrLoginLocked.Error()
+}
+
+func (e *LoginLockedError) Is(target error) bool {
+	return target == ErrLoginLocked
+}
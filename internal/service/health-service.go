@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/zohirovs/internal/models"
+)
+
+// healthCheckTimeout bounds each dependency ping so a hung dependency fails
+// the check instead of the probe.
+const healthCheckTimeout = 2 * time.Second
+
+// Pinger is a dependency checked for readiness.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+type healthCheck struct {
+	name string
+	dep  Pinger
+	// required checks make the instance unavailable when they fail; the
+	// others only degrade it.
+	required bool
+}
+
+type HealthService struct {
+	checks []healthCheck
+	logger *slog.Logger
+}
+
+// NewHealthService checks MongoDB and Redis. Redis is optional: without it
+// the API runs uncached, so its failure degrades readiness but does not
+// take the instance out of rotation.
+func NewHealthService(db, cache Pinger, logger *slog.Logger) *HealthService {
+	return &HealthService{
+		checks: []healthCheck{
+			{name: "mongodb", dep: db, required: true},
+			{name: "redis", dep: cache},
+		},
+		logger: logger,
+	}
+}
+
+// Ready pings every dependency concurrently.
+func (s *HealthService) Ready(ctx context.Context) *models.Readiness {
+	readiness := &models.Readiness{
+		Status: models.HealthOK,
+		Checks: make(map[string]string, len(s.checks)),
+	}
+
+	errs := make([]error, len(s.checks))
+	var wg sync.WaitGroup
+	for i, check := range s.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+			errs[i] = check.dep.Ping(ctx)
+		}()
+	}
+	wg.Wait()
+
+	for i, check := range s.checks {
+		if errs[i] == nil {
+			readiness.Checks[check.name] = string(models.HealthOK)
+			continue
+		}
+		// The probe is public, so the error itself is only logged.
+		readiness.Checks[check.name] = string(models.HealthUnavailable)
+		s.logger.WarnContext(ctx, "readiness check failed", "check", check.name, "error", errs[i])
+		switch {
+		case check.required:
+			readiness.Status = models.HealthUnavailable
+		case readiness.Status == models.HealthOK:
+			readiness.Status = models.HealthDegraded
+		}
+	}
+	return readiness
+}
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/repos"
+	"github.com/zohirovs/internal/tracing"
+)
+
+type InvitationService struct {
+	invitationRepo repos.InvitationRepo
+	tenderRepo     repos.TenderRepo
+	userRepo       repos.UserRepo
+	search         *SearchService
+	notifications  *NotificationService
+	orgs           *OrganizationService
+	audit          *AuditService
+	runJob         JobRunner
+	logger         *slog.Logger
+}
+
+func NewInvitationService(invitationRepo repos.InvitationRepo, tenderRepo repos.TenderRepo, userRepo repos.UserRepo, search *SearchService, notifications *NotificationService, orgs *OrganizationService, audit *AuditService, runJob JobRunner, logger *slog.Logger) *InvitationService {
+	return &InvitationService{
+		invitationRepo: invitationRepo,
+		tenderRepo:     tenderRepo,
+		userRepo:       userRepo,
+		search:         search,
+		notifications:  notifications,
+		orgs:           orgs,
+		audit:          audit,
+		runJob:         runJob,
+		logger:         logger,
+	}
+}
+
+// Invite invites contractors to the client's invite-only tender. Invitations
+// by email stay email-only until the recipient responds with the token
+// mailed to them.
+func (s *InvitationService) Invite(ctx context.Context, clientID, tenderID string, req *models.InviteContractors) (_ []*models.Invitation, err error) {
+	ctx, span := tracing.Start(ctx, "InvitationService.Invite")
+	defer tracing.End(span, &err)
+
+	tender, err := s.ownedTender(ctx, clientID, tenderID)
+	if err != nil {
+		return nil, err
+	}
+	if !tender.IsInviteOnly() {
+		return nil, ErrTenderNotInviteOnly
+	}
+
+	var invitees []*models.Invitation
+	for _, id := range req.ContractorIds {
+		user, err := s.userRepo.GetUserByUserID(ctx, id)
+		if err != nil || user.Role != models.Contractor {
+			return nil, fmt.Errorf("contractor not found: %s", id)
+		}
+		invitees = append(invitees, &models.Invitation{ContractorId: user.ID, Email: models.NormalizeEmail(user.Email)})
+	}
+	for _, email := range req.Emails {
+		email = models.NormalizeEmail(email)
+		if email == "" {
+			continue
+		}
+		invitees = append(invitees, &models.Invitation{Email: email})
+	}
+
+	invitations := make([]*models.Invitation, 0, len(invitees))
+	var notifications []*models.Notification
+	for _, invitee := range invitees {
+		invitee.TenderId = tenderID
+		var token string
+		if invitee.ContractorId == "" {
+			if token, err = newInvitationToken(); err != nil {
+				return nil, err
+			}
+			invitee.TokenHash = hashInvitationToken(token)
+		}
+		invitation, err := s.invitationRepo.CreateInvitation(ctx, invitee)
+		if err != nil {
+			return nil, fmt.Errorf("failed to invite contractor: %w", err)
+		}
+		invitations = append(invitations, invitation)
+		s.audit.Record(ctx, models.AuditInvitationCreated, "invitation", invitation.InvitationId, nil, invitation)
+
+		if invitation.ContractorId != "" {
+			notifications = append(notifications, &models.Notification{
+				UserId:   invitation.ContractorId,
+				Type:     models.NotificationInvited,
+				Title:    fmt.Sprintf("You are invited to bid on %q", tender.Title),
+				Message:  "Accept the invitation to submit a bid.",
+				TenderId: tenderID,
+			})
+		}
+		if invitation.Email != "" {
+			s.emailInvitation(invitation, tender, token)
+		}
+	}
+
+	if len(notifications) > 0 {
+		if err := s.notifications.Notify(ctx, notifications...); err != nil {
+			s.logger.WarnContext(ctx, "failed to notify invited contractors",
+				"error", err,
+				"tender_id", tenderID)
+		}
+	}
+
+	s.syncAccess(ctx, tenderID)
+	return invitations, nil
+}
+
+func (s *InvitationService) ListTenderInvitations(ctx context.Context, clientID, tenderID string) (_ []*models.Invitation, err error) {
+	ctx, span := tracing.Start(ctx, "InvitationService.ListTenderInvitations")
+	defer tracing.End(span, &err)
+
+	if _, err := s.ownedTender(ctx, clientID, tenderID); err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.invitationRepo.ListTenderInvitations(ctx, tenderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invitations: %w", err)
+	}
+	return invitations, nil
+}
+
+func (s *InvitationService) ListMyInvitations(ctx context.Context, contractorID string) (_ []*models.Invitation, err error) {
+	ctx, span := tracing.Start(ctx, "InvitationService.ListMyInvitations")
+	defer tracing.End(span, &err)
+
+	invitations, err := s.invitationRepo.ListContractorInvitations(ctx, contractorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invitations: %w", err)
+	}
+	return invitations, nil
+}
+
+// Respond accepts or declines an invitation addressed to the contractor, or
+// claims an email invitation with the token from its email.
+func (s *InvitationService) Respond(ctx context.Context, contractorID, invitationID string, req *models.RespondInvitation) (_ *models.Invitation, err error) {
+	ctx, span := tracing.Start(ctx, "InvitationService.Respond")
+	defer tracing.End(span, &err)
+
+	invitation, err := s.invitationRepo.GetInvitation(ctx, invitationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invitation: %w", err)
+	}
+
+	if invitation.ContractorId != contractorID {
+		if invitation.ContractorId != "" || invitation.TokenHash == "" || req.Token == "" ||
+			subtle.ConstantTimeCompare([]byte(hashInvitationToken(req.Token)), []byte(invitation.TokenHash)) != 1 {
+			return nil, ErrForbidden
+		}
+	}
+
+	status := models.InvitationDeclined
+	if req.Accept {
+		status = models.InvitationAccepted
+	}
+
+	before := invitation
+	invitation, err = s.invitationRepo.RespondInvitation(ctx, invitationID, contractorID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to respond to invitation: %w", err)
+	}
+	s.audit.Record(ctx, models.AuditInvitationResponded, "invitation", invitationID, before, invitation)
+
+	tender, err := s.tenderRepo.GetTender(ctx, invitation.TenderId)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to load tender for invitation response",
+			"error", err,
+			"tender_id", invitation.TenderId)
+	} else if err := s.notifications.Notify(ctx, &models.Notification{
+		UserId:   tender.ClientId,
+		Type:     models.NotificationInviteResponse,
+		Title:    fmt.Sprintf("Invitation to %q %s", tender.Title, status),
+		Message:  fmt.Sprintf("Contractor %s has %s your invitation.", contractorID, status),
+		TenderId: tender.TenderId,
+	}); err != nil {
+		s.logger.WarnContext(ctx, "failed to notify client of invitation response",
+			"error", err,
+			"tender_id", tender.TenderId)
+	}
+
+	s.syncAccess(ctx, invitation.TenderId)
+	return invitation, nil
+}
+
+// CheckAccess reports whether the user may see the tender. Public tenders
+// are visible to everyone; invite-only ones to their owners and to invitees
+// who have not declined.
+func (s *InvitationService) CheckAccess(ctx context.Context, tender *models.Tender, userID string) (err error) {
+	ctx, span := tracing.Start(ctx, "InvitationService.CheckAccess")
+	defer tracing.End(span, &err)
+
+	if !tender.IsInviteOnly() {
+		return nil
+	}
+	if err := s.orgs.CanViewTender(ctx, userID, tender); err == nil {
+		return nil
+	} else if !errors.Is(err, ErrForbidden) {
+		return err
+	}
+
+	invitation, err := s.invitationRepo.FindInvitation(ctx, tender.TenderId, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check invitation: %w", err)
+	}
+	if invitation == nil || invitation.Status == models.InvitationDeclined {
+		return ErrNotInvited
+	}
+	return nil
+}
+
+// CheckCanBid is CheckAccess for bidding, which additionally requires the
+// invitation to have been accepted.
+func (s *InvitationService) CheckCanBid(ctx context.Context, tender *models.Tender, contractorID string) (err error) {
+	ctx, span := tracing.Start(ctx, "InvitationService.CheckCanBid")
+	defer tracing.End(span, &err)
+
+	if !tender.IsInviteOnly() {
+		return nil
+	}
+
+	invitation, err := s.invitationRepo.FindInvitation(ctx, tender.TenderId, contractorID)
+	if err != nil {
+		return fmt.Errorf("failed to check invitation: %w", err)
+	}
+	switch {
+	case invitation == nil || invitation.Status == models.InvitationDeclined:
+		return ErrNotInvited
+	case invitation.Status != models.InvitationAccepted:
+		return ErrInvitationNotAccepted
+	}
+	return nil
+}
+
+func (s *InvitationService) ownedTender(ctx context.Context, clientID, tenderID string) (*models.Tender, error) {
+	tender, err := s.tenderRepo.GetTender(ctx, tenderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tender: %w", err)
+	}
+	if err := s.orgs.CanManageTender(ctx, clientID, tender); err != nil {
+		return nil, err
+	}
+	return tender, nil
+}
+
+// syncAccess pushes the contractors who may find the tender to the search index.
+func (s *InvitationService) syncAccess(ctx context.Context, tenderID string) {
+	invitations, err := s.invitationRepo.ListTenderInvitations(ctx, tenderID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to list invitations for search access",
+			"error", err,
+			"tender_id", tenderID)
+		return
+	}
+
+	var contractorIDs []string
+	for _, invitation := range invitations {
+		if invitation.ContractorId != "" && invitation.Status != models.InvitationDeclined {
+			contractorIDs = append(contractorIDs, invitation.ContractorId)
+		}
+	}
+	s.search.SetAccess(ctx, tenderID, contractorIDs)
+}
+
+// emailInvitation is sent as a background job so inviting many contractors
+// is not held up by SMTP, and shutdown waits for it. Email invitations carry the token that claims them.
+func (s *InvitationService) emailInvitation(invitation *models.Invitation, tender *models.Tender, token string) {
+	to := invitation.Email
+	subject := fmt.Sprintf("Invitation to bid: %s", tender.Title)
+	body := fmt.Sprintf("You have been invited to bid on the tender %q (deadline %s).\n\nSign in as a contractor to accept or decline the invitation.\n",
+		tender.Title, tender.Deadline.Format("2006-01-02 15:04 MST"))
+	if token != "" {
+		body += fmt.Sprintf("\nInvitation ID: %s\nInvitation token: %s\n\nThe token can be used once; keep it private.\n",
+			invitation.InvitationId, token)
+	}
+
+	s.runJob(func(context.Context) {
+		if err := s.notifications.Email(to, subject, body); err != nil {
+			s.logger.Warn("failed to email invitation",
+				"error", err,
+				"tender_id", tender.TenderId)
+		}
+	})
+}
+
+// newInvitationToken returns a random claim token for an email invitation.
+func newInvitationToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate invitation token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashInvitationToken is what is stored, so the database alone cannot claim
+// invitations.
+func hashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
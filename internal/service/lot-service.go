@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/repos"
+	"github.com/zohirovs/internal/tracing"
+)
+
+type LotService struct {
+	tenderRepo    repos.TenderRepo
+	bidRepo       repos.BidRepo
+	tenders       *TenderService
+	notifications *NotificationService
+	orgs          *OrganizationService
+	audit         *AuditService
+	logger        *slog.Logger
+}
+
+func NewLotService(tenderRepo repos.TenderRepo, bidRepo repos.BidRepo, tenders *TenderService, notifications *NotificationService, orgs *OrganizationService, audit *AuditService, logger *slog.Logger) *LotService {
+	return &LotService{
+		tenderRepo:    tenderRepo,
+		bidRepo:       bidRepo,
+		tenders:       tenders,
+		notifications: notifications,
+		orgs:          orgs,
+		audit:         audit,
+		logger:        logger,
+	}
+}
+
+// AwardLot awards one lot of the client's tender to a bid that priced it.
+func (s *LotService) AwardLot(ctx context.Context, clientID, tenderID, lotID string, req *models.AwardLot) (_ *models.Tender, err error) {
+	ctx, span := tracing.Start(ctx, "LotService.AwardLot")
+	defer tracing.End(span, &err)
+
+	tender, lot, err := s.openLot(ctx, clientID, tenderID, lotID)
+	if err != nil {
+		return nil, err
+	}
+
+	bid, err := s.bidRepo.GetBid(ctx, req.BidId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bid: %w", err)
+	}
+	if bid.TenderId != tenderID || !pricesLot(bid, lotID) {
+		return nil, fmt.Errorf("%w: bid %s does not price lot %s", ErrInvalidLots, bid.BidId, lotID)
+	}
+
+	lot.Status = models.LotAwarded
+	lot.AwardedBidId = bid.BidId
+	lot.AwardedContractorId = bid.ContractorId
+	if err := s.tenderRepo.ResolveLot(ctx, tenderID, lot); err != nil {
+		return nil, s.resolveError(err)
+	}
+	s.audit.Record(ctx, models.AuditLotAwarded, "lot", lotID, nil, lot)
+
+	if bid.Status != "accepted" {
+		if err := s.bidRepo.UpdateBidStatus(ctx, bid.BidId, "accepted"); err != nil {
+			s.logger.WarnContext(ctx, "failed to mark awarded bid accepted",
+				"error", err,
+				"bid_id", bid.BidId)
+		} else {
+			s.audit.Record(ctx, models.AuditBidStatusChanged, "bid", bid.BidId,
+				map[string]string{"status": bid.Status},
+				map[string]string{"status": "accepted"})
+		}
+	}
+
+	if err := s.notifications.Notify(ctx, &models.Notification{
+		UserId:   bid.ContractorId,
+		Type:     models.NotificationLotAwarded,
+		Title:    fmt.Sprintf("You won lot %q", lot.Title),
+		Message:  fmt.Sprintf("Your bid on %q was awarded lot %q.", tender.Title, lot.Title),
+		TenderId: tenderID,
+	}); err != nil {
+		s.logger.WarnContext(ctx, "failed to notify lot winner",
+			"error", err,
+			"tender_id", tenderID)
+	}
+
+	return s.finish(ctx, tenderID)
+}
+
+// CancelLot withdraws a lot from the tender without awarding it.
+func (s *LotService) CancelLot(ctx context.Context, clientID, tenderID, lotID string) (_ *models.Tender, err error) {
+	ctx, span := tracing.Start(ctx, "LotService.CancelLot")
+	defer tracing.End(span, &err)
+
+	_, lot, err := s.openLot(ctx, clientID, tenderID, lotID)
+	if err != nil {
+		return nil, err
+	}
+
+	lot.Status = models.LotCancelled
+	if err := s.tenderRepo.ResolveLot(ctx, tenderID, lot); err != nil {
+		return nil, s.resolveError(err)
+	}
+	s.audit.Record(ctx, models.AuditLotCancelled, "lot", lotID, nil, lot)
+
+	return s.finish(ctx, tenderID)
+}
+
+func (s *LotService) openLot(ctx context.Context, clientID, tenderID, lotID string) (*models.Tender, *models.Lot, error) {
+	tender, err := s.tenderRepo.GetTender(ctx, tenderID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get tender: %w", err)
+	}
+	if err := s.orgs.CanManageTender(ctx, clientID, tender); err != nil {
+		return nil, nil, err
+	}
+
+	lot := tender.Lot(lotID)
+	if lot == nil {
+		return nil, nil, fmt.Errorf("lot not found: %s", lotID)
+	}
+	if lot.Status != models.LotOpen {
+		return nil, nil, ErrLotResolved
+	}
+	return tender, lot, nil
+}
+
+// resolveError maps a lost race on ResolveLot to ErrLotResolved.
+func (s *LotService) resolveError(err error) error {
+	return fmt.Errorf("%w: %v", ErrLotResolved, err)
+}
+
+// finish moves the tender to AWARDED once every lot is resolved, or to CLOSED
+// if they were all cancelled, and returns the updated tender.
+func (s *LotService) finish(ctx context.Context, tenderID string) (*models.Tender, error) {
+	tender, err := s.tenderRepo.GetTender(ctx, tenderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tender: %w", err)
+	}
+
+	resolved, anyAwarded := tender.LotsResolved()
+	if !resolved {
+		s.tenders.refresh(ctx, tenderID)
+		tender.Localize()
+		return tender, nil
+	}
+
+	status := models.CLOSED
+	if anyAwarded {
+		status = models.AWARDED
+	}
+	if err := s.tenders.setStatus(ctx, tenderID, models.Status(tender.Status), status); err != nil {
+		return nil, err
+	}
+
+	return s.tenders.GetTender(ctx, tenderID)
+}
+
+func pricesLot(bid *models.Bid, lotID string) bool {
+	for _, price := range bid.LotPrices {
+		if price.LotId == lotID {
+			return true
+		}
+	}
+	return false
+}
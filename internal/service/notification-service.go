@@ -1,22 +1,151 @@
 package service
 
 import (
+	"context"
+	"fmt"
 	"log/slog"
+	"strings"
+	"time"
 
+	"github.com/zohirovs/internal/mailer"
+	"github.com/zohirovs/internal/models"
 	"github.com/zohirovs/internal/repos"
 	"github.com/zohirovs/internal/storage/redis"
+	"github.com/zohirovs/internal/tracing"
 )
 
 type NotificationService struct {
 	notificationRepo  repos.NotificationRepo
+	userRepo          repos.UserRepo
 	notificationCache *redis.NotificationCaching
+	mailer            *mailer.Mailer
+	audit             *AuditService
 	logger            *slog.Logger
 }
 
-func NewNotificationService(notificationRepo repos.NotificationRepo, cache *redis.NotificationCaching, logger *slog.Logger) *NotificationService {
+func NewNotificationService(notificationRepo repos.NotificationRepo, userRepo repos.UserRepo, cache *redis.NotificationCaching, mailer *mailer.Mailer, audit *AuditService, logger *slog.Logger) *NotificationService {
 	return &NotificationService{
 		notificationRepo:  notificationRepo,
+		userRepo:          userRepo,
 		notificationCache: cache,
+		mailer:            mailer,
+		audit:             audit,
 		logger:            logger,
 	}
 }
+
+// Notify stores in-app notifications for their recipients.
+func (s *NotificationService) Notify(ctx context.Context, notifications ...*models.Notification) (err error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.Notify")
+	defer tracing.End(span, &err)
+
+	if err := s.notificationRepo.CreateNotifications(ctx, notifications); err != nil {
+		return fmt.Errorf("failed to create notifications: %w", err)
+	}
+	return nil
+}
+
+func (s *NotificationService) ListNotifications(ctx context.Context, userID string, unreadOnly bool) (_ []*models.Notification, err error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.ListNotifications")
+	defer tracing.End(span, &err)
+
+	notifications, err := s.notificationRepo.ListNotifications(ctx, userID, unreadOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	return notifications, nil
+}
+
+func (s *NotificationService) MarkRead(ctx context.Context, userID, notificationID string) (err error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.MarkRead")
+	defer tracing.End(span, &err)
+
+	if err := s.notificationRepo.MarkRead(ctx, userID, notificationID); err != nil {
+		return fmt.Errorf("failed to mark notification read: %w", err)
+	}
+	s.audit.Record(ctx, models.AuditNotificationRead, "notification", notificationID, nil, nil)
+	return nil
+}
+
+// Email sends a one-off message outside the digest, e.g. to invitees who do
+// not have an account yet.
+func (s *NotificationService) Email(to, subject, body string) error {
+	if err := s.mailer.Send(to, subject, body); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// SendDigests emails each user one message summarizing their pending digest
+// notifications. Failures for one user do not stop the others.
+func (s *NotificationService) SendDigests(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.SendDigests")
+	defer tracing.End(span, &err)
+
+	pending, err := s.notificationRepo.ListPendingDigest(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list digest notifications: %w", err)
+	}
+
+	byUser := make(map[string][]*models.Notification)
+	var order []string
+	for _, notification := range pending {
+		if _, ok := byUser[notification.UserId]; !ok {
+			order = append(order, notification.UserId)
+		}
+		byUser[notification.UserId] = append(byUser[notification.UserId], notification)
+	}
+
+	for _, userID := range order {
+		notifications := byUser[userID]
+
+		user, err := s.userRepo.GetUserByUserID(ctx, userID)
+		if err != nil {
+			s.logger.WarnContext(ctx, "skipping digest for unknown user",
+				"error", err,
+				"user_id", userID)
+			continue
+		}
+
+		var body strings.Builder
+		ids := make([]string, 0, len(notifications))
+		fmt.Fprintf(&body, "Hello %s,\n\nNew tenders match your saved searches:\n\n", user.Username)
+		for _, notification := range notifications {
+			fmt.Fprintf(&body, "- %s\n  %s\n", notification.Title, notification.Message)
+			ids = append(ids, notification.NotificationId)
+		}
+
+		subject := fmt.Sprintf("%d new matching tenders", len(notifications))
+		if err := s.mailer.Send(user.Email, subject, body.String()); err != nil {
+			s.logger.ErrorContext(ctx, "failed to send digest email",
+				"error", err,
+				"user_id", userID)
+			continue
+		}
+
+		if err := s.notificationRepo.MarkEmailed(ctx, ids); err != nil {
+			s.logger.ErrorContext(ctx, "failed to mark digest notifications emailed",
+				"error", err,
+				"user_id", userID)
+		}
+	}
+
+	return nil
+}
+
+// RunDigests sends digests every interval until ctx is cancelled.
+func (s *NotificationService) RunDigests(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.SendDigests(ctx); err != nil {
+				s.logger.ErrorContext(ctx, "failed to send digests", "error", err)
+			}
+		}
+	}
+}
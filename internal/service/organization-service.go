@@ -0,0 +1,495 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"log/slog"
+
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/repos"
+	"github.com/zohirovs/internal/tracing"
+)
+
+// OrganizationService manages organizations and their members, and decides
+// who may act on tenders and bids: those owned by an organization are
+// managed by its owners and managers and visible to all its members, others
+// only by the user who created them.
+type OrganizationService struct {
+	orgRepo       repos.OrganizationRepo
+	userRepo      repos.UserRepo
+	tenderRepo    repos.TenderRepo
+	bidRepo       repos.BidRepo
+	notifications *NotificationService
+	audit         *AuditService
+	runJob        JobRunner
+	logger        *slog.Logger
+}
+
+func NewOrganizationService(orgRepo repos.OrganizationRepo, userRepo repos.UserRepo, tenderRepo repos.TenderRepo, bidRepo repos.BidRepo, notifications *NotificationService, audit *AuditService, runJob JobRunner, logger *slog.Logger) *OrganizationService {
+	return &OrganizationService{
+		orgRepo:       orgRepo,
+		userRepo:      userRepo,
+		tenderRepo:    tenderRepo,
+		bidRepo:       bidRepo,
+		notifications: notifications,
+		audit:         audit,
+		runJob:        runJob,
+		logger:        logger,
+	}
+}
+
+// CreateOrganization creates an organization of the user's kind with the
+// user as its first owner.
+func (s *OrganizationService) CreateOrganization(ctx context.Context, userID string, req *models.CreateOrganization) (_ *models.Organization, err error) {
+	ctx, span := tracing.Start(ctx, "OrganizationService.CreateOrganization")
+	defer tracing.End(span, &err)
+
+	user, err := s.userRepo.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.Role != models.Client && user.Role != models.Contractor {
+		return nil, ErrAccountKind
+	}
+
+	org, err := s.orgRepo.CreateOrganization(ctx, &models.Organization{
+		Name:      req.Name,
+		Kind:      user.Role,
+		CreatedBy: userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	if err := s.orgRepo.AddMember(ctx, &models.OrganizationMember{
+		OrganizationId: org.OrganizationId,
+		UserId:         userID,
+		Role:           models.OrgOwner,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to add organization owner: %w", err)
+	}
+
+	s.audit.Record(ctx, models.AuditOrganizationCreated, "organization", org.OrganizationId, nil, org)
+
+	org.Role = models.OrgOwner
+	return org, nil
+}
+
+func (s *OrganizationService) ListMyOrganizations(ctx context.Context, userID string) (_ []*models.Organization, err error) {
+	ctx, span := tracing.Start(ctx, "OrganizationService.ListMyOrganizations")
+	defer tracing.End(span, &err)
+
+	memberships, err := s.orgRepo.ListMemberships(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list memberships: %w", err)
+	}
+
+	roles := make(map[string]models.OrgRole, len(memberships))
+	ids := make([]string, 0, len(memberships))
+	for _, member := range memberships {
+		roles[member.OrganizationId] = member.Role
+		ids = append(ids, member.OrganizationId)
+	}
+
+	orgs, err := s.orgRepo.ListOrganizations(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+	for _, org := range orgs {
+		org.Role = roles[org.OrganizationId]
+	}
+	return orgs, nil
+}
+
+func (s *OrganizationService) GetOrganization(ctx context.Context, userID, orgID string) (_ *models.Organization, err error) {
+	ctx, span := tracing.Start(ctx, "OrganizationService.GetOrganization")
+	defer tracing.End(span, &err)
+
+	member, err := s.member(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	org, err := s.orgRepo.GetOrganization(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+	org.Role = member.Role
+	return org, nil
+}
+
+func (s *OrganizationService) ListMembers(ctx context.Context, userID, orgID string) (_ []*models.OrganizationMember, err error) {
+	ctx, span := tracing.Start(ctx, "OrganizationService.ListMembers")
+	defer tracing.End(span, &err)
+
+	if _, err := s.member(ctx, orgID, userID); err != nil {
+		return nil, err
+	}
+
+	members, err := s.orgRepo.ListMembers(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members: %w", err)
+	}
+	return members, nil
+}
+
+// InviteMember invites someone by email. The invite is accepted with the
+// single-use token mailed to the address. Account holders are also notified
+// in the app.
+func (s *OrganizationService) InviteMember(ctx context.Context, userID, orgID string, req *models.InviteMember) (_ *models.MemberInvite, err error) {
+	ctx, span := tracing.Start(ctx, "OrganizationService.InviteMember")
+	defer tracing.End(span, &err)
+
+	if err := s.requireOwner(ctx, orgID, userID); err != nil {
+		return nil, err
+	}
+
+	org, err := s.orgRepo.GetOrganization(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+
+	token, err := newInvitationToken()
+	if err != nil {
+		return nil, err
+	}
+	invite, err := s.orgRepo.CreateMemberInvite(ctx, &models.MemberInvite{
+		OrganizationId: orgID,
+		Email:          models.NormalizeEmail(req.Email),
+		Role:           req.Role,
+		InvitedBy:      userID,
+		TokenHash:      hashInvitationToken(token),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to invite member: %w", err)
+	}
+	s.audit.Record(ctx, models.AuditMemberInvited, "organization_invite", invite.InviteId, nil, invite)
+
+	if user, err := s.userRepo.GetUserByEmail(ctx, invite.Email); err == nil {
+		if err := s.notifications.Notify(ctx, &models.Notification{
+			UserId:  user.ID,
+			Type:    models.NotificationMemberInvite,
+			Title:   fmt.Sprintf("You are invited to join %s", org.Name),
+			Message: fmt.Sprintf("Accept the invitation to join as %s with the token emailed to you.", invite.Role),
+		}); err != nil {
+			s.logger.WarnContext(ctx, "failed to notify invited member",
+				"error", err,
+				"organization_id", orgID)
+		}
+	}
+	s.emailInvite(invite, org, token)
+
+	return invite, nil
+}
+
+func (s *OrganizationService) ListInvites(ctx context.Context, userID, orgID string) (_ []*models.MemberInvite, err error) {
+	ctx, span := tracing.Start(ctx, "OrganizationService.ListInvites")
+	defer tracing.End(span, &err)
+
+	if err := s.requireOwner(ctx, orgID, userID); err != nil {
+		return nil, err
+	}
+
+	invites, err := s.orgRepo.ListMemberInvites(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invites: %w", err)
+	}
+	return invites, nil
+}
+
+// ListMyInvites lists the invites the user has answered. Pending invites are
+// not listed by email, as account emails are not verified; they are found
+// through the invitation email.
+func (s *OrganizationService) ListMyInvites(ctx context.Context, userID string) (_ []*models.MemberInvite, err error) {
+	ctx, span := tracing.Start(ctx, "OrganizationService.ListMyInvites")
+	defer tracing.End(span, &err)
+
+	invites, err := s.orgRepo.ListUserInvites(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invites: %w", err)
+	}
+	return invites, nil
+}
+
+// RespondInvite accepts or declines an invite with the token from its email.
+// Accepting adds the user with the invited role unless they already are a
+// member, whose role is then left alone.
+func (s *OrganizationService) RespondInvite(ctx context.Context, userID, inviteID string, req *models.RespondMemberInvite) (_ *models.MemberInvite, err error) {
+	ctx, span := tracing.Start(ctx, "OrganizationService.RespondInvite")
+	defer tracing.End(span, &err)
+
+	invite, err := s.orgRepo.GetMemberInvite(ctx, inviteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invite: %w", err)
+	}
+
+	if invite.TokenHash == "" || req.Token == "" ||
+		subtle.ConstantTimeCompare([]byte(hashInvitationToken(req.Token)), []byte(invite.TokenHash)) != 1 {
+		return nil, ErrForbidden
+	}
+
+	user, err := s.userRepo.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	status := models.MemberInviteDeclined
+	if req.Accept {
+		org, err := s.orgRepo.GetOrganization(ctx, invite.OrganizationId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get organization: %w", err)
+		}
+		if user.Role != org.Kind {
+			return nil, ErrAccountKind
+		}
+		status = models.MemberInviteAccepted
+	}
+
+	before := invite
+	invite, err = s.orgRepo.RespondMemberInvite(ctx, inviteID, userID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to respond to invite: %w", err)
+	}
+	s.audit.Record(ctx, models.AuditMemberInviteAnswer, "organization_invite", inviteID, before, invite)
+
+	if status == models.MemberInviteAccepted {
+		existing, err := s.orgRepo.GetMember(ctx, invite.OrganizationId, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check membership: %w", err)
+		}
+		if existing == nil {
+			if err := s.orgRepo.AddMember(ctx, &models.OrganizationMember{
+				OrganizationId: invite.OrganizationId,
+				UserId:         userID,
+				Role:           invite.Role,
+			}); err != nil {
+				return nil, fmt.Errorf("failed to add member: %w", err)
+			}
+		}
+	}
+
+	return invite, nil
+}
+
+// ChangeMemberRole is for owners. The last owner cannot be demoted.
+func (s *OrganizationService) ChangeMemberRole(ctx context.Context, userID, orgID, memberID string, role models.OrgRole) (err error) {
+	ctx, span := tracing.Start(ctx, "OrganizationService.ChangeMemberRole")
+	defer tracing.End(span, &err)
+
+	if err := s.requireOwner(ctx, orgID, userID); err != nil {
+		return err
+	}
+
+	member, err := s.orgRepo.GetMember(ctx, orgID, memberID)
+	if err != nil {
+		return fmt.Errorf("failed to get member: %w", err)
+	}
+	if member == nil {
+		return fmt.Errorf("member not found: %s", memberID)
+	}
+	if member.Role == models.OrgOwner && role != models.OrgOwner {
+		if err := s.keepOwner(ctx, orgID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.orgRepo.UpdateMemberRole(ctx, orgID, memberID, role); err != nil {
+		return fmt.Errorf("failed to change member role: %w", err)
+	}
+
+	after := *member
+	after.Role = role
+	s.audit.Record(ctx, models.AuditMemberRoleChanged, "organization_member", orgID+"/"+memberID, member, &after)
+	return nil
+}
+
+// RemoveMember removes a member on an owner's request, or the caller
+// themselves when they leave. The last owner cannot leave.
+func (s *OrganizationService) RemoveMember(ctx context.Context, userID, orgID, memberID string) (err error) {
+	ctx, span := tracing.Start(ctx, "OrganizationService.RemoveMember")
+	defer tracing.End(span, &err)
+
+	if memberID != userID {
+		if err := s.requireOwner(ctx, orgID, userID); err != nil {
+			return err
+		}
+	}
+
+	member, err := s.orgRepo.GetMember(ctx, orgID, memberID)
+	if err != nil {
+		return fmt.Errorf("failed to get member: %w", err)
+	}
+	if member == nil {
+		if memberID == userID {
+			return ErrForbidden
+		}
+		return fmt.Errorf("member not found: %s", memberID)
+	}
+	if member.Role == models.OrgOwner {
+		if err := s.keepOwner(ctx, orgID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.orgRepo.RemoveMember(ctx, orgID, memberID); err != nil {
+		return fmt.Errorf("failed to remove member: %w", err)
+	}
+	s.audit.Record(ctx, models.AuditMemberRemoved, "organization_member", orgID+"/"+memberID, member, nil)
+	return nil
+}
+
+// ListTenders lists a client organization's tenders for its members.
+func (s *OrganizationService) ListTenders(ctx context.Context, userID, orgID string) (_ []*models.Tender, err error) {
+	ctx, span := tracing.Start(ctx, "OrganizationService.ListTenders")
+	defer tracing.End(span, &err)
+
+	if _, err := s.member(ctx, orgID, userID); err != nil {
+		return nil, err
+	}
+
+	tenders, err := s.tenderRepo.ListTendersByOrganization(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenders: %w", err)
+	}
+	for _, tender := range tenders {
+		tender.Localize()
+	}
+	return tenders, nil
+}
+
+// ListBids lists a contractor organization's bids for its members.
+func (s *OrganizationService) ListBids(ctx context.Context, userID, orgID string) (_ []*models.Bid, err error) {
+	ctx, span := tracing.Start(ctx, "OrganizationService.ListBids")
+	defer tracing.End(span, &err)
+
+	if _, err := s.member(ctx, orgID, userID); err != nil {
+		return nil, err
+	}
+
+	bids, err := s.bidRepo.ListBidsByOrganization(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bids: %w", err)
+	}
+	return bids, nil
+}
+
+// CheckActingFor verifies that the user may create tenders (kind client) or
+// bids (kind contractor) on behalf of the organization.
+func (s *OrganizationService) CheckActingFor(ctx context.Context, userID, orgID string, kind models.Role) (err error) {
+	ctx, span := tracing.Start(ctx, "OrganizationService.CheckActingFor")
+	defer tracing.End(span, &err)
+
+	org, err := s.orgRepo.GetOrganization(ctx, orgID)
+	if err != nil {
+		return fmt.Errorf("failed to get organization: %w", err)
+	}
+	if org.Kind != kind {
+		return ErrAccountKind
+	}
+	return s.authorize(ctx, userID, "", orgID, true)
+}
+
+// CanManageTender returns ErrForbidden unless the user may change the tender.
+func (s *OrganizationService) CanManageTender(ctx context.Context, userID string, tender *models.Tender) (err error) {
+	ctx, span := tracing.Start(ctx, "OrganizationService.CanManageTender")
+	defer tracing.End(span, &err)
+
+	return s.authorize(ctx, userID, tender.ClientId, tender.OrganizationId, true)
+}
+
+// CanViewTender returns ErrForbidden unless the user is the tender's owner,
+// which includes every member of an owning organization.
+func (s *OrganizationService) CanViewTender(ctx context.Context, userID string, tender *models.Tender) (err error) {
+	ctx, span := tracing.Start(ctx, "OrganizationService.CanViewTender")
+	defer tracing.End(span, &err)
+
+	return s.authorize(ctx, userID, tender.ClientId, tender.OrganizationId, false)
+}
+
+// CanManageBid returns ErrForbidden unless the user may change the bid.
+func (s *OrganizationService) CanManageBid(ctx context.Context, userID string, bid *models.Bid) (err error) {
+	ctx, span := tracing.Start(ctx, "OrganizationService.CanManageBid")
+	defer tracing.End(span, &err)
+
+	return s.authorize(ctx, userID, bid.ContractorId, bid.OrganizationId, true)
+}
+
+// CanViewBid is CanViewTender for bids.
+func (s *OrganizationService) CanViewBid(ctx context.Context, userID string, bid *models.Bid) (err error) {
+	ctx, span := tracing.Start(ctx, "OrganizationService.CanViewBid")
+	defer tracing.End(span, &err)
+
+	return s.authorize(ctx, userID, bid.ContractorId, bid.OrganizationId, false)
+}
+
+func (s *OrganizationService) authorize(ctx context.Context, userID, ownerID, orgID string, manage bool) error {
+	if userID == "" {
+		return ErrForbidden
+	}
+	if orgID == "" {
+		if userID != ownerID {
+			return ErrForbidden
+		}
+		return nil
+	}
+
+	member, err := s.member(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if manage && !member.Role.CanManage() {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// member returns the user's membership, or ErrForbidden if they have none.
+func (s *OrganizationService) member(ctx context.Context, orgID, userID string) (*models.OrganizationMember, error) {
+	member, err := s.orgRepo.GetMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check membership: %w", err)
+	}
+	if member == nil {
+		return nil, ErrForbidden
+	}
+	return member, nil
+}
+
+func (s *OrganizationService) requireOwner(ctx context.Context, orgID, userID string) error {
+	member, err := s.member(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if member.Role != models.OrgOwner {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func (s *OrganizationService) keepOwner(ctx context.Context, orgID string) error {
+	owners, err := s.orgRepo.CountOwners(ctx, orgID)
+	if err != nil {
+		return fmt.Errorf("failed to count owners: %w", err)
+	}
+	if owners <= 1 {
+		return ErrLastOwner
+	}
+	return nil
+}
+
+// emailInvite is sent as a background job so the request is not held up by
+// SMTP, and shutdown waits for it. It carries the token that answers the invite.
+func (s *OrganizationService) emailInvite(invite *models.MemberInvite, org *models.Organization, token string) {
+	subject := fmt.Sprintf("Invitation to join %s", org.Name)
+	body := fmt.Sprintf("You have been invited to join %s as %s.\n\nSign in to accept or decline the invitation with:\n\nInvitation ID: %s\nInvitation token: %s\n\nThe token can be used once; keep it private.\n",
+		org.Name, invite.Role, invite.InviteId, token)
+
+	s.runJob(func(context.Context) {
+		if err := s.notifications.Email(invite.Email, subject, body); err != nil {
+			s.logger.Warn("failed to email organization invite",
+				"error", err,
+				"organization_id", org.OrganizationId)
+		}
+	})
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/repos"
+	"github.com/zohirovs/internal/tracing"
+	websocket "github.com/zohirovs/internal/ws"
+)
+
+// questionEvent is pushed to the tender's WebSocket room. Question is omitted
+// for unpublished questions so their content stays between asker and client.
+type questionEvent struct {
+	Type       string           `json:"type"`
+	TenderId   string           `json:"tender_id"`
+	QuestionId string           `json:"question_id"`
+	Question   *models.Question `json:"question,omitempty"`
+}
+
+type QuestionService struct {
+	questionRepo  repos.QuestionRepo
+	tenderRepo    repos.TenderRepo
+	bidRepo       repos.BidRepo
+	notifications *NotificationService
+	invitations   *InvitationService
+	orgs          *OrganizationService
+	audit         *AuditService
+	ws            *websocket.Manager
+	cutoff        time.Duration
+	logger        *slog.Logger
+}
+
+func NewQuestionService(questionRepo repos.QuestionRepo, tenderRepo repos.TenderRepo, bidRepo repos.BidRepo, notifications *NotificationService, invitations *InvitationService, orgs *OrganizationService, audit *AuditService, ws *websocket.Manager, cutoff time.Duration, logger *slog.Logger) *QuestionService {
+	return &QuestionService{
+		questionRepo:  questionRepo,
+		tenderRepo:    tenderRepo,
+		bidRepo:       bidRepo,
+		notifications: notifications,
+		invitations:   invitations,
+		orgs:          orgs,
+		audit:         audit,
+		ws:            ws,
+		cutoff:        cutoff,
+		logger:        logger,
+	}
+}
+
+// AskQuestion records a contractor's question while the tender is open and
+// the cutoff before its deadline has not passed.
+func (s *QuestionService) AskQuestion(ctx context.Context, contractorID, tenderID string, req *models.CreateQuestion) (_ *models.Question, err error) {
+	ctx, span := tracing.Start(ctx, "QuestionService.AskQuestion")
+	defer tracing.End(span, &err)
+
+	tender, err := s.tenderRepo.GetTender(ctx, tenderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tender: %w", err)
+	}
+
+	if err := s.invitations.CheckAccess(ctx, tender, contractorID); err != nil {
+		return nil, err
+	}
+
+	if tender.Status != string(models.OPEN) || time.Now().After(tender.Deadline.Add(-s.cutoff)) {
+		return nil, ErrQuestionsClosed
+	}
+
+	question, err := s.questionRepo.CreateQuestion(ctx, &models.Question{
+		TenderId:     tenderID,
+		ContractorId: contractorID,
+		Anonymous:    req.Anonymous,
+		Body:         req.Body,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create question: %w", err)
+	}
+	s.audit.Record(ctx, models.AuditQuestionAsked, "question", question.QuestionId, nil, question)
+
+	s.notify(ctx, &models.Notification{
+		UserId:   tender.ClientId,
+		Type:     models.NotificationQuestionAsked,
+		Title:    fmt.Sprintf("New question on %q", tender.Title),
+		Message:  question.Body,
+		TenderId: tenderID,
+	})
+	s.broadcast(&questionEvent{
+		Type:       string(models.NotificationQuestionAsked),
+		TenderId:   tenderID,
+		QuestionId: question.QuestionId,
+	})
+
+	return question, nil
+}
+
+// ListQuestions returns what the viewer may see: the tender's client sees
+// everything, contractors see their own questions and published answers.
+func (s *QuestionService) ListQuestions(ctx context.Context, viewerID, tenderID string) (_ []*models.Question, err error) {
+	ctx, span := tracing.Start(ctx, "QuestionService.ListQuestions")
+	defer tracing.End(span, &err)
+
+	tender, err := s.tenderRepo.GetTender(ctx, tenderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tender: %w", err)
+	}
+
+	if err := s.invitations.CheckAccess(ctx, tender, viewerID); err != nil {
+		return nil, err
+	}
+
+	questions, err := s.questionRepo.ListQuestions(ctx, tenderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list questions: %w", err)
+	}
+
+	if s.orgs.CanViewTender(ctx, viewerID, tender) == nil {
+		return questions, nil
+	}
+
+	visible := make([]*models.Question, 0, len(questions))
+	for _, q := range questions {
+		switch {
+		case q.ContractorId == viewerID:
+			visible = append(visible, q)
+		case q.Published:
+			visible = append(visible, q.Redacted())
+		}
+	}
+	return visible, nil
+}
+
+// AnswerQuestion lets the tender's client answer a question. Publishing makes
+// the answer visible to every bidder and notifies them.
+func (s *QuestionService) AnswerQuestion(ctx context.Context, clientID, tenderID, questionID string, req *models.AnswerQuestion) (_ *models.Question, err error) {
+	ctx, span := tracing.Start(ctx, "QuestionService.AnswerQuestion")
+	defer tracing.End(span, &err)
+
+	tender, err := s.tenderRepo.GetTender(ctx, tenderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tender: %w", err)
+	}
+	if err := s.orgs.CanManageTender(ctx, clientID, tender); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.questionRepo.GetQuestion(ctx, questionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question: %w", err)
+	}
+	if existing.TenderId != tenderID {
+		return nil, fmt.Errorf("question not found: %s", questionID)
+	}
+
+	question, err := s.questionRepo.AnswerQuestion(ctx, questionID, req.Answer, req.Publish)
+	if err != nil {
+		return nil, fmt.Errorf("failed to answer question: %w", err)
+	}
+	s.audit.Record(ctx, models.AuditQuestionAnswered, "question", questionID, existing, question)
+
+	notifications := []*models.Notification{{
+		UserId:   question.ContractorId,
+		Type:     models.NotificationQuestionAnswer,
+		Title:    fmt.Sprintf("Your question on %q was answered", tender.Title),
+		Message:  question.Answer,
+		TenderId: tenderID,
+	}}
+
+	if question.Published {
+		bidders, err := s.bidders(ctx, tenderID)
+		if err != nil {
+			s.logger.WarnContext(ctx, "failed to list bidders for published answer",
+				"error", err,
+				"tender_id", tenderID)
+		}
+		for _, bidder := range bidders {
+			if bidder == question.ContractorId {
+				continue
+			}
+			notifications = append(notifications, &models.Notification{
+				UserId:   bidder,
+				Type:     models.NotificationAnswerPublished,
+				Title:    fmt.Sprintf("New clarification on %q", tender.Title),
+				Message:  question.Answer,
+				TenderId: tenderID,
+			})
+		}
+
+		s.broadcast(&questionEvent{
+			Type:       string(models.NotificationAnswerPublished),
+			TenderId:   tenderID,
+			QuestionId: questionID,
+			Question:   question.Redacted(),
+		})
+	} else {
+		s.broadcast(&questionEvent{
+			Type:       string(models.NotificationQuestionAnswer),
+			TenderId:   tenderID,
+			QuestionId: questionID,
+		})
+	}
+
+	s.notify(ctx, notifications...)
+
+	return question, nil
+}
+
+// bidders returns the distinct contractors that have bid on the tender.
+func (s *QuestionService) bidders(ctx context.Context, tenderID string) ([]string, error) {
+	bids, err := s.bidRepo.ListBidsForTender(ctx, tenderID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var bidders []string
+	for _, bid := range bids {
+		if !seen[bid.ContractorId] {
+			seen[bid.ContractorId] = true
+			bidders = append(bidders, bid.ContractorId)
+		}
+	}
+	return bidders, nil
+}
+
+// notify and broadcast are best effort: the question or answer is already
+// stored, so delivery failures are only logged.
+func (s *QuestionService) notify(ctx context.Context, notifications ...*models.Notification) {
+	if err := s.notifications.Notify(ctx, notifications...); err != nil {
+		s.logger.WarnContext(ctx, "failed to send question notifications", "error", err)
+	}
+}
+
+func (s *QuestionService) broadcast(event *questionEvent) {
+	if s.ws == nil {
+		return
+	}
+	if err := s.ws.BroadcastToTender(event.TenderId, event); err != nil {
+		s.logger.Warn("failed to broadcast question event",
+			"error", err,
+			"tender_id", event.TenderId)
+	}
+}
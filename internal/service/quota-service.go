@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/zohirovs/internal/config"
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/repos"
+	"github.com/zohirovs/internal/tracing"
+)
+
+const (
+	// Overrides are checked on every request, so they are kept in memory
+	// for a while. Changes made on another instance apply within this TTL.
+	quotaOverrideTTL = time.Minute
+	// maxCachedOverrides bounds the in-memory cache; it is cleared when full.
+	maxCachedOverrides = 10000
+)
+
+type cachedOverride struct {
+	override *models.QuotaOverride
+	expires  time.Time
+}
+
+type QuotaService struct {
+	quotaRepo repos.QuotaRepo
+	userRepo  repos.UserRepo
+	defaults  config.QuotaConfig
+	audit     *AuditService
+	logger    *slog.Logger
+
+	mu        sync.Mutex
+	overrides map[string]cachedOverride
+}
+
+func NewQuotaService(quotaRepo repos.QuotaRepo, userRepo repos.UserRepo, defaults config.QuotaConfig, audit *AuditService, logger *slog.Logger) *QuotaService {
+	return &QuotaService{
+		quotaRepo: quotaRepo,
+		userRepo:  userRepo,
+		defaults:  defaults,
+		audit:     audit,
+		logger:    logger,
+		overrides: make(map[string]cachedOverride),
+	}
+}
+
+// QuotaFor returns the quota in force for a user with the given role.
+func (s *QuotaService) QuotaFor(ctx context.Context, userID, role string) (_ models.Quota, err error) {
+	ctx, span := tracing.Start(ctx, "QuotaService.QuotaFor")
+	defer tracing.End(span, &err)
+
+	override, err := s.override(ctx, userID)
+	if err != nil {
+		return models.Quota{}, err
+	}
+	return override.Apply(s.roleQuota(models.Role(role))), nil
+}
+
+// GetQuota returns a user's quota along with any override, for admins.
+func (s *QuotaService) GetQuota(ctx context.Context, userID string) (_ *models.UserQuota, err error) {
+	ctx, span := tracing.Start(ctx, "QuotaService.GetQuota")
+	defer tracing.End(span, &err)
+
+	user, err := s.userRepo.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	override, err := s.quotaRepo.GetOverride(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quota: %w", err)
+	}
+
+	return &models.UserQuota{
+		UserId:   userID,
+		Role:     user.Role,
+		Quota:    override.Apply(s.roleQuota(user.Role)),
+		Override: override,
+	}, nil
+}
+
+func (s *QuotaService) ListOverrides(ctx context.Context) (_ []*models.QuotaOverride, err error) {
+	ctx, span := tracing.Start(ctx, "QuotaService.ListOverrides")
+	defer tracing.End(span, &err)
+
+	overrides, err := s.quotaRepo.ListOverrides(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quota overrides: %w", err)
+	}
+	return overrides, nil
+}
+
+// SetOverride replaces a user's override; fields left out of req fall back
+// to the user's role quota.
+func (s *QuotaService) SetOverride(ctx context.Context, adminID, userID string, req *models.SetQuotaOverride) (_ *models.UserQuota, err error) {
+	ctx, span := tracing.Start(ctx, "QuotaService.SetOverride")
+	defer tracing.End(span, &err)
+
+	if _, err := s.userRepo.GetUserByUserID(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	before, err := s.quotaRepo.GetOverride(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set quota override: %w", err)
+	}
+
+	override, err := s.quotaRepo.SetOverride(ctx, &models.QuotaOverride{
+		UserId:               userID,
+		RequestsPerMinute:    req.RequestsPerMinute,
+		TendersPerDay:        req.TendersPerDay,
+		BidsPerTenderPerHour: req.BidsPerTenderPerHour,
+		Note:                 req.Note,
+		UpdatedBy:            adminID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set quota override: %w", err)
+	}
+	s.forget(userID)
+	s.audit.Record(ctx, models.AuditQuotaOverridden, "user", userID, before, override)
+
+	return s.GetQuota(ctx, userID)
+}
+
+// DeleteOverride puts a user back on their role quota.
+func (s *QuotaService) DeleteOverride(ctx context.Context, userID string) (err error) {
+	ctx, span := tracing.Start(ctx, "QuotaService.DeleteOverride")
+	defer tracing.End(span, &err)
+
+	before, err := s.quotaRepo.GetOverride(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete quota override: %w", err)
+	}
+
+	if err := s.quotaRepo.DeleteOverride(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete quota override: %w", err)
+	}
+	s.forget(userID)
+	s.audit.Record(ctx, models.AuditQuotaOverrideReset, "user", userID, before, nil)
+	return nil
+}
+
+func (s *QuotaService) roleQuota(role models.Role) models.Quota {
+	switch role {
+	case models.Admin:
+		return models.Quota(s.defaults.Admin)
+	case models.Contractor:
+		return models.Quota(s.defaults.Contractor)
+	default:
+		return models.Quota(s.defaults.Client)
+	}
+}
+
+func (s *QuotaService) override(ctx context.Context, userID string) (*models.QuotaOverride, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	cached, ok := s.overrides[userID]
+	s.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.override, nil
+	}
+
+	override, err := s.quotaRepo.GetOverride(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quota override: %w", err)
+	}
+
+	s.mu.Lock()
+	if len(s.overrides) >= maxCachedOverrides {
+		clear(s.overrides)
+	}
+	s.overrides[userID] = cachedOverride{override: override, expires: now.Add(quotaOverrideTTL)}
+	s.mu.Unlock()
+
+	return override, nil
+}
+
+func (s *QuotaService) forget(userID string) {
+	s.mu.Lock()
+	delete(s.overrides, userID)
+	s.mu.Unlock()
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/repos"
+	"github.com/zohirovs/internal/tracing"
+)
+
+type ReputationService struct {
+	reviewRepo repos.ReviewRepo
+	tenderRepo repos.TenderRepo
+	bidRepo    repos.BidRepo
+	orgs       *OrganizationService
+	audit      *AuditService
+	logger     *slog.Logger
+}
+
+func NewReputationService(reviewRepo repos.ReviewRepo, tenderRepo repos.TenderRepo, bidRepo repos.BidRepo, orgs *OrganizationService, audit *AuditService, logger *slog.Logger) *ReputationService {
+	return &ReputationService{
+		reviewRepo: reviewRepo,
+		tenderRepo: tenderRepo,
+		bidRepo:    bidRepo,
+		orgs:       orgs,
+		audit:      audit,
+		logger:     logger,
+	}
+}
+
+// ReviewAwardedContractor lets the tender's managers rate a contractor whose
+// bid was accepted. A tender awarded lot by lot can have several winners, so
+// each winner can be reviewed once per tender.
+func (s *ReputationService) ReviewAwardedContractor(ctx context.Context, clientID, tenderID string, req *models.CreateReview) (_ *models.Review, err error) {
+	ctx, span := tracing.Start(ctx, "ReputationService.ReviewAwardedContractor")
+	defer tracing.End(span, &err)
+
+	tender, err := s.tenderRepo.GetTender(ctx, tenderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tender: %w", err)
+	}
+	if err := s.orgs.CanManageTender(ctx, clientID, tender); err != nil {
+		return nil, err
+	}
+	if tender.Status != string(models.AWARDED) {
+		return nil, ErrNotAwarded
+	}
+
+	bids, err := s.bidRepo.ListBidsForTender(ctx, tenderID, map[string]interface{}{"status": "accepted"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find awarded bid: %w", err)
+	}
+	if len(bids) == 0 {
+		return nil, ErrNotAwarded
+	}
+
+	// A contractor who won several lots has one bid per lot but gets one review.
+	var awarded *models.Bid
+	winners := make(map[string]bool)
+	for _, bid := range bids {
+		winners[bid.ContractorId] = true
+		if awarded == nil && (req.ContractorId == "" || bid.ContractorId == req.ContractorId) {
+			awarded = bid
+		}
+	}
+	switch {
+	case req.ContractorId == "" && len(winners) > 1:
+		return nil, ErrWinnerRequired
+	case awarded == nil:
+		return nil, ErrNotWinner
+	}
+
+	review := &models.Review{
+		TenderId:        tenderID,
+		BidId:           awarded.BidId,
+		ContractorId:    awarded.ContractorId,
+		ClientId:        clientID,
+		Rating:          req.Rating,
+		Comment:         req.Comment,
+		DeliveredOnTime: req.DeliveredOnTime,
+	}
+
+	created, err := s.reviewRepo.CreateReview(ctx, review)
+	if err != nil {
+		if errors.Is(err, repos.ErrDuplicateReview) {
+			return nil, ErrAlreadyReviewed
+		}
+		return nil, fmt.Errorf("failed to create review: %w", err)
+	}
+	s.audit.Record(ctx, models.AuditReviewCreated, "review", created.ReviewId, nil, created)
+
+	return created, nil
+}
+
+func (s *ReputationService) GetReputation(ctx context.Context, contractorID string) (_ *models.Reputation, err error) {
+	ctx, span := tracing.Start(ctx, "ReputationService.GetReputation")
+	defer tracing.End(span, &err)
+
+	reputation, err := s.reviewRepo.GetReputation(ctx, contractorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reputation: %w", err)
+	}
+	return reputation, nil
+}
+
+func (s *ReputationService) ListReviews(ctx context.Context, contractorID string) (_ []*models.Review, err error) {
+	ctx, span := tracing.Start(ctx, "ReputationService.ListReviews")
+	defer tracing.End(span, &err)
+
+	reviews, err := s.reviewRepo.ListReviewsByContractor(ctx, contractorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+// AttachToBids sets ContractorReputation on each bid for side-by-side evaluation.
+func (s *ReputationService) AttachToBids(ctx context.Context, bids []*models.Bid) (err error) {
+	ctx, span := tracing.Start(ctx, "ReputationService.AttachToBids")
+	defer tracing.End(span, &err)
+
+	if len(bids) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(bids))
+	seen := make(map[string]bool, len(bids))
+	for _, bid := range bids {
+		if !seen[bid.ContractorId] {
+			seen[bid.ContractorId] = true
+			ids = append(ids, bid.ContractorId)
+		}
+	}
+
+	reputations, err := s.reviewRepo.GetReputations(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get reputations: %w", err)
+	}
+
+	for _, bid := range bids {
+		bid.ContractorReputation = reputations[bid.ContractorId]
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zohirovs/internal/config"
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/repos"
+	"github.com/zohirovs/internal/storage/blob"
+	"github.com/zohirovs/internal/tracing"
+)
+
+// RetentionService permanently removes soft-deleted tenders, bids and users
+// once they can no longer be restored, together with the records that
+// belong to them.
+type RetentionService struct {
+	tenderRepo       repos.TenderRepo
+	bidRepo          repos.BidRepo
+	userRepo         repos.UserRepo
+	attachmentRepo   repos.AttachmentRepo
+	questionRepo     repos.QuestionRepo
+	invitationRepo   repos.InvitationRepo
+	reviewRepo       repos.ReviewRepo
+	notificationRepo repos.NotificationRepo
+	savedSearchRepo  repos.SavedSearchRepo
+	locks            repos.LockRepo
+	files            blob.Store
+	audit            *AuditService
+	cfg              config.RetentionConfig
+	logger           *slog.Logger
+	owner            string
+}
+
+const (
+	retentionLock = "retention"
+	// retentionLockStale is how long a purge may go without refreshing its
+	// lock before another instance takes over, e.g. after a crash.
+	retentionLockStale = 5 * time.Minute
+)
+
+func NewRetentionService(tenderRepo repos.TenderRepo, bidRepo repos.BidRepo, userRepo repos.UserRepo, attachmentRepo repos.AttachmentRepo, questionRepo repos.QuestionRepo, invitationRepo repos.InvitationRepo, reviewRepo repos.ReviewRepo, notificationRepo repos.NotificationRepo, savedSearchRepo repos.SavedSearchRepo, locks repos.LockRepo, files blob.Store, audit *AuditService, cfg config.RetentionConfig, logger *slog.Logger) *RetentionService {
+	hostname, _ := os.Hostname()
+
+	return &RetentionService{
+		tenderRepo:       tenderRepo,
+		bidRepo:          bidRepo,
+		userRepo:         userRepo,
+		attachmentRepo:   attachmentRepo,
+		questionRepo:     questionRepo,
+		invitationRepo:   invitationRepo,
+		reviewRepo:       reviewRepo,
+		notificationRepo: notificationRepo,
+		savedSearchRepo:  savedSearchRepo,
+		locks:            locks,
+		files:            files,
+		audit:            audit,
+		cfg:              cfg,
+		logger:           logger,
+		owner:            fmt.Sprintf("%s/%d/%s", hostname, os.Getpid(), uuid.NewString()),
+	}
+}
+
+// Purge removes everything soft-deleted longer ago than the retention
+// period. Only one instance purges at a time; the others skip the run.
+//
+// The records that belong to purged tenders, bids and users go first:
+// attachments and their files, questions, invitations, reviews,
+// notifications and saved searches. If any of those fails, the soft-deleted
+// records are kept so the next run finds and retries them. Each step carries
+// on past failures and the first error is returned.
+func (s *RetentionService) Purge(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "RetentionService.Purge")
+	defer tracing.End(span, &err)
+
+	acquired, err := s.locks.AcquireLock(ctx, retentionLock, s.owner, retentionLockStale)
+	if err != nil {
+		return err
+	}
+	if !acquired {
+		s.logger.DebugContext(ctx, "retention purge is running elsewhere")
+		return nil
+	}
+	defer func() {
+		// Release with a fresh context so a cancelled run still unlocks.
+		releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+		defer cancel()
+		s.locks.ReleaseLock(releaseCtx, retentionLock, s.owner)
+	}()
+
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+	go s.holdLock(ctx, stop)
+
+	cutoff := time.Now().UTC().Add(-s.cfg.Period)
+
+	var firstErr error
+	fail := func(resource string, err error) {
+		s.logger.ErrorContext(ctx, "failed to purge deleted records",
+			"error", err,
+			"resource_type", resource)
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	purged := func(resource string, count int64) {
+		if count == 0 {
+			return
+		}
+		s.logger.InfoContext(ctx, "purged deleted records",
+			"resource_type", resource,
+			"count", count,
+			"cutoff", cutoff)
+		s.audit.Record(ctx, models.AuditRecordsPurged, resource, "", nil,
+			map[string]any{"count": count, "deleted_before": cutoff})
+	}
+
+	tenderIDs, err := s.tenderRepo.ListPurgeableTenders(ctx, cutoff)
+	if err != nil {
+		fail("tender", err)
+	}
+	bidIDs, err := s.bidRepo.ListPurgeableBids(ctx, cutoff)
+	if err != nil {
+		fail("bid", err)
+	}
+	userIDs, err := s.userRepo.ListPurgeableUsers(ctx, cutoff)
+	if err != nil {
+		fail("user", err)
+	}
+	if firstErr != nil {
+		return firstErr
+	}
+
+	dependents := []struct {
+		resource string
+		purge    func() (int64, error)
+	}{
+		{"attachment", func() (int64, error) { return s.purgeAttachments(ctx, tenderIDs, bidIDs) }},
+		{"question", func() (int64, error) { return s.questionRepo.PurgeQuestions(ctx, tenderIDs) }},
+		{"invitation", func() (int64, error) { return s.invitationRepo.PurgeInvitations(ctx, tenderIDs, userIDs) }},
+		{"review", func() (int64, error) { return s.reviewRepo.PurgeReviews(ctx, tenderIDs) }},
+		{"notification", func() (int64, error) { return s.notificationRepo.PurgeNotifications(ctx, tenderIDs, userIDs) }},
+		{"saved_search", func() (int64, error) { return s.savedSearchRepo.PurgeSavedSearches(ctx, userIDs) }},
+	}
+	for _, d := range dependents {
+		count, err := d.purge()
+		purged(d.resource, count)
+		if err != nil {
+			fail(d.resource, err)
+		}
+	}
+	if firstErr != nil {
+		return firstErr
+	}
+
+	purges := []struct {
+		resource string
+		purge    func(context.Context, time.Time) (int64, error)
+	}{
+		{"tender", s.tenderRepo.PurgeDeletedTenders},
+		{"bid", s.bidRepo.PurgeDeletedBids},
+		{"user", s.userRepo.PurgeDeletedUsers},
+	}
+	for _, p := range purges {
+		count, err := p.purge(ctx, cutoff)
+		if err != nil {
+			fail(p.resource, err)
+			continue
+		}
+		purged(p.resource, count)
+	}
+
+	return firstErr
+}
+
+// purgeAttachments removes the files, then the records, of the attachments
+// of the tenders and bids. Records whose file could not be removed are kept
+// so the file is retried.
+func (s *RetentionService) purgeAttachments(ctx context.Context, tenderIDs, bidIDs []string) (int64, error) {
+	attachments, err := s.attachmentRepo.ListAttachmentsFor(ctx, tenderIDs, bidIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	var firstErr error
+	ids := make([]string, 0, len(attachments))
+	for _, attachment := range attachments {
+		if err := s.files.Delete(ctx, attachment.StorageKey); err != nil {
+			s.logger.ErrorContext(ctx, "failed to delete attachment file",
+				"error", err,
+				"attachment_id", attachment.AttachmentId)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to delete attachment file: %w", err)
+			}
+			continue
+		}
+		ids = append(ids, attachment.AttachmentId)
+	}
+
+	count, err := s.attachmentRepo.PurgeAttachments(ctx, ids)
+	if err != nil {
+		return count, err
+	}
+	return count, firstErr
+}
+
+// holdLock refreshes the purge lock until ctx ends, and calls stop if the
+// lock is lost so the run does not carry on alongside another instance.
+func (s *RetentionService) holdLock(ctx context.Context, stop context.CancelFunc) {
+	ticker := time.NewTicker(retentionLockStale / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			held, err := s.locks.RefreshLock(ctx, retentionLock, s.owner)
+			if err != nil {
+				// Try again on the next tick; the lock is not stale yet.
+				continue
+			}
+			if !held {
+				s.logger.ErrorContext(ctx, "lost the retention lock, stopping the purge")
+				stop()
+				return
+			}
+		}
+	}
+}
+
+// Run purges every interval until ctx is cancelled.
+func (s *RetentionService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Purge(ctx); err != nil {
+				s.logger.ErrorContext(ctx, "failed to purge deleted records", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/repos"
+	"github.com/zohirovs/internal/tracing"
+)
+
+type SavedSearchService struct {
+	savedSearchRepo repos.SavedSearchRepo
+	notifications   *NotificationService
+	audit           *AuditService
+	logger          *slog.Logger
+}
+
+func NewSavedSearchService(savedSearchRepo repos.SavedSearchRepo, notifications *NotificationService, audit *AuditService, logger *slog.Logger) *SavedSearchService {
+	return &SavedSearchService{
+		savedSearchRepo: savedSearchRepo,
+		notifications:   notifications,
+		audit:           audit,
+		logger:          logger,
+	}
+}
+
+func (s *SavedSearchService) CreateSavedSearch(ctx context.Context, userID string, req *models.CreateSavedSearch) (_ *models.SavedSearch, err error) {
+	ctx, span := tracing.Start(ctx, "SavedSearchService.CreateSavedSearch")
+	defer tracing.End(span, &err)
+
+	if req.MaxBudget > 0 && req.MinBudget > req.MaxBudget {
+		return nil, fmt.Errorf("min_budget must not exceed max_budget")
+	}
+
+	search := &models.SavedSearch{
+		UserId:      userID,
+		Name:        req.Name,
+		CategoryId:  req.CategoryId,
+		Tags:        models.NormalizeTags(req.Tags),
+		Keywords:    req.Keywords,
+		MinBudget:   req.MinBudget,
+		MaxBudget:   req.MaxBudget,
+		EmailDigest: req.EmailDigest,
+	}
+
+	created, err := s.savedSearchRepo.CreateSavedSearch(ctx, search)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create saved search: %w", err)
+	}
+	s.audit.Record(ctx, models.AuditSavedSearchCreated, "saved_search", created.SearchId, nil, created)
+	return created, nil
+}
+
+func (s *SavedSearchService) ListSavedSearches(ctx context.Context, userID string) (_ []*models.SavedSearch, err error) {
+	ctx, span := tracing.Start(ctx, "SavedSearchService.ListSavedSearches")
+	defer tracing.End(span, &err)
+
+	searches, err := s.savedSearchRepo.ListSavedSearches(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list saved searches: %w", err)
+	}
+	return searches, nil
+}
+
+func (s *SavedSearchService) DeleteSavedSearch(ctx context.Context, userID, searchID string) (err error) {
+	ctx, span := tracing.Start(ctx, "SavedSearchService.DeleteSavedSearch")
+	defer tracing.End(span, &err)
+
+	if err := s.savedSearchRepo.DeleteSavedSearch(ctx, userID, searchID); err != nil {
+		return fmt.Errorf("failed to delete saved search: %w", err)
+	}
+	s.audit.Record(ctx, models.AuditSavedSearchDeleted, "saved_search", searchID, nil, nil)
+	return nil
+}
+
+// MatchTender notifies every contractor with a saved search matching the
+// newly published tender. A contractor with several matching searches gets a
+// single notification, included in the email digest if any match asked for it.
+func (s *SavedSearchService) MatchTender(ctx context.Context, tender *models.Tender) (err error) {
+	ctx, span := tracing.Start(ctx, "SavedSearchService.MatchTender")
+	defer tracing.End(span, &err)
+
+	candidates, err := s.savedSearchRepo.FindCandidates(ctx, tender)
+	if err != nil {
+		return fmt.Errorf("failed to find saved searches: %w", err)
+	}
+
+	byUser := make(map[string]*models.Notification)
+	var notifications []*models.Notification
+	for _, search := range candidates {
+		if search.UserId == tender.ClientId || !search.Matches(tender) {
+			continue
+		}
+
+		if notification, ok := byUser[search.UserId]; ok {
+			notification.Digest = notification.Digest || search.EmailDigest
+			continue
+		}
+
+		notification := &models.Notification{
+			UserId:   search.UserId,
+			Type:     models.NotificationTenderMatch,
+			Title:    fmt.Sprintf("New tender: %s", tender.Title),
+			Message:  fmt.Sprintf("Matches your saved search %q. Budget %d, deadline %s.", search.Name, tender.Budget, tender.Deadline.Format("2006-01-02")),
+			TenderId: tender.TenderId,
+			Digest:   search.EmailDigest,
+		}
+		byUser[search.UserId] = notification
+		notifications = append(notifications, notification)
+	}
+
+	if err := s.notifications.Notify(ctx, notifications...); err != nil {
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "matched tender against saved searches",
+		"tender_id", tender.TenderId,
+		"notified", len(notifications))
+	return nil
+}
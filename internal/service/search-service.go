@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/repos"
+	"github.com/zohirovs/internal/tracing"
+)
+
+const (
+	snippetRadius   = 60
+	maxSnippets     = 3
+	minStemLength   = 4
+	highlightPrefix = "<em>"
+	highlightSuffix = "</em>"
+)
+
+type SearchService struct {
+	index  repos.TenderSearchIndex
+	logger *slog.Logger
+}
+
+func NewSearchService(index repos.TenderSearchIndex, logger *slog.Logger) *SearchService {
+	return &SearchService{
+		index:  index,
+		logger: logger,
+	}
+}
+
+func (s *SearchService) SearchTenders(ctx context.Context, query *models.TenderSearchQuery) (_ *models.TenderSearchResult, err error) {
+	ctx, span := tracing.Start(ctx, "SearchService.SearchTenders")
+	defer tracing.End(span, &err)
+
+	if query.MinBudget > 0 && query.MaxBudget > 0 && query.MinBudget > query.MaxBudget {
+		return nil, fmt.Errorf("min_budget must not exceed max_budget")
+	}
+
+	result, err := s.index.SearchTenders(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search tenders: %w", err)
+	}
+
+	pattern := highlightPattern(query.Query)
+	if pattern != nil {
+		for _, hit := range result.Hits {
+			hit.Highlights = map[string][]string{}
+			if snippets := highlight(hit.Title, pattern, len(hit.Title)); len(snippets) > 0 {
+				hit.Highlights["title"] = snippets
+			}
+			if snippets := highlight(hit.Description, pattern, snippetRadius); len(snippets) > 0 {
+				hit.Highlights["description"] = snippets
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// Index, SetAccess and Remove keep the search index in step with the tender collection.
+// The index is derived data, so failures are logged rather than returned;
+// migration 10 can be re-run to rebuild it.
+func (s *SearchService) Index(ctx context.Context, tender *models.Tender) {
+	ctx, span := tracing.Start(ctx, "SearchService.Index")
+	defer span.End()
+
+	// Drafts are private to their client and must not be searchable.
+	if tender.Status == string(models.DRAFT) {
+		s.Remove(ctx, tender.TenderId)
+		return
+	}
+	if err := s.index.IndexTender(ctx, tender); err != nil {
+		s.logger.WarnContext(ctx, "failed to update tender search index",
+			"error", err,
+			"tender_id", tender.TenderId)
+	}
+}
+
+func (s *SearchService) SetAccess(ctx context.Context, id string, contractorIDs []string) {
+	ctx, span := tracing.Start(ctx, "SearchService.SetAccess")
+	defer span.End()
+
+	if err := s.index.SetTenderAccess(ctx, id, contractorIDs); err != nil {
+		s.logger.WarnContext(ctx, "failed to update tender search access",
+			"error", err,
+			"tender_id", id)
+	}
+}
+
+func (s *SearchService) Remove(ctx context.Context, id string) {
+	ctx, span := tracing.Start(ctx, "SearchService.Remove")
+	defer span.End()
+
+	if err := s.index.RemoveTender(ctx, id); err != nil {
+		s.logger.WarnContext(ctx, "failed to remove tender from search index",
+			"error", err,
+			"tender_id", id)
+	}
+}
+
+// highlightPattern builds a case-insensitive pattern matching words that start
+// with a crude stem of any query term, so "roofing" also highlights "roof".
+// It only approximates the stemming MongoDB applies when matching.
+func highlightPattern(query string) *regexp.Regexp {
+	var stems []string
+	for _, term := range strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		term = strings.ToLower(term)
+		for _, suffix := range []string{"ing", "ed", "es", "s"} {
+			if len(term)-len(suffix) >= minStemLength && strings.HasSuffix(term, suffix) {
+				term = strings.TrimSuffix(term, suffix)
+				break
+			}
+		}
+		stems = append(stems, regexp.QuoteMeta(term))
+	}
+	if len(stems) == 0 {
+		return nil
+	}
+	// Go's \b only understands ASCII, so the word boundary is spelled out to
+	// support non-Latin scripts; the word itself is capture group 1.
+	return regexp.MustCompile(`(?i)(?:^|[^\pL\pN])((?:` + strings.Join(stems, "|") + `)[\pL\pN]*)`)
+}
+
+// highlight returns up to maxSnippets excerpts of text around matches of
+// pattern, each extending radius bytes either side, with matches wrapped in
+// <em> tags. The snippets are HTML: the text itself is escaped, so only the
+// <em> tags are markup.
+func highlight(text string, pattern *regexp.Regexp, radius int) []string {
+	var matches [][2]int
+	for _, m := range pattern.FindAllStringSubmatchIndex(text, -1) {
+		matches = append(matches, [2]int{m[2], m[3]})
+	}
+	if len(matches) == 0 {
+		return nil
+	}
+
+	var snippets []string
+	for i := 0; i < len(matches) && len(snippets) < maxSnippets; {
+		start := max(matches[i][0]-radius, 0)
+		end := min(matches[i][1]+radius, len(text))
+
+		// Merge following matches that fall inside this window.
+		j := i + 1
+		for j < len(matches) && matches[j][0] < end {
+			end = min(max(end, matches[j][1]+radius), len(text))
+			j++
+		}
+
+		start, end = wordBoundary(text, start, end)
+
+		var b strings.Builder
+		if start > 0 {
+			b.WriteString("…")
+		}
+		pos := start
+		for _, m := range matches[i:j] {
+			b.WriteString(html.EscapeString(text[pos:m[0]]))
+			b.WriteString(highlightPrefix)
+			b.WriteString(html.EscapeString(text[m[0]:m[1]]))
+			b.WriteString(highlightSuffix)
+			pos = m[1]
+		}
+		b.WriteString(html.EscapeString(text[pos:end]))
+		if end < len(text) {
+			b.WriteString("…")
+		}
+
+		snippets = append(snippets, b.String())
+		i = j
+	}
+
+	return snippets
+}
+
+// wordBoundary widens start and end to the nearest spaces so snippets never
+// split a word or a multi-byte character.
+func wordBoundary(text string, start, end int) (int, int) {
+	for start > 0 && text[start-1] != ' ' {
+		start--
+	}
+	for end < len(text) && text[end] != ' ' {
+		end++
+	}
+	return start, end
+}
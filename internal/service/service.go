@@ -9,10 +9,15 @@
 package service
 
 import (
+	"context"
 	"log/slog"
 
+	"github.com/zohirovs/internal/config"
+	"github.com/zohirovs/internal/mailer"
 	"github.com/zohirovs/internal/storage"
+	"github.com/zohirovs/internal/storage/blob"
 	"github.com/zohirovs/internal/storage/redis"
+	websocket "github.com/zohirovs/internal/ws"
 )
 
 type (
@@ -21,14 +26,59 @@ type (
 		Notification *NotificationService
 		Tender       *TenderService
 		Bid          *BidService
+		Attachment   *AttachmentService
+		Contractor   *ContractorService
+		Reputation   *ReputationService
+		Category     *CategoryService
+		SavedSearch  *SavedSearchService
+		Search       *SearchService
+		Question     *QuestionService
+		Invitation   *InvitationService
+		Lot          *LotService
+		Template     *TemplateService
+		Organization *OrganizationService
+		Audit        *AuditService
+		Retention    *RetentionService
+		Quota        *QuotaService
+		Health       *HealthService
 	}
+
+	// JobRunner starts a background job that shutdown cancels and waits for.
+	JobRunner func(job func(context.Context))
 )
 
-func NewService(cache *redis.RedisService, logger *slog.Logger, repo storage.StorageI) *Service {
+func NewService(cache *redis.RedisService, logger *slog.Logger, repo storage.StorageI, files blob.Store, ws *websocket.Manager, runJob JobRunner, cfg *config.Config) *Service {
+	audit := NewAuditService(repo.AuditRepo(), logger)
+	notification := NewNotificationService(repo.NotificationRepo(), repo.UserRepo(), cache.Notification, mailer.New(cfg.Email), audit, logger)
+	organization := NewOrganizationService(repo.OrganizationRepo(), repo.UserRepo(), repo.TenderRepo(), repo.BidRepo(), notification, audit, runJob, logger)
+	contractor := NewContractorService(repo.ContractorRepo(), cache.Contractor, audit, logger)
+	reputation := NewReputationService(repo.ReviewRepo(), repo.TenderRepo(), repo.BidRepo(), organization, audit, logger)
+	category := NewCategoryService(repo.CategoryRepo(), audit, logger)
+	savedSearch := NewSavedSearchService(repo.SavedSearchRepo(), notification, audit, logger)
+	search := NewSearchService(repo.TenderSearchIndex(), logger)
+	invitation := NewInvitationService(repo.InvitationRepo(), repo.TenderRepo(), repo.UserRepo(), search, notification, organization, audit, runJob, logger)
+
+	tender := NewTenderService(repo.TenderRepo(), repo.BidRepo(), category, savedSearch, search, invitation, notification, organization, audit, cache.Tender, runJob, logger)
+
 	return &Service{
-		User:         NewUserService(repo.UserRepo(), logger),
-		Notification: NewNotificationService(repo.NotificationRepo(), cache.Notification, logger),
-		Tender:       NewTenderService(repo.TenderRepo(), cache.Tender, logger),
-		Bid:          NewBidService(repo.BidRepo(), repo.TenderRepo(), logger),
+		User:         NewUserService(repo.UserRepo(), cache.RateLimit, cfg.RateLimit, audit, logger),
+		Notification: notification,
+		Tender:       tender,
+		Bid:          NewBidService(repo.BidRepo(), repo.TenderRepo(), contractor, reputation, invitation, organization, audit, logger),
+		Attachment:   NewAttachmentService(repo.AttachmentRepo(), repo.TenderRepo(), repo.BidRepo(), files, organization, audit, cfg.Attachments, logger),
+		Contractor:   contractor,
+		Reputation:   reputation,
+		Category:     category,
+		SavedSearch:  savedSearch,
+		Search:       search,
+		Invitation:   invitation,
+		Question:     NewQuestionService(repo.QuestionRepo(), repo.TenderRepo(), repo.BidRepo(), notification, invitation, organization, audit, ws, cfg.Questions.Cutoff, logger),
+		Lot:          NewLotService(repo.TenderRepo(), repo.BidRepo(), tender, notification, organization, audit, logger),
+		Template:     NewTemplateService(repo.TemplateRepo(), repo.TenderRepo(), tender, organization, audit, logger),
+		Organization: organization,
+		Audit:        audit,
+		Retention:    NewRetentionService(repo.TenderRepo(), repo.BidRepo(), repo.UserRepo(), repo.AttachmentRepo(), repo.QuestionRepo(), repo.InvitationRepo(), repo.ReviewRepo(), repo.NotificationRepo(), repo.SavedSearchRepo(), repo.LockRepo(), files, audit, cfg.Retention, logger),
+		Quota:        NewQuotaService(repo.QuotaRepo(), repo.UserRepo(), cfg.Quotas, audit, logger),
+		Health:       NewHealthService(repo, cache, logger),
 	}
 }
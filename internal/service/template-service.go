@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/repos"
+	"github.com/zohirovs/internal/tracing"
+)
+
+type TemplateService struct {
+	templateRepo repos.TemplateRepo
+	tenderRepo   repos.TenderRepo
+	tenders      *TenderService
+	orgs         *OrganizationService
+	audit        *AuditService
+	logger       *slog.Logger
+}
+
+func NewTemplateService(templateRepo repos.TemplateRepo, tenderRepo repos.TenderRepo, tenders *TenderService, orgs *OrganizationService, audit *AuditService, logger *slog.Logger) *TemplateService {
+	return &TemplateService{
+		templateRepo: templateRepo,
+		tenderRepo:   tenderRepo,
+		tenders:      tenders,
+		orgs:         orgs,
+		audit:        audit,
+		logger:       logger,
+	}
+}
+
+func (s *TemplateService) CreateTemplate(ctx context.Context, clientID string, req *models.SaveTemplate) (_ *models.TenderTemplate, err error) {
+	ctx, span := tracing.Start(ctx, "TemplateService.CreateTemplate")
+	defer tracing.End(span, &err)
+
+	template, err := s.templateRepo.CreateTemplate(ctx, newTemplate(clientID, req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create template: %w", err)
+	}
+	s.audit.Record(ctx, models.AuditTemplateCreated, "template", template.TemplateId, nil, template)
+	return template, nil
+}
+
+func (s *TemplateService) GetTemplate(ctx context.Context, clientID, id string) (_ *models.TenderTemplate, err error) {
+	ctx, span := tracing.Start(ctx, "TemplateService.GetTemplate")
+	defer tracing.End(span, &err)
+
+	template, err := s.templateRepo.GetTemplate(ctx, clientID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+	return template, nil
+}
+
+func (s *TemplateService) ListTemplates(ctx context.Context, clientID string) (_ []*models.TenderTemplate, err error) {
+	ctx, span := tracing.Start(ctx, "TemplateService.ListTemplates")
+	defer tracing.End(span, &err)
+
+	templates, err := s.templateRepo.ListTemplates(ctx, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+	return templates, nil
+}
+
+func (s *TemplateService) UpdateTemplate(ctx context.Context, clientID, id string, req *models.SaveTemplate) (_ *models.TenderTemplate, err error) {
+	ctx, span := tracing.Start(ctx, "TemplateService.UpdateTemplate")
+	defer tracing.End(span, &err)
+
+	before, err := s.templateRepo.GetTemplate(ctx, clientID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+
+	template := newTemplate(clientID, req)
+	template.TemplateId = id
+
+	updated, err := s.templateRepo.UpdateTemplate(ctx, template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update template: %w", err)
+	}
+	s.audit.Record(ctx, models.AuditTemplateUpdated, "template", id, before, updated)
+	return updated, nil
+}
+
+func (s *TemplateService) DeleteTemplate(ctx context.Context, clientID, id string) (err error) {
+	ctx, span := tracing.Start(ctx, "TemplateService.DeleteTemplate")
+	defer tracing.End(span, &err)
+
+	before, err := s.templateRepo.GetTemplate(ctx, clientID, id)
+	if err != nil {
+		return fmt.Errorf("failed to get template: %w", err)
+	}
+
+	if err := s.templateRepo.DeleteTemplate(ctx, clientID, id); err != nil {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+	s.audit.Record(ctx, models.AuditTemplateDeleted, "template", id, before, nil)
+	return nil
+}
+
+// CreateFromTemplate creates a draft tender from one of the client's templates.
+func (s *TemplateService) CreateFromTemplate(ctx context.Context, clientID, templateID string, deadline time.Time, timeZone string, budget int) (_ *models.Tender, err error) {
+	ctx, span := tracing.Start(ctx, "TemplateService.CreateFromTemplate")
+	defer tracing.End(span, &err)
+
+	template, err := s.templateRepo.GetTemplate(ctx, clientID, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+
+	if budget == 0 {
+		budget = template.Budget
+	}
+	if budget == 0 {
+		return nil, ErrBudgetRequired
+	}
+
+	tender := &models.Tender{
+		ClientId:    clientID,
+		Title:       template.Title,
+		Description: template.Description,
+		Budget:      budget,
+		Status:      string(models.DRAFT),
+		Deadline:    deadline,
+		TimeZone:    timeZone,
+		CategoryId:  template.CategoryId,
+		Tags:        template.Tags,
+
+		LineItems:          models.NewLineItems(template.LineItems),
+		EvaluationCriteria: template.EvaluationCriteria,
+
+		RequiresVerified:       template.RequiresVerified,
+		RequiredCertifications: template.RequiredCertifications,
+	}
+
+	return s.tenders.CreateTender(ctx, tender)
+}
+
+// CloneTender copies a tender the client manages into a new draft with a new
+// deadline. Lot deadlines move by the same amount as the tender deadline;
+// bids, awards, invitations and attachments are not copied.
+func (s *TemplateService) CloneTender(ctx context.Context, clientID, tenderID string, deadline time.Time, timeZone string, budget int) (_ *models.Tender, err error) {
+	ctx, span := tracing.Start(ctx, "TemplateService.CloneTender")
+	defer tracing.End(span, &err)
+
+	source, err := s.tenderRepo.GetTender(ctx, tenderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tender: %w", err)
+	}
+	if err := s.orgs.CanManageTender(ctx, clientID, source); err != nil {
+		return nil, err
+	}
+
+	if timeZone == "" {
+		timeZone = source.TimeZone
+	}
+	if budget == 0 {
+		budget = source.Budget
+	}
+
+	clone := &models.Tender{
+		ClientId:      clientID,
+		Title:         source.Title,
+		Description:   source.Description,
+		Budget:        budget,
+		Status:        string(models.DRAFT),
+		Deadline:      deadline,
+		TimeZone:      timeZone,
+		AttachmentUrl: source.AttachmentUrl,
+		CategoryId:    source.CategoryId,
+		Tags:          source.Tags,
+		Visibility:    source.Visibility,
+
+		OrganizationId: source.OrganizationId,
+
+		LineItems:          copyLineItems(source.LineItems),
+		EvaluationCriteria: source.EvaluationCriteria,
+
+		RequiresVerified:       source.RequiresVerified,
+		RequiredCertifications: source.RequiredCertifications,
+	}
+
+	shift := deadline.Sub(source.Deadline)
+	for _, lot := range source.Lots {
+		copied := models.Lot{
+			Title:     lot.Title,
+			Quantity:  lot.Quantity,
+			Unit:      lot.Unit,
+			Budget:    lot.Budget,
+			LineItems: copyLineItems(lot.LineItems),
+		}
+		if lot.Deadline != nil {
+			lotDeadline := lot.Deadline.Add(shift)
+			copied.Deadline = &lotDeadline
+		}
+		clone.Lots = append(clone.Lots, copied)
+	}
+
+	return s.tenders.CreateTender(ctx, clone)
+}
+
+func newTemplate(clientID string, req *models.SaveTemplate) *models.TenderTemplate {
+	return &models.TenderTemplate{
+		ClientId:    clientID,
+		Name:        req.Name,
+		Title:       req.Title,
+		Description: req.Description,
+		Budget:      req.Budget,
+		CategoryId:  req.CategoryId,
+		Tags:        models.NormalizeTags(req.Tags),
+
+		LineItems:          req.LineItems,
+		EvaluationCriteria: req.EvaluationCriteria,
+
+		RequiresVerified:       req.RequiresVerified,
+		RequiredCertifications: req.RequiredCertifications,
+	}
+}
+
+// copyLineItems copies items without their IDs, which the new tender assigns.
+func copyLineItems(items []models.LineItem) []models.LineItem {
+	var copied []models.LineItem
+	for _, item := range items {
+		copied = append(copied, models.LineItem{
+			Description: item.Description,
+			Unit:        item.Unit,
+			Quantity:    item.Quantity,
+		})
+	}
+	return copied
+}
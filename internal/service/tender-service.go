@@ -4,27 +4,66 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"time"
 
+	"github.com/zohirovs/internal/metrics"
 	"github.com/zohirovs/internal/models"
 	"github.com/zohirovs/internal/repos"
 	"github.com/zohirovs/internal/storage/redis"
+	"github.com/zohirovs/internal/tracing"
 )
 
 type TenderService struct {
-	tenderRepo  repos.TenderRepo
-	tenderCache *redis.TenderCaching
-	logger      *slog.Logger
+	tenderRepo    repos.TenderRepo
+	bidRepo       repos.BidRepo
+	categories    *CategoryService
+	savedSearches *SavedSearchService
+	search        *SearchService
+	invitations   *InvitationService
+	notifications *NotificationService
+	orgs          *OrganizationService
+	audit         *AuditService
+	tenderCache   *redis.TenderCaching
+	runJob        JobRunner
+	logger        *slog.Logger
 }
 
-func NewTenderService(tenderRepo repos.TenderRepo, cache *redis.TenderCaching, logger *slog.Logger) *TenderService {
+func NewTenderService(tenderRepo repos.TenderRepo, bidRepo repos.BidRepo, categories *CategoryService, savedSearches *SavedSearchService, search *SearchService, invitations *InvitationService, notifications *NotificationService, orgs *OrganizationService, audit *AuditService, cache *redis.TenderCaching, runJob JobRunner, logger *slog.Logger) *TenderService {
 	return &TenderService{
-		tenderRepo:  tenderRepo,
-		tenderCache: cache,
-		logger:      logger,
+		tenderRepo:    tenderRepo,
+		bidRepo:       bidRepo,
+		categories:    categories,
+		savedSearches: savedSearches,
+		search:        search,
+		invitations:   invitations,
+		notifications: notifications,
+		orgs:          orgs,
+		audit:         audit,
+		tenderCache:   cache,
+		runJob:        runJob,
+		logger:        logger,
 	}
 }
 
-func (s *TenderService) CreateTender(ctx context.Context, tender *models.Tender) (*models.Tender, error) {
+func (s *TenderService) CreateTender(ctx context.Context, tender *models.Tender) (_ *models.Tender, err error) {
+	ctx, span := tracing.Start(ctx, "TenderService.CreateTender")
+	defer tracing.End(span, &err)
+
+	if tender.OrganizationId != "" {
+		if err := s.orgs.CheckActingFor(ctx, tender.ClientId, tender.OrganizationId, models.Client); err != nil {
+			return nil, err
+		}
+	}
+
+	if tender.CategoryId != "" {
+		path, err := s.categories.Lineage(ctx, tender.CategoryId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve tender category: %w", err)
+		}
+		tender.CategoryPath = path
+	}
+	tender.Tags = models.NormalizeTags(tender.Tags)
+
 	createdTender, err := s.tenderRepo.CreateTender(ctx, tender)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create tender: %w", err)
@@ -32,22 +71,33 @@ func (s *TenderService) CreateTender(ctx context.Context, tender *models.Tender)
 
 	if s.tenderCache != nil {
 		if err := s.tenderCache.Set(ctx, createdTender); err != nil {
-			s.logger.Warn("failed to cache tender after creation",
+			s.logger.WarnContext(ctx, "failed to cache tender after creation",
 				"error", err,
 				"tender_id", createdTender.TenderId)
 		}
 	}
 
+	s.audit.Record(ctx, models.AuditTenderCreated, "tender", createdTender.TenderId, nil, createdTender)
+	metrics.TendersCreated.Inc()
+
+	s.search.Index(ctx, createdTender)
+	s.announce(ctx, createdTender)
+
+	createdTender.Localize()
 	return createdTender, nil
 }
 
-func (s *TenderService) GetTender(ctx context.Context, id string) (*models.Tender, error) {
+func (s *TenderService) GetTender(ctx context.Context, id string) (_ *models.Tender, err error) {
+	ctx, span := tracing.Start(ctx, "TenderService.GetTender")
+	defer tracing.End(span, &err)
+
 	if s.tenderCache != nil {
 		tender, err := s.tenderCache.Get(ctx, id)
 		if err == nil {
+			tender.Localize()
 			return tender, nil
 		}
-		s.logger.Debug("cache miss for tender",
+		s.logger.DebugContext(ctx, "cache miss for tender",
 			"tender_id", id,
 			"error", err)
 	}
@@ -59,16 +109,53 @@ func (s *TenderService) GetTender(ctx context.Context, id string) (*models.Tende
 
 	if s.tenderCache != nil {
 		if err := s.tenderCache.Set(ctx, tender); err != nil {
-			s.logger.Warn("failed to update cache after getting tender",
+			s.logger.WarnContext(ctx, "failed to update cache after getting tender",
 				"error", err,
 				"tender_id", id)
 		}
 	}
 
+	tender.Localize()
+	return tender, nil
+}
+
+// GetTenderForUser is GetTender restricted to tenders the user may see.
+func (s *TenderService) GetTenderForUser(ctx context.Context, userID, id string) (_ *models.Tender, err error) {
+	ctx, span := tracing.Start(ctx, "TenderService.GetTenderForUser")
+	defer tracing.End(span, &err)
+
+	tender, err := s.GetTender(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if tender.Status == string(models.DRAFT) && s.orgs.CanViewTender(ctx, userID, tender) != nil {
+		return nil, fmt.Errorf("tender not found: %s", id)
+	}
+	if err := s.invitations.CheckAccess(ctx, tender, userID); err != nil {
+		return nil, err
+	}
 	return tender, nil
 }
 
-func (s *TenderService) UpdateTender(ctx context.Context, tender *models.Tender) (*models.Tender, error) {
+func (s *TenderService) UpdateTender(ctx context.Context, tender *models.Tender) (_ *models.Tender, err error) {
+	ctx, span := tracing.Start(ctx, "TenderService.UpdateTender")
+	defer tracing.End(span, &err)
+
+	before, err := s.tenderRepo.GetTender(ctx, tender.TenderId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tender: %w", err)
+	}
+
+	tender.CategoryPath = nil
+	if tender.CategoryId != "" {
+		path, err := s.categories.Lineage(ctx, tender.CategoryId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve tender category: %w", err)
+		}
+		tender.CategoryPath = path
+	}
+	tender.Tags = models.NormalizeTags(tender.Tags)
+
 	updatedTender, err := s.tenderRepo.UpdateTender(ctx, tender)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update tender: %w", err)
@@ -76,43 +163,256 @@ func (s *TenderService) UpdateTender(ctx context.Context, tender *models.Tender)
 
 	if s.tenderCache != nil {
 		if err := s.tenderCache.Set(ctx, updatedTender); err != nil {
-			s.logger.Warn("failed to update cache after tender update",
+			s.logger.WarnContext(ctx, "failed to update cache after tender update",
 				"error", err,
 				"tender_id", tender.TenderId)
 		}
 	}
 
+	s.audit.Record(ctx, models.AuditTenderUpdated, "tender", tender.TenderId, before, updatedTender)
+
+	s.search.Index(ctx, updatedTender)
+
+	updatedTender.Localize()
 	return updatedTender, nil
 }
 
-func (s *TenderService) DeleteTender(ctx context.Context, id string) error {
-	if err := s.tenderRepo.DeleteTender(ctx, id); err != nil {
+// DeleteTender soft-deletes a tender together with its bids and tells the
+// bidding contractors. An admin can restore it until the retention job
+// purges it.
+func (s *TenderService) DeleteTender(ctx context.Context, userID, id string) (err error) {
+	ctx, span := tracing.Start(ctx, "TenderService.DeleteTender")
+	defer tracing.End(span, &err)
+
+	tender, err := s.tenderRepo.GetTender(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get tender: %w", err)
+	}
+	if err := s.orgs.CanManageTender(ctx, userID, tender); err != nil {
+		return err
+	}
+
+	bids, err := s.bidRepo.ListBidsForTender(ctx, id, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list tender bids: %w", err)
+	}
+
+	// Bids share the tender's deletion time so a restore brings back
+	// exactly these and not ones withdrawn earlier.
+	at := time.Now().UTC().Truncate(time.Millisecond)
+	if err := s.tenderRepo.DeleteTender(ctx, id, at); err != nil {
 		return fmt.Errorf("failed to delete tender: %w", err)
 	}
+	if err := s.bidRepo.DeleteBidsForTender(ctx, id, at); err != nil {
+		return fmt.Errorf("failed to delete tender bids: %w", err)
+	}
 
 	if s.tenderCache != nil {
 		if err := s.tenderCache.Delete(ctx, id); err != nil {
-			s.logger.Warn("failed to delete tender from cache",
+			s.logger.WarnContext(ctx, "failed to delete tender from cache",
 				"error", err,
 				"tender_id", id)
 		}
 	}
 
+	s.audit.Record(ctx, models.AuditTenderDeleted, "tender", id, tender, nil)
+	for _, bid := range bids {
+		s.audit.Record(ctx, models.AuditBidWithdrawn, "bid", bid.BidId, bid, nil)
+	}
+
+	s.search.Remove(ctx, id)
+
+	s.notifyBidders(ctx, tender, bids, models.NotificationTenderDeleted,
+		fmt.Sprintf("Tender %q was withdrawn", tender.Title),
+		fmt.Sprintf("The client withdrew %q and your bid on it.", tender.Title))
+
+	return nil
+}
+
+// RestoreTender undoes DeleteTender on an admin's request, bringing back the
+// bids deleted with the tender.
+func (s *TenderService) RestoreTender(ctx context.Context, id string) (_ *models.Tender, err error) {
+	ctx, span := tracing.Start(ctx, "TenderService.RestoreTender")
+	defer tracing.End(span, &err)
+
+	deleted, err := s.tenderRepo.GetDeletedTender(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted tender: %w", err)
+	}
+
+	if err := s.tenderRepo.RestoreTender(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to restore tender: %w", err)
+	}
+	if _, err := s.bidRepo.RestoreBidsForTender(ctx, id, *deleted.DeletedAt); err != nil {
+		return nil, fmt.Errorf("failed to restore tender bids: %w", err)
+	}
+
+	tender, err := s.tenderRepo.GetTender(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tender: %w", err)
+	}
+	bids, err := s.bidRepo.ListBidsForTender(ctx, id, nil)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to list restored bids",
+			"error", err,
+			"tender_id", id)
+	}
+
+	s.audit.Record(ctx, models.AuditTenderRestored, "tender", id, deleted, tender)
+	for _, bid := range bids {
+		s.audit.Record(ctx, models.AuditBidRestored, "bid", bid.BidId, nil, bid)
+	}
+
+	s.search.Index(ctx, tender)
+
+	s.notifyBidders(ctx, tender, bids, models.NotificationTenderRestored,
+		fmt.Sprintf("Tender %q was restored", tender.Title),
+		fmt.Sprintf("%q and your bid on it have been restored.", tender.Title))
+
+	tender.Localize()
+	return tender, nil
+}
+
+// notifyBidders sends one notification to each contractor with a bid in bids.
+func (s *TenderService) notifyBidders(ctx context.Context, tender *models.Tender, bids []*models.Bid, kind models.NotificationType, title, message string) {
+	notified := make(map[string]bool)
+	var notifications []*models.Notification
+	for _, bid := range bids {
+		if notified[bid.ContractorId] {
+			continue
+		}
+		notified[bid.ContractorId] = true
+		notifications = append(notifications, &models.Notification{
+			UserId:   bid.ContractorId,
+			Type:     kind,
+			Title:    title,
+			Message:  message,
+			TenderId: tender.TenderId,
+		})
+	}
+	if len(notifications) == 0 {
+		return
+	}
+
+	if err := s.notifications.Notify(ctx, notifications...); err != nil {
+		s.logger.WarnContext(ctx, "failed to notify bidders",
+			"error", err,
+			"tender_id", tender.TenderId,
+			"type", kind)
+	}
+}
+
+// UpdateTenderStatus changes the status on the client's request. A multi-lot
+// tender can only be awarded once every lot is awarded or cancelled, which
+// LotService does automatically.
+func (s *TenderService) UpdateTenderStatus(ctx context.Context, userID, id string, status models.Status) (err error) {
+	ctx, span := tracing.Start(ctx, "TenderService.UpdateTenderStatus")
+	defer tracing.End(span, &err)
+
+	tender, err := s.tenderRepo.GetTender(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get tender: %w", err)
+	}
+	if err := s.orgs.CanManageTender(ctx, userID, tender); err != nil {
+		return err
+	}
+
+	if status == models.AWARDED {
+		if resolved, _ := tender.LotsResolved(); !resolved {
+			return ErrLotsUnresolved
+		}
+	}
+
+	publishing := status == models.OPEN && tender.Status == string(models.DRAFT)
+	if publishing && tender.Deadline.Before(time.Now()) {
+		return ErrDeadlinePassed
+	}
+
+	if err := s.setStatus(ctx, id, models.Status(tender.Status), status); err != nil {
+		return err
+	}
+
+	if publishing {
+		tender.Status = string(status)
+		s.announce(ctx, tender)
+	}
 	return nil
 }
 
-func (s *TenderService) UpdateTenderStatus(ctx context.Context, id string, status models.Status) error {
+func (s *TenderService) setStatus(ctx context.Context, id string, from, status models.Status) error {
 	if err := s.tenderRepo.UpdateStatus(ctx, id, status); err != nil {
 		return fmt.Errorf("failed to update tender status: %w", err)
 	}
 
+	s.audit.Record(ctx, models.AuditTenderStatusChanged, "tender", id,
+		map[string]models.Status{"status": from},
+		map[string]models.Status{"status": status})
+	if status == models.AWARDED {
+		metrics.TendersAwarded.Inc()
+	}
+
 	if s.tenderCache != nil {
 		if err := s.tenderCache.Delete(ctx, id); err != nil {
-			s.logger.Warn("failed to invalidate cache after status update",
+			s.logger.WarnContext(ctx, "failed to invalidate cache after status update",
 				"error", err,
 				"tender_id", id)
 		}
 	}
 
+	// Status is a search facet, so the indexed copy has to follow it.
+	tender, err := s.tenderRepo.GetTender(ctx, id)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to reload tender for search index",
+			"error", err,
+			"tender_id", id)
+		return nil
+	}
+	s.search.Index(ctx, tender)
+
 	return nil
 }
+
+// refresh drops the cached copy of a tender changed outside UpdateTender and
+// re-indexes it for search.
+func (s *TenderService) refresh(ctx context.Context, id string) {
+	if s.tenderCache != nil {
+		if err := s.tenderCache.Delete(ctx, id); err != nil {
+			s.logger.WarnContext(ctx, "failed to invalidate tender cache",
+				"error", err,
+				"tender_id", id)
+		}
+	}
+
+	tender, err := s.tenderRepo.GetTender(ctx, id)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to reload tender for search index",
+			"error", err,
+			"tender_id", id)
+		return
+	}
+	s.search.Index(ctx, tender)
+}
+
+// announce matches a newly published tender against saved searches. Matching
+// runs as a background job so publishing is not slowed down by the number of
+// subscribers, and shutdown waits for it. Drafts are announced when
+// published, and invite-only tenders through invitations instead.
+func (s *TenderService) announce(ctx context.Context, tender *models.Tender) {
+	if tender.Status == string(models.DRAFT) || tender.IsInviteOnly() {
+		return
+	}
+
+	matched := *tender
+	s.runJob(func(jobCtx context.Context) {
+		// Keep the request's trace and log attributes, but stop with the job
+		ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		defer cancel()
+		defer context.AfterFunc(jobCtx, cancel)()
+
+		if err := s.savedSearches.MatchTender(ctx, &matched); err != nil {
+			s.logger.ErrorContext(ctx, "failed to match tender against saved searches",
+				"error", err,
+				"tender_id", matched.TenderId)
+		}
+	})
+}
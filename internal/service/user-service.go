@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
@@ -9,28 +10,44 @@ import (
 	"strings"
 	"time"
 
+	"github.com/zohirovs/internal/config"
 	"github.com/zohirovs/internal/models"
 	"github.com/zohirovs/internal/repos"
+	"github.com/zohirovs/internal/storage/redis"
+	"github.com/zohirovs/internal/tracing"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type UserService struct {
 	userRepo repos.UserRepo
+	limiter  *redis.RateLimiter
+	limits   config.RateLimitConfig
+	audit    *AuditService
 	logger   *slog.Logger
 }
 
-func NewUserService(userRepo repos.UserRepo, logger *slog.Logger) *UserService {
+func NewUserService(userRepo repos.UserRepo, limiter *redis.RateLimiter, limits config.RateLimitConfig, audit *AuditService, logger *slog.Logger) *UserService {
 	return &UserService{
 		userRepo: userRepo,
+		limiter:  limiter,
+		limits:   limits,
+		audit:    audit,
 		logger:   logger,
 	}
 }
 
+// dummyHash is compared against when a username does not exist, so unknown
+// and known usernames take as long to reject.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("not a real password"), bcrypt.DefaultCost)
+
 // 1
-func (s *UserService) RegisterUser(ctx context.Context, user *models.RegisterUser) (string, error) {
-	_, err := s.userRepo.GetUserByEmail(ctx, user.Email)
+func (s *UserService) RegisterUser(ctx context.Context, user *models.RegisterUser) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.RegisterUser")
+	defer tracing.End(span, &err)
+
+	_, err = s.userRepo.GetUserByEmail(ctx, user.Email)
 	if err == nil {
-		return "Duplicate", fmt.Errorf("user with this email already exists")
+		return "", ErrUserExists
 	}
 
 	// isValid := s.isEmailExists(user.Email)
@@ -52,14 +69,22 @@ func (s *UserService) RegisterUser(ctx context.Context, user *models.RegisterUse
 
 	token, err := s.userRepo.RegisterUser(ctx, &new_user)
 	if err != nil {
+		if errors.Is(err, repos.ErrDuplicateUser) {
+			return "", ErrUserExists
+		}
 		return "", fmt.Errorf("failed to register user: %w", err)
 	}
 
+	s.audit.Record(ctx, models.AuditUserRegistered, "user", new_user.ID, nil, userSnapshot(&new_user))
+
 	return token, nil
 }
 
 // 2
-func (s *UserService) GetUserByUserID(ctx context.Context, userID string) (*models.User, error) {
+func (s *UserService) GetUserByUserID(ctx context.Context, userID string) (_ *models.User, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetUserByUserID")
+	defer tracing.End(span, &err)
+
 	user, err := s.userRepo.GetUserByUserID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user by userID: %w", err)
@@ -67,7 +92,10 @@ func (s *UserService) GetUserByUserID(ctx context.Context, userID string) (*mode
 	return user, nil
 }
 
-func (s *UserService) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+func (s *UserService) GetUserByUsername(ctx context.Context, username string) (_ *models.User, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetUserByUsername")
+	defer tracing.End(span, &err)
+
 	user, err := s.userRepo.GetUserByUsername(ctx, username)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user by username: %w", err)
@@ -76,7 +104,10 @@ func (s *UserService) GetUserByUsername(ctx context.Context, username string) (*
 }
 
 // 3
-func (s *UserService) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+func (s *UserService) GetUserByEmail(ctx context.Context, email string) (_ *models.User, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetUserByEmail")
+	defer tracing.End(span, &err)
+
 	user, err := s.userRepo.GetUserByEmail(ctx, email)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user by email: %w", err)
@@ -85,30 +116,57 @@ func (s *UserService) GetUserByEmail(ctx context.Context, email string) (*models
 }
 
 // 4
-func (s *UserService) ChangeUserRole(ctx context.Context, userID string, role string) error {
-	err := s.userRepo.ChangeUserRole(ctx, userID, role)
+func (s *UserService) ChangeUserRole(ctx context.Context, userID string, role string) (err error) {
+	ctx, span := tracing.Start(ctx, "UserService.ChangeUserRole")
+	defer tracing.End(span, &err)
+
+	before, err := s.userRepo.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	err = s.userRepo.ChangeUserRole(ctx, userID, role)
 	if err != nil {
 		return fmt.Errorf("failed to change user role: %w", err)
 	}
+
+	s.audit.Record(ctx, models.AuditUserRoleChanged, "user", userID,
+		map[string]string{"role": string(before.Role)},
+		map[string]string{"role": role})
 	return nil
 }
 
 // 5
-func (s *UserService) ChangeUserPassword(ctx context.Context, resetPassword *models.ResetPassword) error {
+func (s *UserService) ChangeUserPassword(ctx context.Context, resetPassword *models.ResetPassword) (err error) {
+	ctx, span := tracing.Start(ctx, "UserService.ChangeUserPassword")
+	defer tracing.End(span, &err)
+
 	if !s.isValidPassword(resetPassword.NewPassword) {
 		return fmt.Errorf("invalid password format")
 	}
 
-	err := s.userRepo.ChangeUserPassword(ctx, resetPassword)
+	// Audit entries name users by ID, like every other user action.
+	user, err := s.userRepo.GetUserByEmail(ctx, resetPassword.Email)
+	if err != nil {
+		return fmt.Errorf("failed to get user by email: %w", err)
+	}
+
+	err = s.userRepo.ChangeUserPassword(ctx, resetPassword)
 	if err != nil {
 		return fmt.Errorf("failed to change user password: %w", err)
 	}
+
+	// Never snapshot the password itself.
+	s.audit.Record(ctx, models.AuditUserPasswordChanged, "user", user.ID, nil, nil)
 	return nil
 }
 
 // 6
-func (s *UserService) SendVerificationCode(ctx context.Context, email string) error {
-	_, err := s.GetUserByEmail(ctx, email)
+func (s *UserService) SendVerificationCode(ctx context.Context, email string) (err error) {
+	ctx, span := tracing.Start(ctx, "UserService.SendVerificationCode")
+	defer tracing.End(span, &err)
+
+	_, err = s.GetUserByEmail(ctx, email)
 	if err != nil {
 		return fmt.Errorf("user does not exists: %w", err)
 	}
@@ -122,16 +180,38 @@ func (s *UserService) SendVerificationCode(ctx context.Context, email string) er
 }
 
 // 7
-func (s *UserService) Login(ctx context.Context, login *models.LoginRequest) (string, error) {
-	resp, err := s.userRepo.GetUserByUsername(ctx, login.Username)
+// Login returns ErrInvalidCredentials for unknown usernames and wrong
+// passwords alike, and a *LoginLockedError while the account is locked out
+// after repeated failures.
+func (s *UserService) Login(ctx context.Context, login *models.LoginRequest) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.Login")
+	defer tracing.End(span, &err)
+
+	account := strings.ToLower(login.Username)
+
+	wait, err := s.limiter.LoginLockedFor(ctx, account)
 	if err != nil {
-		return "not found", fmt.Errorf("failed to get user by username: %w", err)
+		return "", err
+	}
+	if wait > 0 {
+		return "", &LoginLockedError{RetryAfter: wait}
 	}
 
-	_, err = s.checkPassword(resp.Password, login.Password)
-	if err != nil {
-		s.logger.Error("Failed to check password", slog.String("error", err.Error()))
-		return "", fmt.Errorf("failed to check password: %w", err)
+	hash := string(dummyHash)
+	resp, err := s.userRepo.GetCredentials(ctx, login.Username)
+	switch {
+	case err == nil:
+		hash = resp.Password
+	case !strings.Contains(err.Error(), "not found"):
+		return "", fmt.Errorf("failed to get user by username: %w", err)
+	}
+
+	if ok, _ := s.checkPassword(hash, login.Password); !ok || resp == nil {
+		return "", s.loginFailed(ctx, account)
+	}
+
+	if err := s.limiter.ClearLoginFailures(ctx, account); err != nil {
+		s.logger.WarnContext(ctx, "failed to clear login failures", "error", err)
 	}
 
 	token, err := s.userRepo.Login(ctx, login)
@@ -142,6 +222,36 @@ func (s *UserService) Login(ctx context.Context, login *models.LoginRequest) (st
 	return token, nil
 }
 
+// loginFailed counts a failed login and, from the configured threshold on,
+// locks the account for twice as long with every further failure.
+func (s *UserService) loginFailed(ctx context.Context, account string) error {
+	failures, err := s.limiter.RecordLoginFailure(ctx, account)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to record login failure", "error", err)
+		return ErrInvalidCredentials
+	}
+
+	over := failures - int64(s.limits.LockoutThreshold)
+	if s.limits.LockoutThreshold <= 0 || over < 0 {
+		return ErrInvalidCredentials
+	}
+
+	lock := s.limits.LockoutMax
+	if over < 32 {
+		lock = min(s.limits.LockoutBase<<over, s.limits.LockoutMax)
+	}
+	if err := s.limiter.LockLogin(ctx, account, lock); err != nil {
+		s.logger.WarnContext(ctx, "failed to lock login", "error", err)
+		return ErrInvalidCredentials
+	}
+
+	s.logger.WarnContext(ctx, "login locked after repeated failures",
+		"username", account,
+		"failures", failures,
+		"locked_for", lock)
+	return &LoginLockedError{RetryAfter: lock}
+}
+
 func (s *UserService) isEmailExists(email string) bool {
 	// Check email format
 	parts := strings.Split(email, "@")
@@ -191,3 +301,63 @@ func (s *UserService) checkPassword(hashedPassword, password string) (bool, erro
 	}
 	return true, nil
 }
+
+// DeleteUser soft-deletes a user on an admin's request.
+func (s *UserService) DeleteUser(ctx context.Context, userID string) (err error) {
+	ctx, span := tracing.Start(ctx, "UserService.DeleteUser")
+	defer tracing.End(span, &err)
+
+	before, err := s.userRepo.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := s.userRepo.DeleteUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	s.audit.Record(ctx, models.AuditUserDeleted, "user", userID, userSnapshot(before), nil)
+	return nil
+}
+
+// IsActive reports whether the user exists and is not deleted. Lookups go
+// through the user cache, which DeleteUser clears.
+func (s *UserService) IsActive(ctx context.Context, userID string) (_ bool, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.IsActive")
+	defer tracing.End(span, &err)
+
+	user, err := s.userRepo.GetUserByUserID(ctx, userID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "invalid user ID") {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get user: %w", err)
+	}
+	return user.DeletedAt.IsZero(), nil
+}
+
+func (s *UserService) RestoreUser(ctx context.Context, userID string) (err error) {
+	ctx, span := tracing.Start(ctx, "UserService.RestoreUser")
+	defer tracing.End(span, &err)
+
+	if err := s.userRepo.RestoreUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to restore user: %w", err)
+	}
+
+	user, err := s.userRepo.GetUserByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	s.audit.Record(ctx, models.AuditUserRestored, "user", userID, nil, userSnapshot(user))
+	return nil
+}
+
+// userSnapshot is the audited view of a user, without the password.
+func userSnapshot(user *models.User) map[string]string {
+	return map[string]string{
+		"username": user.Username,
+		"email":    user.Email,
+		"role":     string(user.Role),
+	}
+}
@@ -0,0 +1,34 @@
+// Package blob stores attachment files outside MongoDB, either on the local
+// filesystem or in an S3-compatible object store.
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/zohirovs/internal/config"
+)
+
+// ErrNotFound is returned when an object does not exist in the store.
+var ErrNotFound = errors.New("blob not found")
+
+// Store is a minimal object store keyed by slash-separated paths.
+type Store interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// New returns the store selected by the attachment configuration.
+func New(cfg *config.Config) (Store, error) {
+	switch cfg.Attachments.Driver {
+	case "", "local":
+		return NewLocalStore(cfg.Attachments.LocalDir)
+	case "s3":
+		return NewS3Store(cfg.Attachments.S3)
+	default:
+		return nil, fmt.Errorf("unknown attachment storage driver: %s", cfg.Attachments.Driver)
+	}
+}
@@ -0,0 +1,104 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStoreRoundTrip(t *testing.T) {
+	root := t.TempDir()
+	store, err := NewLocalStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	const key = "tenders/t1/a1.pdf"
+	if err := store.Put(ctx, key, strings.NewReader("proposal"), 8, "application/pdf"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "tenders", "t1", "a1.pdf")); err != nil {
+		t.Errorf("object not stored below root: %v", err)
+	}
+
+	body, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	got, _ := io.ReadAll(body)
+	body.Close()
+	if string(got) != "proposal" {
+		t.Errorf("Get = %q", got)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete = %v, want ErrNotFound", err)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Errorf("Delete of a missing object = %v, want nil", err)
+	}
+
+	// No temporary upload files are left behind.
+	entries, _ := os.ReadDir(filepath.Join(root, "tenders", "t1"))
+	if len(entries) != 0 {
+		t.Errorf("leftover files: %v", entries)
+	}
+}
+
+func TestLocalStorePath(t *testing.T) {
+	root := t.TempDir()
+	store, err := NewLocalStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"a.pdf", "tenders/t1/a.pdf", "/tenders/a.pdf", "tenders/../a.pdf"} {
+		path, err := store.path(key)
+		if err != nil {
+			t.Errorf("path(%q) = %v", key, err)
+			continue
+		}
+		if !strings.HasPrefix(path, store.root+string(filepath.Separator)) {
+			t.Errorf("path(%q) = %q, outside %q", key, path, store.root)
+		}
+	}
+
+	for _, key := range []string{"", ".", "..", "../a.pdf", "tenders/../../a.pdf", "../" + filepath.Base(root) + "x/a.pdf"} {
+		if path, err := store.path(key); err == nil {
+			t.Errorf("path(%q) = %q, want an error", key, path)
+		}
+	}
+
+	ctx := context.Background()
+	if err := store.Put(ctx, "../escape", strings.NewReader("x"), 1, "text/plain"); err == nil {
+		t.Error("Put outside root succeeded")
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(root), "escape")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("file written outside root: %v", err)
+	}
+	if _, err := store.Get(ctx, "../../etc/passwd"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Get outside root = %v, want an invalid key error", err)
+	}
+}
+
+func TestNewLocalStoreCreatesRoot(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "data", "uploads")
+	store, err := NewLocalStore(root + string(filepath.Separator) + ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.root != root {
+		t.Errorf("root = %q, want %q", store.root, root)
+	}
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		t.Errorf("root directory not created: %v", err)
+	}
+}
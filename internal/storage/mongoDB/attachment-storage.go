@@ -0,0 +1,152 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AttachmentStorage struct {
+	db     *mongo.Collection
+	logger *slog.Logger
+}
+
+func NewAttachmentStorage(db *mongo.Database, logger *slog.Logger) *AttachmentStorage {
+	return &AttachmentStorage{
+		db:     db.Collection("Attachments"),
+		logger: logger,
+	}
+}
+
+func (s *AttachmentStorage) CreateAttachment(ctx context.Context, attachment *models.Attachment) (*models.Attachment, error) {
+	ctx, span := tracing.Start(ctx, "AttachmentStorage.CreateAttachment")
+	defer span.End()
+
+	if attachment.AttachmentId == "" {
+		attachment.AttachmentId = primitive.NewObjectID().Hex()
+	}
+	attachment.CreatedAt = time.Now().UTC()
+
+	_, err := s.db.InsertOne(ctx, attachment)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to create attachment",
+			"error", err,
+			"attachment_id", attachment.AttachmentId)
+		return nil, fmt.Errorf("failed to create attachment: %w", err)
+	}
+
+	return attachment, nil
+}
+
+func (s *AttachmentStorage) GetAttachment(ctx context.Context, id string) (*models.Attachment, error) {
+	ctx, span := tracing.Start(ctx, "AttachmentStorage.GetAttachment")
+	defer span.End()
+
+	var attachment models.Attachment
+
+	err := s.db.FindOne(ctx, bson.M{"attachment_id": id}).Decode(&attachment)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("attachment not found: %s", id)
+		}
+		s.logger.ErrorContext(ctx, "failed to get attachment",
+			"error", err,
+			"attachment_id", id)
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	return &attachment, nil
+}
+
+// ListTenderAttachments returns the tender's own documents, excluding the
+// technical proposals attached to its bids.
+func (s *AttachmentStorage) ListTenderAttachments(ctx context.Context, tenderId string) ([]*models.Attachment, error) {
+	ctx, span := tracing.Start(ctx, "AttachmentStorage.ListTenderAttachments")
+	defer span.End()
+
+	return s.list(ctx, bson.M{"tender_id": tenderId, "bid_id": bson.M{"$exists": false}})
+}
+
+func (s *AttachmentStorage) ListBidAttachments(ctx context.Context, bidId string) ([]*models.Attachment, error) {
+	ctx, span := tracing.Start(ctx, "AttachmentStorage.ListBidAttachments")
+	defer span.End()
+
+	return s.list(ctx, bson.M{"bid_id": bidId})
+}
+
+func (s *AttachmentStorage) DeleteAttachment(ctx context.Context, id string) error {
+	ctx, span := tracing.Start(ctx, "AttachmentStorage.DeleteAttachment")
+	defer span.End()
+
+	result, err := s.db.DeleteOne(ctx, bson.M{"attachment_id": id})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to delete attachment",
+			"error", err,
+			"attachment_id", id)
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("attachment not found: %s", id)
+	}
+
+	return nil
+}
+
+func (s *AttachmentStorage) ListAttachmentsFor(ctx context.Context, tenderIds []string, bidIds []string) ([]*models.Attachment, error) {
+	ctx, span := tracing.Start(ctx, "AttachmentStorage.ListAttachmentsFor")
+	defer span.End()
+
+	filter := referencing(map[string][]string{"tender_id": tenderIds, "bid_id": bidIds})
+	if filter == nil {
+		return nil, nil
+	}
+	return s.list(ctx, filter)
+}
+
+func (s *AttachmentStorage) PurgeAttachments(ctx context.Context, ids []string) (int64, error) {
+	ctx, span := tracing.Start(ctx, "AttachmentStorage.PurgeAttachments")
+	defer span.End()
+
+	filter := referencing(map[string][]string{"attachment_id": ids})
+	if filter == nil {
+		return 0, nil
+	}
+
+	result, err := s.db.DeleteMany(ctx, filter)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to purge attachments",
+			"error", err)
+		return 0, fmt.Errorf("failed to purge attachments: %w", err)
+	}
+
+	return result.DeletedCount, nil
+}
+
+func (s *AttachmentStorage) list(ctx context.Context, filter bson.M) ([]*models.Attachment, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+
+	cursor, err := s.db.Find(ctx, filter, opts)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list attachments",
+			"error", err)
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var attachments []*models.Attachment
+	if err = cursor.All(ctx, &attachments); err != nil {
+		return nil, fmt.Errorf("failed to decode attachments: %w", err)
+	}
+
+	return attachments, nil
+}
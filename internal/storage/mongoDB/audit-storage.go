@@ -0,0 +1,151 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// appendAttempts bounds retries when concurrent appends race for the same Seq.
+const appendAttempts = 10
+
+const defaultAuditLimit = 100
+
+type AuditStorage struct {
+	db     *mongo.Collection
+	logger *slog.Logger
+}
+
+func NewAuditStorage(db *mongo.Database, logger *slog.Logger) *AuditStorage {
+	return &AuditStorage{
+		db:     db.Collection("AuditLog"),
+		logger: logger,
+	}
+}
+
+// Append links the entry to the current head of the chain. The unique seq
+// index makes concurrent appends conflict instead of forking the chain; the
+// loser re-reads the head and tries again.
+func (s *AuditStorage) Append(ctx context.Context, entry *models.AuditEntry) (*models.AuditEntry, error) {
+	ctx, span := tracing.Start(ctx, "AuditStorage.Append")
+	defer span.End()
+
+	entry.At = entry.At.UTC().Truncate(time.Millisecond)
+
+	for attempt := 0; attempt < appendAttempts; attempt++ {
+		var head models.AuditEntry
+		err := s.db.FindOne(ctx, bson.M{}, options.FindOne().SetSort(bson.D{{Key: "seq", Value: -1}})).Decode(&head)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			s.logger.ErrorContext(ctx, "failed to read audit chain head",
+				"error", err)
+			return nil, fmt.Errorf("failed to read audit chain head: %w", err)
+		}
+
+		entry.Seq = head.Seq + 1
+		entry.PrevHash = head.Hash
+		entry.Hash = entry.ComputeHash()
+
+		_, err = s.db.InsertOne(ctx, entry)
+		if err == nil {
+			return entry, nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			s.logger.ErrorContext(ctx, "failed to append audit entry",
+				"error", err,
+				"action", entry.Action)
+			return nil, fmt.Errorf("failed to append audit entry: %w", err)
+		}
+	}
+
+	return nil, fmt.Errorf("failed to append audit entry: too much contention after %d attempts", appendAttempts)
+}
+
+func (s *AuditStorage) Query(ctx context.Context, query *models.AuditQuery) ([]*models.AuditEntry, error) {
+	ctx, span := tracing.Start(ctx, "AuditStorage.Query")
+	defer span.End()
+
+	filter := bson.M{}
+	if query.ActorId != "" {
+		filter["actor_id"] = query.ActorId
+	}
+	if query.Action != "" {
+		filter["action"] = query.Action
+	}
+	if query.ResourceType != "" {
+		filter["resource_type"] = query.ResourceType
+	}
+	if query.ResourceId != "" {
+		filter["resource_id"] = query.ResourceId
+	}
+	if query.RequestId != "" {
+		filter["request_id"] = query.RequestId
+	}
+	if query.BeforeSeq > 0 {
+		filter["seq"] = bson.M{"$lt": query.BeforeSeq}
+	}
+
+	at := bson.M{}
+	if !query.From.IsZero() {
+		at["$gte"] = query.From.UTC()
+	}
+	if !query.To.IsZero() {
+		at["$lt"] = query.To.UTC()
+	}
+	if len(at) > 0 {
+		filter["at"] = at
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultAuditLimit
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "seq", Value: -1}}).SetLimit(int64(limit))
+
+	cursor, err := s.db.Find(ctx, filter, opts)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to query audit log",
+			"error", err)
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	entries := []*models.AuditEntry{}
+	if err = cursor.All(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode audit entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (s *AuditStorage) Walk(ctx context.Context, fn func(*models.AuditEntry) error) error {
+	ctx, span := tracing.Start(ctx, "AuditStorage.Walk")
+	defer span.End()
+
+	cursor, err := s.db.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "seq", Value: 1}}))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to read audit log",
+			"error", err)
+		return fmt.Errorf("failed to read audit log: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var entry models.AuditEntry
+		if err := cursor.Decode(&entry); err != nil {
+			return fmt.Errorf("failed to decode audit entry: %w", err)
+		}
+		if err := fn(&entry); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
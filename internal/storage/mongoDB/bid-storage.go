@@ -8,25 +8,38 @@ import (
 	"time"
 
 	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/storage/redis"
+	"github.com/zohirovs/internal/tracing"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/sync/singleflight"
 )
 
 type BidStorage struct {
 	db     *mongo.Collection
 	logger *slog.Logger
+
+	// cache holds each tender's unfiltered bid list and summary. It may be
+	// nil, e.g. in migrations. Concurrent misses for the same tender share
+	// one database read through loads.
+	cache *redis.BidCaching
+	loads singleflight.Group
 }
 
-func NewBidStorage(db *mongo.Database, logger *slog.Logger) *BidStorage {
+func NewBidStorage(db *mongo.Database, logger *slog.Logger, cache *redis.BidCaching) *BidStorage {
 	return &BidStorage{
 		db:     db.Collection("Bids"),
 		logger: logger,
+		cache:  cache,
 	}
 }
 
 func (s *BidStorage) CreateBid(ctx context.Context, bid *models.Bid) (*models.Bid, error) {
+	ctx, span := tracing.Start(ctx, "BidStorage.CreateBid")
+	defer span.End()
+
 	if bid.BidId == "" {
 		bid.BidId = primitive.NewObjectID().Hex()
 	}
@@ -36,24 +49,28 @@ func (s *BidStorage) CreateBid(ctx context.Context, bid *models.Bid) (*models.Bi
 
 	_, err := s.db.InsertOne(ctx, bid)
 	if err != nil {
-		s.logger.Error("failed to create bid",
+		s.logger.ErrorContext(ctx, "failed to create bid",
 			"error", err,
 			"bid_id", bid.BidId)
 		return nil, fmt.Errorf("failed to create bid: %w", err)
 	}
+	s.invalidate(ctx, bid.TenderId)
 
 	return bid, nil
 }
 
 func (s *BidStorage) GetBid(ctx context.Context, id string) (*models.Bid, error) {
+	ctx, span := tracing.Start(ctx, "BidStorage.GetBid")
+	defer span.End()
+
 	var bid models.Bid
 
-	err := s.db.FindOne(ctx, bson.M{"bid_id": id}).Decode(&bid)
+	err := s.db.FindOne(ctx, bson.M{"bid_id": id, "deleted_at": notDeleted}).Decode(&bid)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, fmt.Errorf("bid not found: %s", id)
 		}
-		s.logger.Error("failed to get bid",
+		s.logger.ErrorContext(ctx, "failed to get bid",
 			"error", err,
 			"bid_id", id)
 		return nil, fmt.Errorf("failed to get bid: %w", err)
@@ -62,8 +79,96 @@ func (s *BidStorage) GetBid(ctx context.Context, id string) (*models.Bid, error)
 	return &bid, nil
 }
 
+// ListBidsForTender lists a tender's live bids. Unfiltered lists are served
+// from the cache.
 func (s *BidStorage) ListBidsForTender(ctx context.Context, tenderId string, filter map[string]interface{}) ([]*models.Bid, error) {
-	baseFilter := bson.M{"tender_id": tenderId}
+	ctx, span := tracing.Start(ctx, "BidStorage.ListBidsForTender")
+	defer span.End()
+
+	if len(filter) == 0 && s.cache != nil {
+		return s.cachedBids(ctx, tenderId)
+	}
+	return s.findBidsForTender(ctx, tenderId, filter)
+}
+
+// GetBidSummary returns the number of live bids on a tender and the lowest
+// price among them.
+func (s *BidStorage) GetBidSummary(ctx context.Context, tenderId string) (*models.BidSummary, error) {
+	ctx, span := tracing.Start(ctx, "BidStorage.GetBidSummary")
+	defer span.End()
+
+	var gen int64
+	cacheable := false
+	if s.cache != nil {
+		if summary, err := s.cache.GetSummary(ctx, tenderId); err == nil {
+			return summary, nil
+		}
+		gen, cacheable = s.generation(ctx, tenderId)
+	}
+
+	bids, err := s.ListBidsForTender(ctx, tenderId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := models.SummarizeBids(tenderId, bids)
+	if cacheable {
+		if err := s.cache.SetSummary(ctx, gen, summary); err != nil {
+			s.logger.WarnContext(ctx, "failed to cache bid summary",
+				"error", err,
+				"tender_id", tenderId)
+		}
+	}
+
+	return summary, nil
+}
+
+// cachedBids reads a tender's bids through the cache. On a miss only one
+// caller queries MongoDB; the others wait for and share its result.
+func (s *BidStorage) cachedBids(ctx context.Context, tenderId string) ([]*models.Bid, error) {
+	if bids, err := s.cache.GetList(ctx, tenderId); err == nil {
+		return bids, nil
+	}
+
+	v, err, shared := s.loads.Do(tenderId, func() (interface{}, error) {
+		// Waiters must not fail because the first caller went away.
+		loadCtx := context.WithoutCancel(ctx)
+		gen, cacheable := s.generation(loadCtx, tenderId)
+		bids, err := s.findBidsForTender(loadCtx, tenderId, nil)
+		if err != nil {
+			return nil, err
+		}
+		if !cacheable {
+			return bids, nil
+		}
+		if err := s.cache.SetList(loadCtx, tenderId, gen, bids); err != nil {
+			s.logger.WarnContext(ctx, "failed to cache tender bids",
+				"error", err,
+				"tender_id", tenderId)
+		}
+		return bids, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bids := v.([]*models.Bid)
+	if shared {
+		// Callers annotate the bids they get, so each needs its own copies.
+		s.cache.RecordCoalesced()
+		copies := make([]*models.Bid, len(bids))
+		for i, bid := range bids {
+			c := *bid
+			copies[i] = &c
+		}
+		bids = copies
+	}
+
+	return bids, nil
+}
+
+func (s *BidStorage) findBidsForTender(ctx context.Context, tenderId string, filter map[string]interface{}) ([]*models.Bid, error) {
+	baseFilter := bson.M{"tender_id": tenderId, "deleted_at": notDeleted}
 	if filter != nil {
 		for k, v := range filter {
 			baseFilter[k] = v
@@ -71,7 +176,7 @@ func (s *BidStorage) ListBidsForTender(ctx context.Context, tenderId string, fil
 	}
 	cursor, err := s.db.Find(ctx, baseFilter)
 	if err != nil {
-		s.logger.Error("failed to list bids",
+		s.logger.ErrorContext(ctx, "failed to list bids",
 			"error", err,
 			"tender_id", tenderId)
 		return nil, fmt.Errorf("failed to list bids: %w", err)
@@ -87,31 +192,36 @@ func (s *BidStorage) ListBidsForTender(ctx context.Context, tenderId string, fil
 }
 
 func (s *BidStorage) UpdateBidStatus(ctx context.Context, bidId string, status string) error {
+	ctx, span := tracing.Start(ctx, "BidStorage.UpdateBidStatus")
+	defer span.End()
+
 	update := bson.M{
 		"$set": bson.M{
 			"status": status,
 		},
 	}
 
-	result, err := s.db.UpdateOne(ctx, bson.M{"bid_id": bidId}, update)
+	err := s.updateBid(ctx, bson.M{"bid_id": bidId, "deleted_at": notDeleted}, update)
 	if err != nil {
-		s.logger.Error("failed to update bid status",
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return fmt.Errorf("bid not found: %s", bidId)
+		}
+		s.logger.ErrorContext(ctx, "failed to update bid status",
 			"error", err,
 			"bid_id", bidId)
 		return fmt.Errorf("failed to update bid status: %w", err)
 	}
 
-	if result.MatchedCount == 0 {
-		return fmt.Errorf("bid not found: %s", bidId)
-	}
-
 	return nil
 }
 
 func (s *BidStorage) ListBidsByContractor(ctx context.Context, contractorId string) ([]*models.Bid, error) {
-	cursor, err := s.db.Find(ctx, bson.M{"contractor_id": contractorId})
+	ctx, span := tracing.Start(ctx, "BidStorage.ListBidsByContractor")
+	defer span.End()
+
+	cursor, err := s.db.Find(ctx, bson.M{"contractor_id": contractorId, "deleted_at": notDeleted})
 	if err != nil {
-		s.logger.Error("failed to list contractor bids",
+		s.logger.ErrorContext(ctx, "failed to list contractor bids",
 			"error", err,
 			"contractor_id", contractorId)
 		return nil, fmt.Errorf("failed to list contractor bids: %w", err)
@@ -126,42 +236,189 @@ func (s *BidStorage) ListBidsByContractor(ctx context.Context, contractorId stri
 	return bids, nil
 }
 
-func (s *BidStorage) CreateIndexes(ctx context.Context) error {
-	indexes := []mongo.IndexModel{
-		{
-			Keys: bson.D{
-				{Key: "bid_id", Value: 1},
-			},
-			Options: options.Index().SetUnique(true),
-		},
-		{
-			Keys: bson.D{
-				{Key: "tender_id", Value: 1},
-			},
-		},
-		{
-			Keys: bson.D{
-				{Key: "contractor_id", Value: 1},
-			},
-		},
-		{
-			Keys: bson.D{
-				{Key: "price", Value: 1},
-			},
-		},
-		{
-			Keys: bson.D{
-				{Key: "delivery_time", Value: 1},
-			},
-		},
+func (s *BidStorage) ListBidsByOrganization(ctx context.Context, orgId string) ([]*models.Bid, error) {
+	ctx, span := tracing.Start(ctx, "BidStorage.ListBidsByOrganization")
+	defer span.End()
+
+	cursor, err := s.db.Find(ctx, bson.M{"organization_id": orgId, "deleted_at": notDeleted}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list organization bids",
+			"error", err,
+			"organization_id", orgId)
+		return nil, fmt.Errorf("failed to list organization bids: %w", err)
 	}
+	defer cursor.Close(ctx)
 
-	_, err := s.db.Indexes().CreateMany(ctx, indexes)
+	bids := []*models.Bid{}
+	if err = cursor.All(ctx, &bids); err != nil {
+		return nil, fmt.Errorf("failed to decode bids: %w", err)
+	}
+
+	return bids, nil
+}
+
+// DeleteBid soft-deletes a single bid.
+func (s *BidStorage) DeleteBid(ctx context.Context, bidId string, at time.Time) error {
+	ctx, span := tracing.Start(ctx, "BidStorage.DeleteBid")
+	defer span.End()
+
+	update := bson.M{"$set": bson.M{"deleted_at": at}}
+
+	err := s.updateBid(ctx, bson.M{"bid_id": bidId, "deleted_at": notDeleted}, update)
 	if err != nil {
-		s.logger.Error("failed to create bid indexes",
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return fmt.Errorf("bid not found: %s", bidId)
+		}
+		s.logger.ErrorContext(ctx, "failed to delete bid",
+			"error", err,
+			"bid_id", bidId)
+		return fmt.Errorf("failed to delete bid: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteBidsForTender soft-deletes the live bids of a tender, stamping them
+// with the tender's own deletion time.
+func (s *BidStorage) DeleteBidsForTender(ctx context.Context, tenderId string, at time.Time) error {
+	ctx, span := tracing.Start(ctx, "BidStorage.DeleteBidsForTender")
+	defer span.End()
+
+	update := bson.M{"$set": bson.M{"deleted_at": at}}
+
+	_, err := s.db.UpdateMany(ctx, bson.M{"tender_id": tenderId, "deleted_at": notDeleted}, update)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to delete tender bids",
+			"error", err,
+			"tender_id", tenderId)
+		return fmt.Errorf("failed to delete tender bids: %w", err)
+	}
+	s.invalidate(ctx, tenderId)
+
+	return nil
+}
+
+func (s *BidStorage) GetDeletedBid(ctx context.Context, id string) (*models.Bid, error) {
+	ctx, span := tracing.Start(ctx, "BidStorage.GetDeletedBid")
+	defer span.End()
+
+	var bid models.Bid
+
+	err := s.db.FindOne(ctx, bson.M{"bid_id": id, "deleted_at": isDeleted}).Decode(&bid)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("deleted bid not found: %s", id)
+		}
+		s.logger.ErrorContext(ctx, "failed to get deleted bid",
+			"error", err,
+			"bid_id", id)
+		return nil, fmt.Errorf("failed to get deleted bid: %w", err)
+	}
+
+	return &bid, nil
+}
+
+func (s *BidStorage) RestoreBid(ctx context.Context, bidId string) error {
+	ctx, span := tracing.Start(ctx, "BidStorage.RestoreBid")
+	defer span.End()
+
+	update := bson.M{"$unset": bson.M{"deleted_at": ""}}
+
+	err := s.updateBid(ctx, bson.M{"bid_id": bidId, "deleted_at": isDeleted}, update)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return fmt.Errorf("deleted bid not found: %s", bidId)
+		}
+		s.logger.ErrorContext(ctx, "failed to restore bid",
+			"error", err,
+			"bid_id", bidId)
+		return fmt.Errorf("failed to restore bid: %w", err)
+	}
+
+	return nil
+}
+
+// RestoreBidsForTender restores the bids deleted together with a tender,
+// which carry the tender's deletion time. Bids withdrawn earlier stay
+// deleted.
+func (s *BidStorage) RestoreBidsForTender(ctx context.Context, tenderId string, deletedAt time.Time) (int64, error) {
+	ctx, span := tracing.Start(ctx, "BidStorage.RestoreBidsForTender")
+	defer span.End()
+
+	update := bson.M{"$unset": bson.M{"deleted_at": ""}}
+
+	result, err := s.db.UpdateMany(ctx, bson.M{"tender_id": tenderId, "deleted_at": deletedAt}, update)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to restore tender bids",
+			"error", err,
+			"tender_id", tenderId)
+		return 0, fmt.Errorf("failed to restore tender bids: %w", err)
+	}
+	s.invalidate(ctx, tenderId)
+
+	return result.ModifiedCount, nil
+}
+
+func (s *BidStorage) ListPurgeableBids(ctx context.Context, cutoff time.Time) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "BidStorage.ListPurgeableBids")
+	defer span.End()
+
+	return purgeableIDs(ctx, s.db, "bid_id", "deleted_at", cutoff)
+}
+
+// PurgeDeletedBids permanently removes bids soft-deleted at or before cutoff
+// and returns how many were removed.
+func (s *BidStorage) PurgeDeletedBids(ctx context.Context, cutoff time.Time) (int64, error) {
+	ctx, span := tracing.Start(ctx, "BidStorage.PurgeDeletedBids")
+	defer span.End()
+
+	result, err := s.db.DeleteMany(ctx, bson.M{"deleted_at": purgeable(cutoff)})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to purge deleted bids",
 			"error", err)
-		return fmt.Errorf("failed to create bid indexes: %w", err)
+		return 0, fmt.Errorf("failed to purge deleted bids: %w", err)
 	}
 
+	return result.DeletedCount, nil
+}
+
+// updateBid applies update to the bid matching filter and invalidates its
+// tender's cached bids. It returns mongo.ErrNoDocuments if nothing matched.
+func (s *BidStorage) updateBid(ctx context.Context, filter bson.M, update bson.M) error {
+	opts := options.FindOneAndUpdate().SetProjection(bson.M{"tender_id": 1})
+
+	var bid models.Bid
+	if err := s.db.FindOneAndUpdate(ctx, filter, update, opts).Decode(&bid); err != nil {
+		return err
+	}
+	s.invalidate(ctx, bid.TenderId)
+
 	return nil
 }
+
+// generation returns the cache generation of a tender's bids, read before
+// loading them. Without it a load may not be cached, as it could overwrite a
+// later invalidation.
+func (s *BidStorage) generation(ctx context.Context, tenderId string) (int64, bool) {
+	gen, err := s.cache.Generation(ctx, tenderId)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to get bid cache generation",
+			"error", err,
+			"tender_id", tenderId)
+		return 0, false
+	}
+	return gen, true
+}
+
+// invalidate drops a tender's cached bids after they changed. A failure
+// only leaves the cache stale until its TTL runs out, so it is logged.
+func (s *BidStorage) invalidate(ctx context.Context, tenderId string) {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.Invalidate(ctx, tenderId); err != nil {
+		s.logger.WarnContext(ctx, "failed to invalidate cached bids",
+			"error", err,
+			"tender_id", tenderId)
+	}
+}
@@ -0,0 +1,126 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CategoryStorage struct {
+	db     *mongo.Collection
+	logger *slog.Logger
+}
+
+func NewCategoryStorage(db *mongo.Database, logger *slog.Logger) *CategoryStorage {
+	return &CategoryStorage{
+		db:     db.Collection("Categories"),
+		logger: logger,
+	}
+}
+
+func (s *CategoryStorage) CreateCategory(ctx context.Context, category *models.Category) (*models.Category, error) {
+	ctx, span := tracing.Start(ctx, "CategoryStorage.CreateCategory")
+	defer span.End()
+
+	if category.CategoryId == "" {
+		category.CategoryId = primitive.NewObjectID().Hex()
+	}
+	category.CreatedAt = time.Now().UTC()
+
+	_, err := s.db.InsertOne(ctx, category)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, fmt.Errorf("category slug already in use: %s", category.Slug)
+		}
+		s.logger.ErrorContext(ctx, "failed to create category",
+			"error", err,
+			"category_id", category.CategoryId)
+		return nil, fmt.Errorf("failed to create category: %w", err)
+	}
+
+	return category, nil
+}
+
+func (s *CategoryStorage) GetCategory(ctx context.Context, id string) (*models.Category, error) {
+	ctx, span := tracing.Start(ctx, "CategoryStorage.GetCategory")
+	defer span.End()
+
+	var category models.Category
+
+	err := s.db.FindOne(ctx, bson.M{"category_id": id}).Decode(&category)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("category not found: %s", id)
+		}
+		s.logger.ErrorContext(ctx, "failed to get category",
+			"error", err,
+			"category_id", id)
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+
+	return &category, nil
+}
+
+func (s *CategoryStorage) ListCategories(ctx context.Context) ([]*models.Category, error) {
+	ctx, span := tracing.Start(ctx, "CategoryStorage.ListCategories")
+	defer span.End()
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+
+	cursor, err := s.db.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list categories",
+			"error", err)
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var categories []*models.Category
+	if err = cursor.All(ctx, &categories); err != nil {
+		return nil, fmt.Errorf("failed to decode categories: %w", err)
+	}
+
+	return categories, nil
+}
+
+func (s *CategoryStorage) DeleteCategory(ctx context.Context, id string) error {
+	ctx, span := tracing.Start(ctx, "CategoryStorage.DeleteCategory")
+	defer span.End()
+
+	result, err := s.db.DeleteOne(ctx, bson.M{"category_id": id})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to delete category",
+			"error", err,
+			"category_id", id)
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("category not found: %s", id)
+	}
+
+	return nil
+}
+
+func (s *CategoryStorage) HasChildren(ctx context.Context, id string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "CategoryStorage.HasChildren")
+	defer span.End()
+
+	count, err := s.db.CountDocuments(ctx, bson.M{"parent_id": id}, options.Count().SetLimit(1))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to count child categories",
+			"error", err,
+			"category_id", id)
+		return false, fmt.Errorf("failed to count child categories: %w", err)
+	}
+	return count > 0, nil
+}
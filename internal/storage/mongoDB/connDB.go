@@ -2,25 +2,42 @@ package mongodb
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/zohirovs/internal/config"
+	"github.com/zohirovs/internal/metrics"
+	"github.com/zohirovs/internal/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func ConnectDB(config *config.Config) (*mongo.Database, error) {
-	uri := fmt.Sprintf("mongodb://%s:%s@%s:%s",
-		config.MongoDb.User,
-		config.MongoDb.Password,
-		config.MongoDb.Host,
-		config.MongoDb.Port)
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	clientOptions := options.Client().ApplyURI(uri)
+	clientOptions := options.Client().ApplyURI(config.MongoDb.ConnectionURI()).SetMonitor(commandMonitor())
+	if config.MongoDb.User != "" {
+		clientOptions.SetAuth(options.Credential{
+			Username:      config.MongoDb.User,
+			Password:      config.MongoDb.Password,
+			AuthSource:    config.MongoDb.AuthSource,
+			AuthMechanism: config.MongoDb.AuthMechanism,
+		})
+	}
+	if config.MongoDb.TLS.Enabled {
+		tlsConfig, err := config.MongoDb.TLS.Config()
+		if err != nil {
+			return nil, err
+		}
+		clientOptions.SetTLSConfig(tlsConfig)
+	}
 
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
@@ -38,3 +55,66 @@ func ConnectDB(config *config.Config) (*mongo.Database, error) {
 
 	return db, nil
 }
+
+// commandMonitor times and traces every MongoDB command per collection. The
+// collection is only in the started event, so it is kept with the command's
+// span by request ID until the command finishes.
+func commandMonitor() *event.CommandMonitor {
+	type command struct {
+		collection string
+		span       trace.Span
+	}
+	var commands sync.Map
+
+	finished := func(e event.CommandFinishedEvent, outcome string, err error) {
+		name := "none"
+		if v, ok := commands.LoadAndDelete(e.RequestID); ok {
+			cmd := v.(command)
+			name = cmd.collection
+			if err != nil {
+				tracing.Fail(cmd.span, err)
+			}
+			cmd.span.End()
+		}
+		metrics.MongoDuration.
+			WithLabelValues(name, e.CommandName, outcome).
+			Observe(e.Duration.Seconds())
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			collection := commandCollection(e.Command)
+			_, span := tracing.Start(ctx, "mongodb."+e.CommandName,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					attribute.String("db.system", "mongodb"),
+					attribute.String("db.namespace", e.DatabaseName),
+					attribute.String("db.collection.name", collection),
+					attribute.String("db.operation.name", e.CommandName),
+				),
+			)
+			commands.Store(e.RequestID, command{collection: collection, span: span})
+		},
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			finished(e.CommandFinishedEvent, "ok", nil)
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			finished(e.CommandFinishedEvent, "error", errors.New(e.Failure))
+		},
+	}
+}
+
+// commandCollection returns the collection a command targets: the value of
+// its first element for find, insert, aggregate and the like, or the
+// collection field of getMore. Database commands get "none".
+func commandCollection(cmd bson.Raw) string {
+	if first, err := cmd.IndexErr(0); err == nil {
+		if name, ok := first.Value().StringValueOK(); ok {
+			return name
+		}
+	}
+	if name, ok := cmd.Lookup("collection").StringValueOK(); ok {
+		return name
+	}
+	return "none"
+}
@@ -0,0 +1,154 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ContractorStorage struct {
+	db     *mongo.Collection
+	logger *slog.Logger
+}
+
+func NewContractorStorage(db *mongo.Database, logger *slog.Logger) *ContractorStorage {
+	return &ContractorStorage{
+		db:     db.Collection("ContractorProfiles"),
+		logger: logger,
+	}
+}
+
+// UpsertProfile creates or replaces the contractor's editable profile fields.
+// Any change sends the profile back to pending verification.
+func (s *ContractorStorage) UpsertProfile(ctx context.Context, profile *models.ContractorProfile) (*models.ContractorProfile, error) {
+	ctx, span := tracing.Start(ctx, "ContractorStorage.UpsertProfile")
+	defer span.End()
+
+	now := time.Now().UTC()
+
+	update := bson.M{
+		"$set": bson.M{
+			"company_name":        profile.CompanyName,
+			"registration_number": profile.RegistrationNumber,
+			"categories":          profile.Categories,
+			"regions":             profile.Regions,
+			"certifications":      profile.Certifications,
+			"verification_status": models.VerificationPending,
+			"updated_at":          now,
+		},
+		"$unset": bson.M{
+			"verification_note": "",
+			"verified_by":       "",
+			"verified_at":       "",
+		},
+		"$setOnInsert": bson.M{
+			"user_id":    profile.UserId,
+			"created_at": now,
+		},
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var result models.ContractorProfile
+	err := s.db.FindOneAndUpdate(ctx, bson.M{"user_id": profile.UserId}, update, opts).Decode(&result)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, fmt.Errorf("registration number already in use: %s", profile.RegistrationNumber)
+		}
+		s.logger.ErrorContext(ctx, "failed to upsert contractor profile",
+			"error", err,
+			"user_id", profile.UserId)
+		return nil, fmt.Errorf("failed to save contractor profile: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (s *ContractorStorage) GetProfile(ctx context.Context, userId string) (*models.ContractorProfile, error) {
+	ctx, span := tracing.Start(ctx, "ContractorStorage.GetProfile")
+	defer span.End()
+
+	var profile models.ContractorProfile
+
+	err := s.db.FindOne(ctx, bson.M{"user_id": userId}).Decode(&profile)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("contractor profile not found: %s", userId)
+		}
+		s.logger.ErrorContext(ctx, "failed to get contractor profile",
+			"error", err,
+			"user_id", userId)
+		return nil, fmt.Errorf("failed to get contractor profile: %w", err)
+	}
+
+	return &profile, nil
+}
+
+// ListProfiles lists profiles, optionally only those with the given status.
+func (s *ContractorStorage) ListProfiles(ctx context.Context, status models.VerificationStatus) ([]*models.ContractorProfile, error) {
+	ctx, span := tracing.Start(ctx, "ContractorStorage.ListProfiles")
+	defer span.End()
+
+	filter := bson.M{}
+	if status != "" {
+		filter["verification_status"] = status
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "updated_at", Value: 1}})
+
+	cursor, err := s.db.Find(ctx, filter, opts)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list contractor profiles",
+			"error", err)
+		return nil, fmt.Errorf("failed to list contractor profiles: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var profiles []*models.ContractorProfile
+	if err = cursor.All(ctx, &profiles); err != nil {
+		return nil, fmt.Errorf("failed to decode contractor profiles: %w", err)
+	}
+
+	return profiles, nil
+}
+
+func (s *ContractorStorage) UpdateVerification(ctx context.Context, userId string, status models.VerificationStatus, note string, verifiedBy string) (*models.ContractorProfile, error) {
+	ctx, span := tracing.Start(ctx, "ContractorStorage.UpdateVerification")
+	defer span.End()
+
+	now := time.Now().UTC()
+
+	update := bson.M{
+		"$set": bson.M{
+			"verification_status": status,
+			"verification_note":   note,
+			"verified_by":         verifiedBy,
+			"verified_at":         now,
+			"updated_at":          now,
+		},
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var result models.ContractorProfile
+	err := s.db.FindOneAndUpdate(ctx, bson.M{"user_id": userId}, update, opts).Decode(&result)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("contractor profile not found: %s", userId)
+		}
+		s.logger.ErrorContext(ctx, "failed to update contractor verification",
+			"error", err,
+			"user_id", userId)
+		return nil, fmt.Errorf("failed to update contractor verification: %w", err)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,188 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type InvitationStorage struct {
+	db     *mongo.Collection
+	logger *slog.Logger
+}
+
+func NewInvitationStorage(db *mongo.Database, logger *slog.Logger) *InvitationStorage {
+	return &InvitationStorage{
+		db:     db.Collection("Invitations"),
+		logger: logger,
+	}
+}
+
+func (s *InvitationStorage) CreateInvitation(ctx context.Context, invitation *models.Invitation) (*models.Invitation, error) {
+	ctx, span := tracing.Start(ctx, "InvitationStorage.CreateInvitation")
+	defer span.End()
+
+	filter := bson.M{"tender_id": invitation.TenderId}
+	if invitation.ContractorId != "" {
+		filter["contractor_id"] = invitation.ContractorId
+	} else {
+		filter["email"] = invitation.Email
+	}
+
+	update := bson.M{"$setOnInsert": bson.M{
+		"invitation_id": primitive.NewObjectID().Hex(),
+		"tender_id":     invitation.TenderId,
+		"contractor_id": invitation.ContractorId,
+		"email":         invitation.Email,
+		"status":        models.InvitationPending,
+		"created_at":    time.Now().UTC(),
+	}}
+	if invitation.TokenHash != "" {
+		// Re-inviting mails a new token, so only the latest one works; once an
+		// email invitation is claimed, inviting the email again starts afresh.
+		filter["contractor_id"] = ""
+		update["$set"] = bson.M{"token_hash": invitation.TokenHash}
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var result models.Invitation
+	if err := s.db.FindOneAndUpdate(ctx, filter, update, opts).Decode(&result); err != nil {
+		s.logger.ErrorContext(ctx, "failed to create invitation",
+			"error", err,
+			"tender_id", invitation.TenderId)
+		return nil, fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (s *InvitationStorage) GetInvitation(ctx context.Context, id string) (*models.Invitation, error) {
+	ctx, span := tracing.Start(ctx, "InvitationStorage.GetInvitation")
+	defer span.End()
+
+	var invitation models.Invitation
+	err := s.db.FindOne(ctx, bson.M{"invitation_id": id}).Decode(&invitation)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("invitation not found: %s", id)
+		}
+		s.logger.ErrorContext(ctx, "failed to get invitation",
+			"error", err,
+			"invitation_id", id)
+		return nil, fmt.Errorf("failed to get invitation: %w", err)
+	}
+
+	return &invitation, nil
+}
+
+func (s *InvitationStorage) FindInvitation(ctx context.Context, tenderId string, contractorId string) (*models.Invitation, error) {
+	ctx, span := tracing.Start(ctx, "InvitationStorage.FindInvitation")
+	defer span.End()
+
+	var invitation models.Invitation
+	err := s.db.FindOne(ctx, bson.M{"tender_id": tenderId, "contractor_id": contractorId}).Decode(&invitation)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		s.logger.ErrorContext(ctx, "failed to find invitation",
+			"error", err,
+			"tender_id", tenderId)
+		return nil, fmt.Errorf("failed to find invitation: %w", err)
+	}
+
+	return &invitation, nil
+}
+
+func (s *InvitationStorage) ListTenderInvitations(ctx context.Context, tenderId string) ([]*models.Invitation, error) {
+	ctx, span := tracing.Start(ctx, "InvitationStorage.ListTenderInvitations")
+	defer span.End()
+
+	return s.find(ctx, bson.M{"tender_id": tenderId})
+}
+
+func (s *InvitationStorage) ListContractorInvitations(ctx context.Context, contractorId string) ([]*models.Invitation, error) {
+	ctx, span := tracing.Start(ctx, "InvitationStorage.ListContractorInvitations")
+	defer span.End()
+
+	return s.find(ctx, bson.M{"contractor_id": contractorId})
+}
+
+func (s *InvitationStorage) RespondInvitation(ctx context.Context, id string, contractorId string, status models.InvitationStatus) (*models.Invitation, error) {
+	ctx, span := tracing.Start(ctx, "InvitationStorage.RespondInvitation")
+	defer span.End()
+
+	update := bson.M{
+		"$set": bson.M{
+			"contractor_id": contractorId,
+			"status":        status,
+			"responded_at":  time.Now().UTC(),
+		},
+		"$unset": bson.M{"token_hash": ""},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	// An email invitation can only be claimed once.
+	filter := bson.M{
+		"invitation_id": id,
+		"contractor_id": bson.M{"$in": bson.A{contractorId, ""}},
+	}
+
+	var invitation models.Invitation
+	err := s.db.FindOneAndUpdate(ctx, filter, update, opts).Decode(&invitation)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("invitation not found: %s", id)
+		}
+		s.logger.ErrorContext(ctx, "failed to respond to invitation",
+			"error", err,
+			"invitation_id", id)
+		return nil, fmt.Errorf("failed to respond to invitation: %w", err)
+	}
+
+	return &invitation, nil
+}
+
+func (s *InvitationStorage) PurgeInvitations(ctx context.Context, tenderIds []string, contractorIds []string) (int64, error) {
+	ctx, span := tracing.Start(ctx, "InvitationStorage.PurgeInvitations")
+	defer span.End()
+
+	filter := referencing(map[string][]string{"tender_id": tenderIds, "contractor_id": contractorIds})
+	if filter == nil {
+		return 0, nil
+	}
+
+	result, err := s.db.DeleteMany(ctx, filter)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to purge invitations",
+			"error", err)
+		return 0, fmt.Errorf("failed to purge invitations: %w", err)
+	}
+
+	return result.DeletedCount, nil
+}
+
+func (s *InvitationStorage) find(ctx context.Context, filter bson.M) ([]*models.Invitation, error) {
+	cursor, err := s.db.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list invitations",
+			"error", err)
+		return nil, fmt.Errorf("failed to list invitations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	invitations := []*models.Invitation{}
+	if err = cursor.All(ctx, &invitations); err != nil {
+		return nil, fmt.Errorf("failed to decode invitations: %w", err)
+	}
+
+	return invitations, nil
+}
@@ -0,0 +1,103 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zohirovs/internal/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LockStorage keeps one document per lock, keyed by the lock's name.
+type LockStorage struct {
+	db     *mongo.Collection
+	logger *slog.Logger
+}
+
+type lockDocument struct {
+	Name     string    `bson:"_id"`
+	Owner    string    `bson:"owner"`
+	LockedAt time.Time `bson:"locked_at"`
+}
+
+func NewLockStorage(db *mongo.Database, logger *slog.Logger) *LockStorage {
+	return &LockStorage{
+		db:     db.Collection("Locks"),
+		logger: logger,
+	}
+}
+
+func (s *LockStorage) AcquireLock(ctx context.Context, name string, owner string, staleAfter time.Duration) (bool, error) {
+	ctx, span := tracing.Start(ctx, "LockStorage.AcquireLock")
+	defer span.End()
+
+	now := time.Now().UTC()
+	_, err := s.db.InsertOne(ctx, lockDocument{Name: name, Owner: owner, LockedAt: now})
+	if err == nil {
+		return true, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		s.logger.ErrorContext(ctx, "failed to acquire lock",
+			"error", err,
+			"lock", name)
+		return false, fmt.Errorf("failed to acquire lock %s: %w", name, err)
+	}
+
+	// Take over the lock only if its holder has gone quiet for too long.
+	var previous lockDocument
+	err = s.db.FindOneAndUpdate(ctx,
+		bson.M{"_id": name, "locked_at": bson.M{"$lt": now.Add(-staleAfter)}},
+		bson.M{"$set": bson.M{"owner": owner, "locked_at": now}},
+		options.FindOneAndUpdate().SetReturnDocument(options.Before),
+	).Decode(&previous)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return false, nil
+		}
+		s.logger.ErrorContext(ctx, "failed to acquire lock",
+			"error", err,
+			"lock", name)
+		return false, fmt.Errorf("failed to acquire lock %s: %w", name, err)
+	}
+
+	s.logger.WarnContext(ctx, "took over stale lock",
+		"lock", name,
+		"previous_owner", previous.Owner,
+		"locked_at", previous.LockedAt)
+	return true, nil
+}
+
+func (s *LockStorage) RefreshLock(ctx context.Context, name string, owner string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "LockStorage.RefreshLock")
+	defer span.End()
+
+	result, err := s.db.UpdateOne(ctx,
+		bson.M{"_id": name, "owner": owner},
+		bson.M{"$set": bson.M{"locked_at": time.Now().UTC()}},
+	)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to refresh lock",
+			"error", err,
+			"lock", name)
+		return false, fmt.Errorf("failed to refresh lock %s: %w", name, err)
+	}
+	return result.MatchedCount == 1, nil
+}
+
+func (s *LockStorage) ReleaseLock(ctx context.Context, name string, owner string) error {
+	ctx, span := tracing.Start(ctx, "LockStorage.ReleaseLock")
+	defer span.End()
+
+	if _, err := s.db.DeleteOne(ctx, bson.M{"_id": name, "owner": owner}); err != nil {
+		s.logger.ErrorContext(ctx, "failed to release lock",
+			"error", err,
+			"lock", name)
+		return fmt.Errorf("failed to release lock %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,328 @@
+// Package migrations implements versioned schema migrations for the MongoDB
+// collections. Applied versions are recorded in the schema_migrations
+// collection and a lock document prevents concurrent runs.
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	migrationsCollection = "schema_migrations"
+	lockCollection       = "schema_migrations_lock"
+	lockID               = "migrate"
+
+	// staleLockAfter is how long a lock may go unrefreshed before another
+	// runner is allowed to take it over, e.g. after a crashed migration. A
+	// running migrator refreshes its lock every third of that.
+	staleLockAfter = 15 * time.Minute
+)
+
+var (
+	// ErrLocked is returned when another runner holds the migration lock.
+	ErrLocked = errors.New("migrations are locked by another process")
+
+	// ErrLockLost is returned when another runner took the lock over while
+	// migrations were running; nothing further is recorded.
+	ErrLockLost = errors.New("migration lock was taken over by another process")
+)
+
+// Migration is a single versioned schema change.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error
+	Down        func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error
+}
+
+// Status describes whether a migration has been applied.
+type Status struct {
+	Version     int
+	Description string
+	AppliedAt   *time.Time
+}
+
+type appliedMigration struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"applied_at"`
+}
+
+type migrationLock struct {
+	ID       string    `bson:"_id"`
+	Owner    string    `bson:"owner"`
+	LockedAt time.Time `bson:"locked_at"`
+}
+
+type Migrator struct {
+	db         *mongo.Database
+	applied    *mongo.Collection
+	lock       *mongo.Collection
+	logger     *slog.Logger
+	migrations []Migration
+	owner      string
+}
+
+// NewMigrator returns a migrator for the registered migrations.
+func NewMigrator(db *mongo.Database, logger *slog.Logger) *Migrator {
+	migrations := append([]Migration(nil), registry...)
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	hostname, _ := os.Hostname()
+
+	return &Migrator{
+		db:         db,
+		applied:    db.Collection(migrationsCollection),
+		lock:       db.Collection(lockCollection),
+		logger:     logger,
+		migrations: migrations,
+		owner:      fmt.Sprintf("%s/%d/%s", hostname, os.Getpid(), uuid.NewString()),
+	}
+}
+
+// Up applies up to steps pending migrations in version order. A steps value
+// of zero or less applies all of them. It returns the applied versions.
+func (m *Migrator) Up(ctx context.Context, steps int) ([]int, error) {
+	var done []int
+
+	err := m.withLock(ctx, func(ctx context.Context) error {
+		applied, err := m.appliedVersions(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if steps > 0 && len(done) == steps {
+				break
+			}
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			m.logger.InfoContext(ctx, "applying migration",
+				"version", migration.Version,
+				"description", migration.Description)
+
+			if err := migration.Up(ctx, m.db, m.logger); err != nil {
+				return fmt.Errorf("migration %d (%s) failed: %w", migration.Version, migration.Description, err)
+			}
+			if err := m.checkLock(ctx); err != nil {
+				return fmt.Errorf("migration %d (%s) not recorded: %w", migration.Version, migration.Description, err)
+			}
+
+			record := appliedMigration{
+				Version:     migration.Version,
+				Description: migration.Description,
+				AppliedAt:   time.Now().UTC(),
+			}
+			if _, err := m.applied.InsertOne(ctx, record); err != nil {
+				return fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
+			}
+
+			done = append(done, migration.Version)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Down reverts the last steps applied migrations, newest first. A steps value
+// of zero or less is treated as one. It returns the reverted versions.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]int, error) {
+	if steps <= 0 {
+		steps = 1
+	}
+
+	var done []int
+
+	err := m.withLock(ctx, func(ctx context.Context) error {
+		applied, err := m.appliedVersions(ctx)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+
+			m.logger.InfoContext(ctx, "reverting migration",
+				"version", migration.Version,
+				"description", migration.Description)
+
+			if migration.Down != nil {
+				if err := migration.Down(ctx, m.db, m.logger); err != nil {
+					return fmt.Errorf("reverting migration %d (%s) failed: %w", migration.Version, migration.Description, err)
+				}
+			}
+			if err := m.checkLock(ctx); err != nil {
+				return fmt.Errorf("reverted migration %d (%s) not unrecorded: %w", migration.Version, migration.Description, err)
+			}
+
+			if _, err := m.applied.DeleteOne(ctx, bson.M{"_id": migration.Version}); err != nil {
+				return fmt.Errorf("failed to unrecord migration %d: %w", migration.Version, err)
+			}
+
+			done = append(done, migration.Version)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Status lists every registered migration with its applied time, if any.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{
+			Version:     migration.Version,
+			Description: migration.Description,
+		}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+func (m *Migrator) appliedVersions(ctx context.Context) (map[int]appliedMigration, error) {
+	cursor, err := m.applied.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var records []appliedMigration
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("failed to decode applied migrations: %w", err)
+	}
+
+	applied := make(map[int]appliedMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// withLock runs fn while holding the migration lock. The lock is refreshed
+// in the background, and fn's context is cancelled if it is lost.
+func (m *Migrator) withLock(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := m.acquireLock(ctx); err != nil {
+		return err
+	}
+
+	defer func() {
+		// Release with a fresh context so a cancelled run still unlocks.
+		releaseCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if _, err := m.lock.DeleteOne(releaseCtx, bson.M{"_id": lockID, "owner": m.owner}); err != nil {
+			m.logger.ErrorContext(ctx, "failed to release migration lock", "error", err)
+		}
+	}()
+
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+	go m.holdLock(ctx, stop)
+
+	return fn(ctx)
+}
+
+// holdLock refreshes the lock until ctx ends, and calls stop if the lock is
+// lost so migrations do not carry on alongside another runner.
+func (m *Migrator) holdLock(ctx context.Context, stop context.CancelFunc) {
+	ticker := time.NewTicker(staleLockAfter / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			result, err := m.lock.UpdateOne(ctx,
+				bson.M{"_id": lockID, "owner": m.owner},
+				bson.M{"$set": bson.M{"locked_at": time.Now().UTC()}},
+			)
+			if err != nil {
+				// Try again on the next tick; the lock is not stale yet.
+				m.logger.WarnContext(ctx, "failed to refresh migration lock", "error", err)
+				continue
+			}
+			if result.MatchedCount == 0 {
+				m.logger.ErrorContext(ctx, "lost the migration lock, stopping")
+				stop()
+				return
+			}
+		}
+	}
+}
+
+// checkLock returns ErrLockLost unless this migrator still holds the lock.
+// It is checked before each version is recorded, so a runner whose lock was
+// taken over does not record versions the new holder may also apply.
+func (m *Migrator) checkLock(ctx context.Context) error {
+	err := m.lock.FindOne(ctx, bson.M{"_id": lockID, "owner": m.owner}).Err()
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrLockLost
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check migration lock: %w", err)
+	}
+	return nil
+}
+
+func (m *Migrator) acquireLock(ctx context.Context) error {
+	now := time.Now().UTC()
+
+	_, err := m.lock.InsertOne(ctx, migrationLock{ID: lockID, Owner: m.owner, LockedAt: now})
+	if err == nil {
+		return nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+
+	// Take over the lock only if its holder has gone quiet for too long.
+	var current migrationLock
+	err = m.lock.FindOneAndUpdate(ctx,
+		bson.M{"_id": lockID, "locked_at": bson.M{"$lt": now.Add(-staleLockAfter)}},
+		bson.M{"$set": bson.M{"owner": m.owner, "locked_at": now}},
+		options.FindOneAndUpdate().SetReturnDocument(options.Before),
+	).Decode(&current)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrLocked
+		}
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+
+	m.logger.WarnContext(ctx, "took over stale migration lock",
+		"previous_owner", current.Owner,
+		"locked_at", current.LockedAt)
+	return nil
+}
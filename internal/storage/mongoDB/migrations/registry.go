@@ -0,0 +1,440 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/zohirovs/internal/models"
+	mongodb "github.com/zohirovs/internal/storage/mongoDB"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// registry holds every known migration. Versions must be unique and must
+// never be reused once released; append new migrations at the end. Index
+// definitions are spelled out here rather than taken from the storage code,
+// so a released migration never changes.
+var registry = []Migration{
+	{
+		Version:     1,
+		Description: "create Users indexes",
+		Up: func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error {
+			return createIndexes(ctx, db, "Users",
+				mongo.IndexModel{Keys: bson.D{{Key: "email", Value: 1}}, Options: options.Index().SetUnique(true)},
+				mongo.IndexModel{Keys: bson.D{{Key: "username", Value: 1}}, Options: options.Index().SetUnique(true)},
+				mongo.IndexModel{Keys: bson.D{{Key: "role", Value: 1}}},
+			)
+		},
+		Down: dropIndexes("Users", "email_1", "username_1", "role_1"),
+	},
+	{
+		Version:     2,
+		Description: "create Tenders indexes",
+		Up: func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error {
+			return createIndexes(ctx, db, "Tenders",
+				mongo.IndexModel{Keys: bson.D{{Key: "tenderid", Value: 1}}, Options: options.Index().SetUnique(true)},
+				mongo.IndexModel{Keys: bson.D{{Key: "clientid", Value: 1}}},
+				mongo.IndexModel{Keys: bson.D{{Key: "status", Value: 1}, {Key: "deadline", Value: 1}}},
+			)
+		},
+		Down: dropIndexes("Tenders", "tenderid_1", "clientid_1", "status_1_deadline_1"),
+	},
+	{
+		Version:     3,
+		Description: "create Bids indexes",
+		Up: func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error {
+			return createIndexes(ctx, db, "Bids",
+				mongo.IndexModel{Keys: bson.D{{Key: "bid_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+				mongo.IndexModel{Keys: bson.D{{Key: "tender_id", Value: 1}}},
+				mongo.IndexModel{Keys: bson.D{{Key: "contractor_id", Value: 1}}},
+				mongo.IndexModel{Keys: bson.D{{Key: "price", Value: 1}}},
+				mongo.IndexModel{Keys: bson.D{{Key: "delivery_time", Value: 1}}},
+			)
+		},
+		Down: dropIndexes("Bids", "bid_id_1", "tender_id_1", "contractor_id_1", "price_1", "delivery_time_1"),
+	},
+	{
+		Version:     4,
+		Description: "create Notifications indexes",
+		Up: func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error {
+			return createIndexes(ctx, db, "Notifications",
+				mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+				mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "read", Value: 1}}},
+			)
+		},
+		Down: dropIndexes("Notifications", "user_id_1_created_at_-1", "user_id_1_read_1"),
+	},
+	{
+		Version:     5,
+		Description: "convert string tender deadlines to dates",
+		Up: func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error {
+			converted, err := mongodb.NewTenderStorage(db, logger, nil).ConvertStringDeadlines(ctx)
+			if err != nil {
+				return err
+			}
+			logger.InfoContext(ctx, "converted tender deadlines", "count", converted)
+			return nil
+		},
+		// Date deadlines are what the application expects; there is nothing to undo.
+		Down: nil,
+	},
+	{
+		Version:     6,
+		Description: "create Attachments indexes",
+		Up: func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error {
+			return createIndexes(ctx, db, "Attachments",
+				mongo.IndexModel{Keys: bson.D{{Key: "attachment_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+				mongo.IndexModel{Keys: bson.D{{Key: "tender_id", Value: 1}}},
+				mongo.IndexModel{Keys: bson.D{{Key: "bid_id", Value: 1}}},
+			)
+		},
+		Down: dropIndexes("Attachments", "attachment_id_1", "tender_id_1", "bid_id_1"),
+	},
+	{
+		Version:     7,
+		Description: "create ContractorProfiles indexes",
+		Up: func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error {
+			return createIndexes(ctx, db, "ContractorProfiles",
+				mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+				mongo.IndexModel{Keys: bson.D{{Key: "registration_number", Value: 1}}, Options: options.Index().SetUnique(true)},
+				mongo.IndexModel{Keys: bson.D{{Key: "verification_status", Value: 1}}},
+			)
+		},
+		Down: dropIndexes("ContractorProfiles", "user_id_1", "registration_number_1", "verification_status_1"),
+	},
+	{
+		Version:     8,
+		Description: "create Reviews and ContractorReputation indexes",
+		Up: func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error {
+			if err := createIndexes(ctx, db, "Reviews",
+				mongo.IndexModel{Keys: bson.D{{Key: "tender_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+				mongo.IndexModel{Keys: bson.D{{Key: "contractor_id", Value: 1}, {Key: "created_at", Value: -1}}},
+			); err != nil {
+				return err
+			}
+			return createIndexes(ctx, db, "ContractorReputation",
+				mongo.IndexModel{Keys: bson.D{{Key: "contractor_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+			)
+		},
+		Down: func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error {
+			if err := dropIndexes("Reviews", "tender_id_1", "contractor_id_1_created_at_-1")(ctx, db, logger); err != nil {
+				return err
+			}
+			return dropIndexes("ContractorReputation", "contractor_id_1")(ctx, db, logger)
+		},
+	},
+	{
+		Version:     9,
+		Description: "create Categories and SavedSearches indexes, index tender categories and tags",
+		Up: func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error {
+			if err := createIndexes(ctx, db, "Categories",
+				mongo.IndexModel{Keys: bson.D{{Key: "category_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+				mongo.IndexModel{Keys: bson.D{{Key: "slug", Value: 1}}, Options: options.Index().SetUnique(true)},
+				mongo.IndexModel{Keys: bson.D{{Key: "parent_id", Value: 1}}},
+			); err != nil {
+				return err
+			}
+			if err := createIndexes(ctx, db, "SavedSearches",
+				mongo.IndexModel{Keys: bson.D{{Key: "search_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+				mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}}},
+				mongo.IndexModel{Keys: bson.D{{Key: "category_id", Value: 1}, {Key: "min_budget", Value: 1}}},
+			); err != nil {
+				return err
+			}
+			if err := createIndexes(ctx, db, "Tenders",
+				mongo.IndexModel{Keys: bson.D{{Key: "categorypath", Value: 1}}},
+				mongo.IndexModel{Keys: bson.D{{Key: "tags", Value: 1}}},
+			); err != nil {
+				return err
+			}
+			return createIndexes(ctx, db, "Notifications",
+				mongo.IndexModel{Keys: bson.D{{Key: "notification_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+				mongo.IndexModel{Keys: bson.D{{Key: "digest", Value: 1}, {Key: "emailed_at", Value: 1}}},
+			)
+		},
+		Down: func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error {
+			for collection, names := range map[string][]string{
+				"Categories":    {"category_id_1", "slug_1", "parent_id_1"},
+				"SavedSearches": {"search_id_1", "user_id_1", "category_id_1_min_budget_1"},
+				"Tenders":       {"categorypath_1", "tags_1"},
+				"Notifications": {"notification_id_1", "digest_1_emailed_at_1"},
+			} {
+				if err := dropIndexes(collection, names...)(ctx, db, logger); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version:     10,
+		Description: "create TenderSearch text index and index existing tenders",
+		Up: func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error {
+			if err := createIndexes(ctx, db, "TenderSearch",
+				mongo.IndexModel{Keys: bson.D{{Key: "tender_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+				mongo.IndexModel{
+					Keys: bson.D{{Key: "title", Value: "text"}, {Key: "description", Value: "text"}},
+					Options: options.Index().
+						SetName("tender_text").
+						SetWeights(bson.D{{Key: "title", Value: 10}, {Key: "description", Value: 2}}).
+						SetDefaultLanguage("english").
+						SetLanguageOverride("language"),
+				},
+				mongo.IndexModel{Keys: bson.D{{Key: "status", Value: 1}, {Key: "category_path", Value: 1}, {Key: "budget", Value: 1}}},
+			); err != nil {
+				return err
+			}
+
+			index := mongodb.NewTenderSearchStorage(db, models.DefaultSearchLanguage, logger)
+
+			cursor, err := db.Collection("Tenders").Find(ctx, bson.M{})
+			if err != nil {
+				return fmt.Errorf("failed to read tenders: %w", err)
+			}
+			defer cursor.Close(ctx)
+
+			indexed := 0
+			for cursor.Next(ctx) {
+				var tender models.Tender
+				if err := cursor.Decode(&tender); err != nil {
+					return fmt.Errorf("failed to decode tender: %w", err)
+				}
+				if err := index.IndexTender(ctx, &tender); err != nil {
+					return err
+				}
+				indexed++
+			}
+			if err := cursor.Err(); err != nil {
+				return fmt.Errorf("failed to read tenders: %w", err)
+			}
+
+			logger.InfoContext(ctx, "indexed existing tenders for search", "count", indexed)
+			return nil
+		},
+		Down: func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error {
+			if err := db.Collection("TenderSearch").Drop(ctx); err != nil {
+				return fmt.Errorf("failed to drop TenderSearch: %w", err)
+			}
+			return nil
+		},
+	},
+	{
+		Version:     11,
+		Description: "create Questions indexes",
+		Up: func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error {
+			return createIndexes(ctx, db, "Questions",
+				mongo.IndexModel{Keys: bson.D{{Key: "question_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+				mongo.IndexModel{Keys: bson.D{{Key: "tender_id", Value: 1}, {Key: "created_at", Value: 1}}},
+			)
+		},
+		Down: dropIndexes("Questions", "question_id_1", "tender_id_1_created_at_1"),
+	},
+	{
+		Version:     12,
+		Description: "create Invitations indexes and index invited contractors for search",
+		Up: func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error {
+			if err := createIndexes(ctx, db, "Invitations",
+				mongo.IndexModel{Keys: bson.D{{Key: "invitation_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+				mongo.IndexModel{Keys: bson.D{{Key: "tender_id", Value: 1}, {Key: "contractor_id", Value: 1}}},
+				mongo.IndexModel{Keys: bson.D{{Key: "contractor_id", Value: 1}}},
+				mongo.IndexModel{Keys: bson.D{{Key: "email", Value: 1}}},
+			); err != nil {
+				return err
+			}
+			return createIndexes(ctx, db, "TenderSearch",
+				mongo.IndexModel{Keys: bson.D{{Key: "invited", Value: 1}}},
+			)
+		},
+		Down: func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error {
+			if err := dropIndexes("Invitations", "invitation_id_1", "tender_id_1_contractor_id_1", "contractor_id_1", "email_1")(ctx, db, logger); err != nil {
+				return err
+			}
+			return dropIndexes("TenderSearch", "invited_1")(ctx, db, logger)
+		},
+	},
+	{
+		Version:     13,
+		Description: "create TenderTemplates indexes",
+		Up: func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error {
+			return createIndexes(ctx, db, "TenderTemplates",
+				mongo.IndexModel{Keys: bson.D{{Key: "template_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+				mongo.IndexModel{Keys: bson.D{{Key: "client_id", Value: 1}, {Key: "name", Value: 1}}},
+			)
+		},
+		Down: dropIndexes("TenderTemplates", "template_id_1", "client_id_1_name_1"),
+	},
+	{
+		Version:     14,
+		Description: "create Organizations indexes and index tenders and bids by organization",
+		Up: func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error {
+			if err := createIndexes(ctx, db, "Organizations",
+				mongo.IndexModel{Keys: bson.D{{Key: "organization_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+			); err != nil {
+				return err
+			}
+			if err := createIndexes(ctx, db, "OrganizationMembers",
+				mongo.IndexModel{Keys: bson.D{{Key: "organization_id", Value: 1}, {Key: "user_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+				mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}}},
+			); err != nil {
+				return err
+			}
+			if err := createIndexes(ctx, db, "OrganizationInvites",
+				mongo.IndexModel{Keys: bson.D{{Key: "invite_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+				mongo.IndexModel{Keys: bson.D{{Key: "organization_id", Value: 1}, {Key: "email", Value: 1}, {Key: "status", Value: 1}}},
+				mongo.IndexModel{Keys: bson.D{{Key: "email", Value: 1}, {Key: "status", Value: 1}}},
+			); err != nil {
+				return err
+			}
+			if err := createIndexes(ctx, db, "Tenders",
+				mongo.IndexModel{Keys: bson.D{{Key: "organizationid", Value: 1}}},
+			); err != nil {
+				return err
+			}
+			return createIndexes(ctx, db, "Bids",
+				mongo.IndexModel{Keys: bson.D{{Key: "organization_id", Value: 1}}},
+			)
+		},
+		Down: func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error {
+			for collection, names := range map[string][]string{
+				"Organizations":       {"organization_id_1"},
+				"OrganizationMembers": {"organization_id_1_user_id_1", "user_id_1"},
+				"OrganizationInvites": {"invite_id_1", "organization_id_1_email_1_status_1", "email_1_status_1"},
+				"Tenders":             {"organizationid_1"},
+				"Bids":                {"organization_id_1"},
+			} {
+				if err := dropIndexes(collection, names...)(ctx, db, logger); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version:     15,
+		Description: "create AuditLog indexes",
+		Up: func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error {
+			return createIndexes(ctx, db, "AuditLog",
+				mongo.IndexModel{Keys: bson.D{{Key: "seq", Value: 1}}, Options: options.Index().SetUnique(true)},
+				mongo.IndexModel{Keys: bson.D{{Key: "actor_id", Value: 1}, {Key: "seq", Value: -1}}},
+				mongo.IndexModel{Keys: bson.D{{Key: "resource_type", Value: 1}, {Key: "resource_id", Value: 1}, {Key: "seq", Value: -1}}},
+				mongo.IndexModel{Keys: bson.D{{Key: "action", Value: 1}, {Key: "seq", Value: -1}}},
+				mongo.IndexModel{Keys: bson.D{{Key: "request_id", Value: 1}}},
+				mongo.IndexModel{Keys: bson.D{{Key: "at", Value: -1}}},
+			)
+		},
+		Down: dropIndexes("AuditLog", "seq_1", "actor_id_1_seq_-1", "resource_type_1_resource_id_1_seq_-1", "action_1_seq_-1", "request_id_1", "at_-1"),
+	},
+	{
+		Version:     16,
+		Description: "index soft-deletion times for the retention purge",
+		Up: func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error {
+			for collection, key := range map[string]string{
+				"Tenders": "deletedat",
+				"Bids":    "deleted_at",
+				"Users":   "deletedat",
+			} {
+				if err := createIndexes(ctx, db, collection,
+					mongo.IndexModel{Keys: bson.D{{Key: key, Value: 1}}},
+				); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error {
+			for collection, name := range map[string]string{
+				"Tenders": "deletedat_1",
+				"Bids":    "deleted_at_1",
+				"Users":   "deletedat_1",
+			} {
+				if err := dropIndexes(collection, name)(ctx, db, logger); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version:     17,
+		Description: "create QuotaOverrides indexes",
+		Up: func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error {
+			return createIndexes(ctx, db, "QuotaOverrides",
+				mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+			)
+		},
+		Down: dropIndexes("QuotaOverrides", "user_id_1"),
+	},
+	{
+		Version:     18,
+		Description: "allow one review per winner of a tender instead of one per tender",
+		Up: func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error {
+			if err := createIndexes(ctx, db, "Reviews", mongo.IndexModel{
+				Keys:    bson.D{{Key: "tender_id", Value: 1}, {Key: "contractor_id", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			}); err != nil {
+				return err
+			}
+			return dropIndexes("Reviews", "tender_id_1")(ctx, db, logger)
+		},
+		Down: func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error {
+			// Fails if a tender has since been reviewed for several winners.
+			if err := createIndexes(ctx, db, "Reviews", mongo.IndexModel{
+				Keys:    bson.D{{Key: "tender_id", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			}); err != nil {
+				return err
+			}
+			return dropIndexes("Reviews", "tender_id_1_contractor_id_1")(ctx, db, logger)
+		},
+	},
+	{
+		Version:     19,
+		Description: "list organization invites by the user who answered them instead of by email",
+		Up: func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error {
+			if err := createIndexes(ctx, db, "OrganizationInvites",
+				mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}}},
+			); err != nil {
+				return err
+			}
+			return dropIndexes("OrganizationInvites", "email_1_status_1")(ctx, db, logger)
+		},
+		Down: func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error {
+			if err := createIndexes(ctx, db, "OrganizationInvites",
+				mongo.IndexModel{Keys: bson.D{{Key: "email", Value: 1}, {Key: "status", Value: 1}}},
+			); err != nil {
+				return err
+			}
+			return dropIndexes("OrganizationInvites", "user_id_1")(ctx, db, logger)
+		},
+	},
+}
+
+// createIndexes adds indexes to a collection.
+func createIndexes(ctx context.Context, db *mongo.Database, collection string, indexes ...mongo.IndexModel) error {
+	if _, err := db.Collection(collection).Indexes().CreateMany(ctx, indexes); err != nil {
+		return fmt.Errorf("failed to create indexes on %s: %w", collection, err)
+	}
+	return nil
+}
+
+// dropIndexes returns a Down function that drops the named indexes, ignoring
+// ones that no longer exist.
+func dropIndexes(collection string, names ...string) func(context.Context, *mongo.Database, *slog.Logger) error {
+	return func(ctx context.Context, db *mongo.Database, logger *slog.Logger) error {
+		indexes := db.Collection(collection).Indexes()
+		for _, name := range names {
+			if _, err := indexes.DropOne(ctx, name); err != nil {
+				var cmdErr mongo.CommandError
+				if errors.As(err, &cmdErr) && cmdErr.Name == "IndexNotFound" {
+					logger.WarnContext(ctx, "index already dropped", "collection", collection, "index", name)
+					continue
+				}
+				return fmt.Errorf("failed to drop index %s on %s: %w", name, collection, err)
+			}
+		}
+		return nil
+	}
+}
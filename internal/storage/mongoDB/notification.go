@@ -1,10 +1,18 @@
 package mongodb
 
 import (
+	"context"
+	"fmt"
 	"log/slog"
+	"time"
 
+	"github.com/zohirovs/internal/models"
 	"github.com/zohirovs/internal/storage/redis"
+	"github.com/zohirovs/internal/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type NotificationStorage struct {
@@ -20,3 +28,139 @@ func NewNotificationStorage(db *mongo.Database, logger *slog.Logger, cache *redi
 		notificationCache: cache,
 	}
 }
+
+func (s *NotificationStorage) CreateNotifications(ctx context.Context, notifications []*models.Notification) error {
+	ctx, span := tracing.Start(ctx, "NotificationStorage.CreateNotifications")
+	defer span.End()
+
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	docs := make([]interface{}, 0, len(notifications))
+	for _, notification := range notifications {
+		if notification.NotificationId == "" {
+			notification.NotificationId = primitive.NewObjectID().Hex()
+		}
+		notification.CreatedAt = now
+		docs = append(docs, notification)
+	}
+
+	if _, err := s.db.InsertMany(ctx, docs); err != nil {
+		s.logger.ErrorContext(ctx, "failed to create notifications",
+			"error", err,
+			"count", len(docs))
+		return fmt.Errorf("failed to create notifications: %w", err)
+	}
+
+	return nil
+}
+
+func (s *NotificationStorage) ListNotifications(ctx context.Context, userId string, unreadOnly bool) ([]*models.Notification, error) {
+	ctx, span := tracing.Start(ctx, "NotificationStorage.ListNotifications")
+	defer span.End()
+
+	filter := bson.M{"user_id": userId}
+	if unreadOnly {
+		filter["read"] = false
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(100)
+
+	cursor, err := s.db.Find(ctx, filter, opts)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list notifications",
+			"error", err,
+			"user_id", userId)
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var notifications []*models.Notification
+	if err = cursor.All(ctx, &notifications); err != nil {
+		return nil, fmt.Errorf("failed to decode notifications: %w", err)
+	}
+
+	return notifications, nil
+}
+
+func (s *NotificationStorage) MarkRead(ctx context.Context, userId string, notificationId string) error {
+	ctx, span := tracing.Start(ctx, "NotificationStorage.MarkRead")
+	defer span.End()
+
+	result, err := s.db.UpdateOne(ctx,
+		bson.M{"notification_id": notificationId, "user_id": userId},
+		bson.M{"$set": bson.M{"read": true}})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to mark notification read",
+			"error", err,
+			"notification_id", notificationId)
+		return fmt.Errorf("failed to mark notification read: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("notification not found: %s", notificationId)
+	}
+
+	return nil
+}
+
+// ListPendingDigest returns digest notifications that have not been emailed yet.
+func (s *NotificationStorage) ListPendingDigest(ctx context.Context) ([]*models.Notification, error) {
+	ctx, span := tracing.Start(ctx, "NotificationStorage.ListPendingDigest")
+	defer span.End()
+
+	filter := bson.M{"digest": true, "emailed_at": bson.M{"$exists": false}}
+	opts := options.Find().SetSort(bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: 1}})
+
+	cursor, err := s.db.Find(ctx, filter, opts)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list digest notifications",
+			"error", err)
+		return nil, fmt.Errorf("failed to list digest notifications: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var notifications []*models.Notification
+	if err = cursor.All(ctx, &notifications); err != nil {
+		return nil, fmt.Errorf("failed to decode notifications: %w", err)
+	}
+
+	return notifications, nil
+}
+
+func (s *NotificationStorage) MarkEmailed(ctx context.Context, notificationIds []string) error {
+	ctx, span := tracing.Start(ctx, "NotificationStorage.MarkEmailed")
+	defer span.End()
+
+	_, err := s.db.UpdateMany(ctx,
+		bson.M{"notification_id": bson.M{"$in": notificationIds}},
+		bson.M{"$set": bson.M{"emailed_at": time.Now().UTC()}})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to mark notifications emailed",
+			"error", err)
+		return fmt.Errorf("failed to mark notifications emailed: %w", err)
+	}
+
+	return nil
+}
+
+func (s *NotificationStorage) PurgeNotifications(ctx context.Context, tenderIds []string, userIds []string) (int64, error) {
+	ctx, span := tracing.Start(ctx, "NotificationStorage.PurgeNotifications")
+	defer span.End()
+
+	filter := referencing(map[string][]string{"tender_id": tenderIds, "user_id": userIds})
+	if filter == nil {
+		return 0, nil
+	}
+
+	result, err := s.db.DeleteMany(ctx, filter)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to purge notifications",
+			"error", err)
+		return 0, fmt.Errorf("failed to purge notifications: %w", err)
+	}
+
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,333 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type OrganizationStorage struct {
+	db      *mongo.Collection
+	members *mongo.Collection
+	invites *mongo.Collection
+	logger  *slog.Logger
+}
+
+func NewOrganizationStorage(db *mongo.Database, logger *slog.Logger) *OrganizationStorage {
+	return &OrganizationStorage{
+		db:      db.Collection("Organizations"),
+		members: db.Collection("OrganizationMembers"),
+		invites: db.Collection("OrganizationInvites"),
+		logger:  logger,
+	}
+}
+
+func (s *OrganizationStorage) CreateOrganization(ctx context.Context, org *models.Organization) (*models.Organization, error) {
+	ctx, span := tracing.Start(ctx, "OrganizationStorage.CreateOrganization")
+	defer span.End()
+
+	if org.OrganizationId == "" {
+		org.OrganizationId = primitive.NewObjectID().Hex()
+	}
+	org.CreatedAt = time.Now().UTC()
+
+	if _, err := s.db.InsertOne(ctx, org); err != nil {
+		s.logger.ErrorContext(ctx, "failed to create organization",
+			"error", err,
+			"organization_id", org.OrganizationId)
+		return nil, fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	return org, nil
+}
+
+func (s *OrganizationStorage) GetOrganization(ctx context.Context, id string) (*models.Organization, error) {
+	ctx, span := tracing.Start(ctx, "OrganizationStorage.GetOrganization")
+	defer span.End()
+
+	var org models.Organization
+	err := s.db.FindOne(ctx, bson.M{"organization_id": id}).Decode(&org)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("organization not found: %s", id)
+		}
+		s.logger.ErrorContext(ctx, "failed to get organization",
+			"error", err,
+			"organization_id", id)
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+
+	return &org, nil
+}
+
+func (s *OrganizationStorage) ListOrganizations(ctx context.Context, ids []string) ([]*models.Organization, error) {
+	ctx, span := tracing.Start(ctx, "OrganizationStorage.ListOrganizations")
+	defer span.End()
+
+	orgs := []*models.Organization{}
+	if len(ids) == 0 {
+		return orgs, nil
+	}
+
+	cursor, err := s.db.Find(ctx, bson.M{"organization_id": bson.M{"$in": ids}}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list organizations",
+			"error", err)
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	if err = cursor.All(ctx, &orgs); err != nil {
+		return nil, fmt.Errorf("failed to decode organizations: %w", err)
+	}
+
+	return orgs, nil
+}
+
+func (s *OrganizationStorage) AddMember(ctx context.Context, member *models.OrganizationMember) error {
+	ctx, span := tracing.Start(ctx, "OrganizationStorage.AddMember")
+	defer span.End()
+
+	filter := bson.M{"organization_id": member.OrganizationId, "user_id": member.UserId}
+	update := bson.M{
+		"$set":         bson.M{"role": member.Role},
+		"$setOnInsert": bson.M{"joined_at": time.Now().UTC()},
+	}
+
+	if _, err := s.members.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true)); err != nil {
+		s.logger.ErrorContext(ctx, "failed to add organization member",
+			"error", err,
+			"organization_id", member.OrganizationId,
+			"user_id", member.UserId)
+		return fmt.Errorf("failed to add organization member: %w", err)
+	}
+
+	return nil
+}
+
+func (s *OrganizationStorage) GetMember(ctx context.Context, orgId string, userId string) (*models.OrganizationMember, error) {
+	ctx, span := tracing.Start(ctx, "OrganizationStorage.GetMember")
+	defer span.End()
+
+	var member models.OrganizationMember
+	err := s.members.FindOne(ctx, bson.M{"organization_id": orgId, "user_id": userId}).Decode(&member)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		s.logger.ErrorContext(ctx, "failed to get organization member",
+			"error", err,
+			"organization_id", orgId)
+		return nil, fmt.Errorf("failed to get organization member: %w", err)
+	}
+
+	return &member, nil
+}
+
+func (s *OrganizationStorage) ListMembers(ctx context.Context, orgId string) ([]*models.OrganizationMember, error) {
+	ctx, span := tracing.Start(ctx, "OrganizationStorage.ListMembers")
+	defer span.End()
+
+	return s.findMembers(ctx, bson.M{"organization_id": orgId})
+}
+
+func (s *OrganizationStorage) ListMemberships(ctx context.Context, userId string) ([]*models.OrganizationMember, error) {
+	ctx, span := tracing.Start(ctx, "OrganizationStorage.ListMemberships")
+	defer span.End()
+
+	return s.findMembers(ctx, bson.M{"user_id": userId})
+}
+
+func (s *OrganizationStorage) UpdateMemberRole(ctx context.Context, orgId string, userId string, role models.OrgRole) error {
+	ctx, span := tracing.Start(ctx, "OrganizationStorage.UpdateMemberRole")
+	defer span.End()
+
+	result, err := s.members.UpdateOne(ctx,
+		bson.M{"organization_id": orgId, "user_id": userId},
+		bson.M{"$set": bson.M{"role": role}})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to update organization member role",
+			"error", err,
+			"organization_id", orgId,
+			"user_id", userId)
+		return fmt.Errorf("failed to update member role: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("member not found: %s", userId)
+	}
+
+	return nil
+}
+
+func (s *OrganizationStorage) RemoveMember(ctx context.Context, orgId string, userId string) error {
+	ctx, span := tracing.Start(ctx, "OrganizationStorage.RemoveMember")
+	defer span.End()
+
+	result, err := s.members.DeleteOne(ctx, bson.M{"organization_id": orgId, "user_id": userId})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to remove organization member",
+			"error", err,
+			"organization_id", orgId,
+			"user_id", userId)
+		return fmt.Errorf("failed to remove member: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("member not found: %s", userId)
+	}
+
+	return nil
+}
+
+func (s *OrganizationStorage) CountOwners(ctx context.Context, orgId string) (int64, error) {
+	ctx, span := tracing.Start(ctx, "OrganizationStorage.CountOwners")
+	defer span.End()
+
+	count, err := s.members.CountDocuments(ctx, bson.M{"organization_id": orgId, "role": models.OrgOwner})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to count organization owners",
+			"error", err,
+			"organization_id", orgId)
+		return 0, fmt.Errorf("failed to count owners: %w", err)
+	}
+
+	return count, nil
+}
+
+func (s *OrganizationStorage) CreateMemberInvite(ctx context.Context, invite *models.MemberInvite) (*models.MemberInvite, error) {
+	ctx, span := tracing.Start(ctx, "OrganizationStorage.CreateMemberInvite")
+	defer span.End()
+
+	filter := bson.M{
+		"organization_id": invite.OrganizationId,
+		"email":           invite.Email,
+		"status":          models.MemberInvitePending,
+	}
+	update := bson.M{
+		// Re-inviting mails a new token, so only the latest one works.
+		"$set": bson.M{
+			"role":       invite.Role,
+			"invited_by": invite.InvitedBy,
+			"token_hash": invite.TokenHash,
+		},
+		"$setOnInsert": bson.M{
+			"invite_id":  primitive.NewObjectID().Hex(),
+			"created_at": time.Now().UTC(),
+		},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var result models.MemberInvite
+	if err := s.invites.FindOneAndUpdate(ctx, filter, update, opts).Decode(&result); err != nil {
+		s.logger.ErrorContext(ctx, "failed to create organization invite",
+			"error", err,
+			"organization_id", invite.OrganizationId)
+		return nil, fmt.Errorf("failed to create invite: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (s *OrganizationStorage) GetMemberInvite(ctx context.Context, id string) (*models.MemberInvite, error) {
+	ctx, span := tracing.Start(ctx, "OrganizationStorage.GetMemberInvite")
+	defer span.End()
+
+	var invite models.MemberInvite
+	err := s.invites.FindOne(ctx, bson.M{"invite_id": id}).Decode(&invite)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("invite not found: %s", id)
+		}
+		s.logger.ErrorContext(ctx, "failed to get organization invite",
+			"error", err,
+			"invite_id", id)
+		return nil, fmt.Errorf("failed to get invite: %w", err)
+	}
+
+	return &invite, nil
+}
+
+func (s *OrganizationStorage) ListMemberInvites(ctx context.Context, orgId string) ([]*models.MemberInvite, error) {
+	ctx, span := tracing.Start(ctx, "OrganizationStorage.ListMemberInvites")
+	defer span.End()
+
+	return s.findInvites(ctx, bson.M{"organization_id": orgId})
+}
+
+func (s *OrganizationStorage) ListUserInvites(ctx context.Context, userId string) ([]*models.MemberInvite, error) {
+	ctx, span := tracing.Start(ctx, "OrganizationStorage.ListUserInvites")
+	defer span.End()
+
+	return s.findInvites(ctx, bson.M{"user_id": userId})
+}
+
+func (s *OrganizationStorage) RespondMemberInvite(ctx context.Context, id string, userId string, status models.MemberInviteStatus) (*models.MemberInvite, error) {
+	ctx, span := tracing.Start(ctx, "OrganizationStorage.RespondMemberInvite")
+	defer span.End()
+
+	update := bson.M{
+		"$set": bson.M{
+			"user_id":      userId,
+			"status":       status,
+			"responded_at": time.Now().UTC(),
+		},
+		"$unset": bson.M{"token_hash": ""},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var invite models.MemberInvite
+	err := s.invites.FindOneAndUpdate(ctx, bson.M{"invite_id": id, "status": models.MemberInvitePending}, update, opts).Decode(&invite)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("pending invite not found: %s", id)
+		}
+		s.logger.ErrorContext(ctx, "failed to respond to organization invite",
+			"error", err,
+			"invite_id", id)
+		return nil, fmt.Errorf("failed to respond to invite: %w", err)
+	}
+
+	return &invite, nil
+}
+
+func (s *OrganizationStorage) findMembers(ctx context.Context, filter bson.M) ([]*models.OrganizationMember, error) {
+	cursor, err := s.members.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "joined_at", Value: 1}}))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list organization members",
+			"error", err)
+		return nil, fmt.Errorf("failed to list members: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	members := []*models.OrganizationMember{}
+	if err = cursor.All(ctx, &members); err != nil {
+		return nil, fmt.Errorf("failed to decode members: %w", err)
+	}
+
+	return members, nil
+}
+
+func (s *OrganizationStorage) findInvites(ctx context.Context, filter bson.M) ([]*models.MemberInvite, error) {
+	cursor, err := s.invites.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list organization invites",
+			"error", err)
+		return nil, fmt.Errorf("failed to list invites: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	invites := []*models.MemberInvite{}
+	if err = cursor.All(ctx, &invites); err != nil {
+		return nil, fmt.Errorf("failed to decode invites: %w", err)
+	}
+
+	return invites, nil
+}
@@ -0,0 +1,137 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type QuestionStorage struct {
+	db     *mongo.Collection
+	logger *slog.Logger
+}
+
+func NewQuestionStorage(db *mongo.Database, logger *slog.Logger) *QuestionStorage {
+	return &QuestionStorage{
+		db:     db.Collection("Questions"),
+		logger: logger,
+	}
+}
+
+func (s *QuestionStorage) CreateQuestion(ctx context.Context, question *models.Question) (*models.Question, error) {
+	ctx, span := tracing.Start(ctx, "QuestionStorage.CreateQuestion")
+	defer span.End()
+
+	if question.QuestionId == "" {
+		question.QuestionId = primitive.NewObjectID().Hex()
+	}
+	question.CreatedAt = time.Now().UTC()
+
+	_, err := s.db.InsertOne(ctx, question)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to create question",
+			"error", err,
+			"tender_id", question.TenderId)
+		return nil, fmt.Errorf("failed to create question: %w", err)
+	}
+
+	return question, nil
+}
+
+func (s *QuestionStorage) GetQuestion(ctx context.Context, id string) (*models.Question, error) {
+	ctx, span := tracing.Start(ctx, "QuestionStorage.GetQuestion")
+	defer span.End()
+
+	var question models.Question
+	err := s.db.FindOne(ctx, bson.M{"question_id": id}).Decode(&question)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("question not found: %s", id)
+		}
+		s.logger.ErrorContext(ctx, "failed to get question",
+			"error", err,
+			"question_id", id)
+		return nil, fmt.Errorf("failed to get question: %w", err)
+	}
+
+	return &question, nil
+}
+
+func (s *QuestionStorage) ListQuestions(ctx context.Context, tenderId string) ([]*models.Question, error) {
+	ctx, span := tracing.Start(ctx, "QuestionStorage.ListQuestions")
+	defer span.End()
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := s.db.Find(ctx, bson.M{"tender_id": tenderId}, opts)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list questions",
+			"error", err,
+			"tender_id", tenderId)
+		return nil, fmt.Errorf("failed to list questions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	questions := []*models.Question{}
+	if err = cursor.All(ctx, &questions); err != nil {
+		return nil, fmt.Errorf("failed to decode questions: %w", err)
+	}
+
+	return questions, nil
+}
+
+func (s *QuestionStorage) AnswerQuestion(ctx context.Context, id string, answer string, publish bool) (*models.Question, error) {
+	ctx, span := tracing.Start(ctx, "QuestionStorage.AnswerQuestion")
+	defer span.End()
+
+	set := bson.M{
+		"answer":      answer,
+		"answered_at": time.Now().UTC(),
+	}
+	if publish {
+		set["published"] = true
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var question models.Question
+	err := s.db.FindOneAndUpdate(ctx, bson.M{"question_id": id}, bson.M{"$set": set}, opts).Decode(&question)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("question not found: %s", id)
+		}
+		s.logger.ErrorContext(ctx, "failed to answer question",
+			"error", err,
+			"question_id", id)
+		return nil, fmt.Errorf("failed to answer question: %w", err)
+	}
+
+	return &question, nil
+}
+
+func (s *QuestionStorage) PurgeQuestions(ctx context.Context, tenderIds []string) (int64, error) {
+	ctx, span := tracing.Start(ctx, "QuestionStorage.PurgeQuestions")
+	defer span.End()
+
+	filter := referencing(map[string][]string{"tender_id": tenderIds})
+	if filter == nil {
+		return 0, nil
+	}
+
+	result, err := s.db.DeleteMany(ctx, filter)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to purge questions",
+			"error", err)
+		return 0, fmt.Errorf("failed to purge questions: %w", err)
+	}
+
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,110 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type QuotaStorage struct {
+	db     *mongo.Collection
+	logger *slog.Logger
+}
+
+func NewQuotaStorage(db *mongo.Database, logger *slog.Logger) *QuotaStorage {
+	return &QuotaStorage{
+		db:     db.Collection("QuotaOverrides"),
+		logger: logger,
+	}
+}
+
+func (s *QuotaStorage) GetOverride(ctx context.Context, userID string) (*models.QuotaOverride, error) {
+	ctx, span := tracing.Start(ctx, "QuotaStorage.GetOverride")
+	defer span.End()
+
+	var override models.QuotaOverride
+
+	err := s.db.FindOne(ctx, bson.M{"user_id": userID}).Decode(&override)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		s.logger.ErrorContext(ctx, "failed to get quota override",
+			"error", err,
+			"user_id", userID)
+		return nil, fmt.Errorf("failed to get quota override: %w", err)
+	}
+
+	return &override, nil
+}
+
+// SetOverride replaces a user's override as a whole.
+func (s *QuotaStorage) SetOverride(ctx context.Context, override *models.QuotaOverride) (*models.QuotaOverride, error) {
+	ctx, span := tracing.Start(ctx, "QuotaStorage.SetOverride")
+	defer span.End()
+
+	override.UpdatedAt = time.Now().UTC()
+
+	_, err := s.db.ReplaceOne(ctx,
+		bson.M{"user_id": override.UserId},
+		override,
+		options.Replace().SetUpsert(true),
+	)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to set quota override",
+			"error", err,
+			"user_id", override.UserId)
+		return nil, fmt.Errorf("failed to set quota override: %w", err)
+	}
+
+	return override, nil
+}
+
+func (s *QuotaStorage) DeleteOverride(ctx context.Context, userID string) error {
+	ctx, span := tracing.Start(ctx, "QuotaStorage.DeleteOverride")
+	defer span.End()
+
+	result, err := s.db.DeleteOne(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to delete quota override",
+			"error", err,
+			"user_id", userID)
+		return fmt.Errorf("failed to delete quota override: %w", err)
+	}
+
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("quota override not found: %s", userID)
+	}
+
+	return nil
+}
+
+func (s *QuotaStorage) ListOverrides(ctx context.Context) ([]*models.QuotaOverride, error) {
+	ctx, span := tracing.Start(ctx, "QuotaStorage.ListOverrides")
+	defer span.End()
+
+	opts := options.Find().SetSort(bson.D{{Key: "updated_at", Value: -1}})
+
+	cursor, err := s.db.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list quota overrides",
+			"error", err)
+		return nil, fmt.Errorf("failed to list quota overrides: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	overrides := []*models.QuotaOverride{}
+	if err = cursor.All(ctx, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to decode quota overrides: %w", err)
+	}
+
+	return overrides, nil
+}
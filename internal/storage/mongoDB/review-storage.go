@@ -0,0 +1,240 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/repos"
+	"github.com/zohirovs/internal/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ReviewStorage struct {
+	db         *mongo.Collection
+	reputation *mongo.Collection
+	logger     *slog.Logger
+}
+
+func NewReviewStorage(db *mongo.Database, logger *slog.Logger) *ReviewStorage {
+	return &ReviewStorage{
+		db:         db.Collection("Reviews"),
+		reputation: db.Collection("ContractorReputation"),
+		logger:     logger,
+	}
+}
+
+// CreateReview stores the review and folds it into the contractor's aggregate.
+// The unique (tender_id, contractor_id) index guarantees one review per
+// winner, so the aggregate is only incremented for reviews that were actually
+// inserted. If the increment fails the aggregate is rebuilt from the reviews,
+// so it never stays out of step with them.
+func (s *ReviewStorage) CreateReview(ctx context.Context, review *models.Review) (*models.Review, error) {
+	ctx, span := tracing.Start(ctx, "ReviewStorage.CreateReview")
+	defer span.End()
+
+	if review.ReviewId == "" {
+		review.ReviewId = primitive.NewObjectID().Hex()
+	}
+	review.CreatedAt = time.Now().UTC()
+
+	if _, err := s.db.InsertOne(ctx, review); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, repos.ErrDuplicateReview
+		}
+		s.logger.ErrorContext(ctx, "failed to create review",
+			"error", err,
+			"tender_id", review.TenderId)
+		return nil, fmt.Errorf("failed to create review: %w", err)
+	}
+
+	// The review is stored; finish the aggregate even if the caller goes away.
+	ctx = context.WithoutCancel(ctx)
+
+	onTime := 0
+	if review.DeliveredOnTime {
+		onTime = 1
+	}
+
+	update := bson.M{
+		"$inc": bson.M{
+			"review_count":  1,
+			"rating_sum":    review.Rating,
+			"on_time_count": onTime,
+		},
+		"$set": bson.M{"updated_at": review.CreatedAt},
+	}
+
+	_, err := s.reputation.UpdateOne(ctx, bson.M{"contractor_id": review.ContractorId}, update, options.Update().SetUpsert(true))
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to update contractor reputation, recomputing it",
+			"error", err,
+			"contractor_id", review.ContractorId,
+			"review_id", review.ReviewId)
+		if err := s.recomputeReputation(ctx, review.ContractorId); err != nil {
+			s.logger.ErrorContext(ctx, "failed to recompute contractor reputation",
+				"error", err,
+				"contractor_id", review.ContractorId)
+			return nil, fmt.Errorf("failed to update contractor reputation: %w", err)
+		}
+	}
+
+	return review, nil
+}
+
+// PurgeReviews removes the tenders' reviews and rebuilds the reputation of
+// every contractor who lost one.
+func (s *ReviewStorage) PurgeReviews(ctx context.Context, tenderIds []string) (int64, error) {
+	ctx, span := tracing.Start(ctx, "ReviewStorage.PurgeReviews")
+	defer span.End()
+
+	if len(tenderIds) == 0 {
+		return 0, nil
+	}
+	filter := bson.M{"tender_id": bson.M{"$in": tenderIds}}
+
+	contractorIds, err := s.db.Distinct(ctx, "contractor_id", filter)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list reviewed contractors",
+			"error", err)
+		return 0, fmt.Errorf("failed to list reviewed contractors: %w", err)
+	}
+
+	result, err := s.db.DeleteMany(ctx, filter)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to purge reviews",
+			"error", err)
+		return 0, fmt.Errorf("failed to purge reviews: %w", err)
+	}
+
+	for _, id := range contractorIds {
+		contractorId, _ := id.(string)
+		if err := s.recomputeReputation(ctx, contractorId); err != nil {
+			s.logger.ErrorContext(ctx, "failed to recompute contractor reputation",
+				"error", err,
+				"contractor_id", contractorId)
+			return result.DeletedCount, fmt.Errorf("failed to recompute contractor reputation: %w", err)
+		}
+	}
+
+	return result.DeletedCount, nil
+}
+
+// recomputeReputation rebuilds the contractor's aggregate from their reviews.
+func (s *ReviewStorage) recomputeReputation(ctx context.Context, contractorId string) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"contractor_id": contractorId}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":           nil,
+			"review_count":  bson.M{"$sum": 1},
+			"rating_sum":    bson.M{"$sum": "$rating"},
+			"on_time_count": bson.M{"$sum": bson.M{"$cond": bson.A{"$delivered_on_time", 1, 0}}},
+		}}},
+	}
+	cursor, err := s.db.Aggregate(ctx, pipeline)
+	if err != nil {
+		return fmt.Errorf("failed to aggregate reviews: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var totals models.Reputation
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&totals); err != nil {
+			return fmt.Errorf("failed to decode review totals: %w", err)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to aggregate reviews: %w", err)
+	}
+
+	update := bson.M{"$set": bson.M{
+		"review_count":  totals.ReviewCount,
+		"rating_sum":    totals.RatingSum,
+		"on_time_count": totals.OnTimeCount,
+		"updated_at":    time.Now().UTC(),
+	}}
+	if _, err := s.reputation.UpdateOne(ctx, bson.M{"contractor_id": contractorId}, update, options.Update().SetUpsert(true)); err != nil {
+		return fmt.Errorf("failed to store contractor reputation: %w", err)
+	}
+	return nil
+}
+
+func (s *ReviewStorage) ListReviewsByContractor(ctx context.Context, contractorId string) ([]*models.Review, error) {
+	ctx, span := tracing.Start(ctx, "ReviewStorage.ListReviewsByContractor")
+	defer span.End()
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := s.db.Find(ctx, bson.M{"contractor_id": contractorId}, opts)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list reviews",
+			"error", err,
+			"contractor_id", contractorId)
+		return nil, fmt.Errorf("failed to list reviews: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var reviews []*models.Review
+	if err = cursor.All(ctx, &reviews); err != nil {
+		return nil, fmt.Errorf("failed to decode reviews: %w", err)
+	}
+
+	return reviews, nil
+}
+
+// GetReputation returns the contractor's aggregate; contractors without
+// reviews get an empty aggregate rather than an error.
+func (s *ReviewStorage) GetReputation(ctx context.Context, contractorId string) (*models.Reputation, error) {
+	ctx, span := tracing.Start(ctx, "ReviewStorage.GetReputation")
+	defer span.End()
+
+	reputation := models.Reputation{ContractorId: contractorId}
+
+	err := s.reputation.FindOne(ctx, bson.M{"contractor_id": contractorId}).Decode(&reputation)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		s.logger.ErrorContext(ctx, "failed to get contractor reputation",
+			"error", err,
+			"contractor_id", contractorId)
+		return nil, fmt.Errorf("failed to get contractor reputation: %w", err)
+	}
+
+	reputation.Compute()
+	return &reputation, nil
+}
+
+func (s *ReviewStorage) GetReputations(ctx context.Context, contractorIds []string) (map[string]*models.Reputation, error) {
+	ctx, span := tracing.Start(ctx, "ReviewStorage.GetReputations")
+	defer span.End()
+
+	cursor, err := s.reputation.Find(ctx, bson.M{"contractor_id": bson.M{"$in": contractorIds}})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list contractor reputations",
+			"error", err)
+		return nil, fmt.Errorf("failed to list contractor reputations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var found []*models.Reputation
+	if err = cursor.All(ctx, &found); err != nil {
+		return nil, fmt.Errorf("failed to decode contractor reputations: %w", err)
+	}
+
+	reputations := make(map[string]*models.Reputation, len(contractorIds))
+	for _, id := range contractorIds {
+		reputations[id] = &models.Reputation{ContractorId: id}
+	}
+	for _, reputation := range found {
+		reputations[reputation.ContractorId] = reputation
+	}
+	for _, reputation := range reputations {
+		reputation.Compute()
+	}
+
+	return reputations, nil
+}
@@ -0,0 +1,129 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SavedSearchStorage struct {
+	db     *mongo.Collection
+	logger *slog.Logger
+}
+
+func NewSavedSearchStorage(db *mongo.Database, logger *slog.Logger) *SavedSearchStorage {
+	return &SavedSearchStorage{
+		db:     db.Collection("SavedSearches"),
+		logger: logger,
+	}
+}
+
+func (s *SavedSearchStorage) CreateSavedSearch(ctx context.Context, search *models.SavedSearch) (*models.SavedSearch, error) {
+	ctx, span := tracing.Start(ctx, "SavedSearchStorage.CreateSavedSearch")
+	defer span.End()
+
+	if search.SearchId == "" {
+		search.SearchId = primitive.NewObjectID().Hex()
+	}
+	search.CreatedAt = time.Now().UTC()
+
+	_, err := s.db.InsertOne(ctx, search)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to create saved search",
+			"error", err,
+			"user_id", search.UserId)
+		return nil, fmt.Errorf("failed to create saved search: %w", err)
+	}
+
+	return search, nil
+}
+
+func (s *SavedSearchStorage) ListSavedSearches(ctx context.Context, userId string) ([]*models.SavedSearch, error) {
+	ctx, span := tracing.Start(ctx, "SavedSearchStorage.ListSavedSearches")
+	defer span.End()
+
+	return s.find(ctx, bson.M{"user_id": userId})
+}
+
+func (s *SavedSearchStorage) DeleteSavedSearch(ctx context.Context, userId string, searchId string) error {
+	ctx, span := tracing.Start(ctx, "SavedSearchStorage.DeleteSavedSearch")
+	defer span.End()
+
+	result, err := s.db.DeleteOne(ctx, bson.M{"search_id": searchId, "user_id": userId})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to delete saved search",
+			"error", err,
+			"search_id", searchId)
+		return fmt.Errorf("failed to delete saved search: %w", err)
+	}
+
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("saved search not found: %s", searchId)
+	}
+
+	return nil
+}
+
+func (s *SavedSearchStorage) FindCandidates(ctx context.Context, tender *models.Tender) ([]*models.SavedSearch, error) {
+	ctx, span := tracing.Start(ctx, "SavedSearchStorage.FindCandidates")
+	defer span.End()
+
+	filter := bson.M{
+		"$and": bson.A{
+			bson.M{"$or": bson.A{
+				bson.M{"category_id": ""},
+				bson.M{"category_id": bson.M{"$in": tender.CategoryPath}},
+			}},
+			bson.M{"min_budget": bson.M{"$lte": tender.Budget}},
+			bson.M{"$or": bson.A{
+				bson.M{"max_budget": 0},
+				bson.M{"max_budget": bson.M{"$gte": tender.Budget}},
+			}},
+		},
+	}
+	return s.find(ctx, filter)
+}
+
+func (s *SavedSearchStorage) PurgeSavedSearches(ctx context.Context, userIds []string) (int64, error) {
+	ctx, span := tracing.Start(ctx, "SavedSearchStorage.PurgeSavedSearches")
+	defer span.End()
+
+	filter := referencing(map[string][]string{"user_id": userIds})
+	if filter == nil {
+		return 0, nil
+	}
+
+	result, err := s.db.DeleteMany(ctx, filter)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to purge saved searches",
+			"error", err)
+		return 0, fmt.Errorf("failed to purge saved searches: %w", err)
+	}
+
+	return result.DeletedCount, nil
+}
+
+func (s *SavedSearchStorage) find(ctx context.Context, filter bson.M) ([]*models.SavedSearch, error) {
+	cursor, err := s.db.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list saved searches",
+			"error", err)
+		return nil, fmt.Errorf("failed to list saved searches: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var searches []*models.SavedSearch
+	if err = cursor.All(ctx, &searches); err != nil {
+		return nil, fmt.Errorf("failed to decode saved searches: %w", err)
+	}
+
+	return searches, nil
+}
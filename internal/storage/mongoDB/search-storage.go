@@ -0,0 +1,224 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const defaultSearchLimit = 20
+
+// TenderSearchStorage keeps a denormalized copy of each tender in a collection
+// with a weighted text index. Title matches weigh five times description ones.
+type TenderSearchStorage struct {
+	db       *mongo.Collection
+	language string
+	logger   *slog.Logger
+}
+
+// tenderSearchDocument is the indexed form of a tender. Language selects the
+// stemmer MongoDB uses for the document. The invited contractor list is
+// maintained separately by SetTenderAccess.
+type tenderSearchDocument struct {
+	TenderId     string    `bson:"tender_id"`
+	ClientId     string    `bson:"client_id"`
+	Visibility   string    `bson:"visibility"`
+	Title        string    `bson:"title"`
+	Description  string    `bson:"description"`
+	Status       string    `bson:"status"`
+	Budget       int       `bson:"budget"`
+	Deadline     time.Time `bson:"deadline"`
+	CategoryId   string    `bson:"category_id"`
+	CategoryPath []string  `bson:"category_path"`
+	Tags         []string  `bson:"tags"`
+	Language     string    `bson:"language"`
+}
+
+func NewTenderSearchStorage(db *mongo.Database, language string, logger *slog.Logger) *TenderSearchStorage {
+	if language == "" {
+		language = models.DefaultSearchLanguage
+	}
+	return &TenderSearchStorage{
+		db:       db.Collection("TenderSearch"),
+		language: language,
+		logger:   logger,
+	}
+}
+
+func (s *TenderSearchStorage) IndexTender(ctx context.Context, tender *models.Tender) error {
+	ctx, span := tracing.Start(ctx, "TenderSearchStorage.IndexTender")
+	defer span.End()
+
+	doc := tenderSearchDocument{
+		TenderId:     tender.TenderId,
+		ClientId:     tender.ClientId,
+		Visibility:   tender.Visibility,
+		Title:        tender.Title,
+		Description:  tender.Description,
+		Status:       tender.Status,
+		Budget:       tender.Budget,
+		Deadline:     tender.Deadline,
+		CategoryId:   tender.CategoryId,
+		CategoryPath: tender.CategoryPath,
+		Tags:         tender.Tags,
+		Language:     s.language,
+	}
+
+	_, err := s.db.UpdateOne(ctx, bson.M{"tender_id": tender.TenderId}, bson.M{"$set": doc}, options.Update().SetUpsert(true))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to index tender",
+			"error", err,
+			"tender_id", tender.TenderId)
+		return fmt.Errorf("failed to index tender: %w", err)
+	}
+
+	return nil
+}
+
+func (s *TenderSearchStorage) SetTenderAccess(ctx context.Context, id string, contractorIds []string) error {
+	ctx, span := tracing.Start(ctx, "TenderSearchStorage.SetTenderAccess")
+	defer span.End()
+
+	if contractorIds == nil {
+		contractorIds = []string{}
+	}
+	_, err := s.db.UpdateOne(ctx, bson.M{"tender_id": id}, bson.M{"$set": bson.M{"invited": contractorIds}})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to update tender search access",
+			"error", err,
+			"tender_id", id)
+		return fmt.Errorf("failed to update tender search access: %w", err)
+	}
+	return nil
+}
+
+func (s *TenderSearchStorage) RemoveTender(ctx context.Context, id string) error {
+	ctx, span := tracing.Start(ctx, "TenderSearchStorage.RemoveTender")
+	defer span.End()
+
+	if _, err := s.db.DeleteOne(ctx, bson.M{"tender_id": id}); err != nil {
+		s.logger.ErrorContext(ctx, "failed to remove tender from search index",
+			"error", err,
+			"tender_id", id)
+		return fmt.Errorf("failed to remove tender from search index: %w", err)
+	}
+	return nil
+}
+
+func (s *TenderSearchStorage) SearchTenders(ctx context.Context, query *models.TenderSearchQuery) (*models.TenderSearchResult, error) {
+	ctx, span := tracing.Start(ctx, "TenderSearchStorage.SearchTenders")
+	defer span.End()
+
+	language := query.Language
+	if language == "" {
+		language = s.language
+	}
+
+	match := bson.M{"$text": bson.M{"$search": query.Query, "$language": language}}
+	if query.Status != "" {
+		match["status"] = query.Status
+	}
+	if query.CategoryId != "" {
+		match["category_path"] = query.CategoryId
+	}
+	budget := bson.M{}
+	if query.MinBudget > 0 {
+		budget["$gte"] = query.MinBudget
+	}
+	if query.MaxBudget > 0 {
+		budget["$lte"] = query.MaxBudget
+	}
+	if len(budget) > 0 {
+		match["budget"] = budget
+	}
+	match["$or"] = bson.A{
+		bson.M{"visibility": bson.M{"$ne": models.VisibilityInviteOnly}},
+		bson.M{"invited": query.ViewerId},
+		bson.M{"client_id": query.ViewerId},
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+
+	// The last boundary is exclusive, so it must exceed any real budget.
+	boundaries := append(append([]int{}, models.BudgetBands...), int(^uint32(0)>>1))
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$addFields", Value: bson.M{"score": bson.M{"$meta": "textScore"}}}},
+		{{Key: "$facet", Value: bson.M{
+			"hits": bson.A{
+				bson.M{"$sort": bson.D{{Key: "score", Value: -1}, {Key: "deadline", Value: 1}}},
+				bson.M{"$skip": query.Offset},
+				bson.M{"$limit": limit},
+			},
+			"total":    bson.A{bson.M{"$count": "count"}},
+			"status":   bson.A{bson.M{"$sortByCount": "$status"}},
+			"category": bson.A{bson.M{"$match": bson.M{"category_id": bson.M{"$ne": ""}}}, bson.M{"$sortByCount": "$category_id"}},
+			"budget": bson.A{bson.M{"$bucket": bson.M{
+				"groupBy":    "$budget",
+				"boundaries": boundaries,
+				"default":    -1,
+				"output":     bson.M{"count": bson.M{"$sum": 1}},
+			}}},
+		}}},
+	}
+
+	cursor, err := s.db.Aggregate(ctx, pipeline)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to search tenders",
+			"error", err,
+			"query", query.Query)
+		return nil, fmt.Errorf("failed to search tenders: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var facets []struct {
+		Hits  []*models.TenderSearchHit `bson:"hits"`
+		Total []struct {
+			Count int `bson:"count"`
+		} `bson:"total"`
+		Status   []models.FacetCount `bson:"status"`
+		Category []models.FacetCount `bson:"category"`
+		Budget   []struct {
+			Lower int `bson:"_id"`
+			Count int `bson:"count"`
+		} `bson:"budget"`
+	}
+	if err := cursor.All(ctx, &facets); err != nil {
+		return nil, fmt.Errorf("failed to decode search results: %w", err)
+	}
+
+	result := &models.TenderSearchResult{Hits: []*models.TenderSearchHit{}}
+	if len(facets) == 0 {
+		return result, nil
+	}
+
+	f := facets[0]
+	result.Hits = append(result.Hits, f.Hits...)
+	if len(f.Total) > 0 {
+		result.Total = f.Total[0].Count
+	}
+	result.Facets.Status = f.Status
+	result.Facets.Category = f.Category
+	for _, band := range f.Budget {
+		if band.Lower < 0 {
+			continue
+		}
+		result.Facets.Budget = append(result.Facets.Budget, models.FacetCount{
+			Value: models.BudgetBandLabel(band.Lower),
+			Count: band.Count,
+		})
+	}
+
+	return result, nil
+}
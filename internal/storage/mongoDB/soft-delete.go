@@ -0,0 +1,64 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Soft-deleted documents carry their deletion time. Live ones have no
+// deletion time, or the zero time users have always been stored with, so
+// both conditions compare against the zero time rather than null.
+var (
+	notDeleted = bson.M{"$not": bson.M{"$gt": time.Time{}}}
+	isDeleted  = bson.M{"$gt": time.Time{}}
+)
+
+// purgeable matches documents soft-deleted at or before cutoff.
+func purgeable(cutoff time.Time) bson.M {
+	return bson.M{"$gt": time.Time{}, "$lte": cutoff}
+}
+
+// purgeableIDs returns the idField of the documents in coll soft-deleted, by
+// deletedField, at or before cutoff. ObjectIDs are returned in hex.
+func purgeableIDs(ctx context.Context, coll *mongo.Collection, idField, deletedField string, cutoff time.Time) ([]string, error) {
+	opts := options.Find().SetProjection(bson.M{idField: 1})
+	cursor, err := coll.Find(ctx, bson.M{deletedField: purgeable(cutoff)}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list purgeable %s: %w", coll.Name(), err)
+	}
+	defer cursor.Close(ctx)
+
+	var ids []string
+	for cursor.Next(ctx) {
+		switch id := cursor.Current.Lookup(idField); id.Type {
+		case bson.TypeObjectID:
+			ids = append(ids, id.ObjectID().Hex())
+		case bson.TypeString:
+			ids = append(ids, id.StringValue())
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list purgeable %s: %w", coll.Name(), err)
+	}
+	return ids, nil
+}
+
+// referencing matches documents whose field is one of the listed ids, for
+// each non-empty list; it returns nil when every list is empty.
+func referencing(refs map[string][]string) bson.M {
+	var or bson.A
+	for field, ids := range refs {
+		if len(ids) > 0 {
+			or = append(or, bson.M{field: bson.M{"$in": ids}})
+		}
+	}
+	if len(or) == 0 {
+		return nil
+	}
+	return bson.M{"$or": or}
+}
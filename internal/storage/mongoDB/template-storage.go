@@ -0,0 +1,144 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/zohirovs/internal/models"
+	"github.com/zohirovs/internal/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TemplateStorage struct {
+	db     *mongo.Collection
+	logger *slog.Logger
+}
+
+func NewTemplateStorage(db *mongo.Database, logger *slog.Logger) *TemplateStorage {
+	return &TemplateStorage{
+		db:     db.Collection("TenderTemplates"),
+		logger: logger,
+	}
+}
+
+func (s *TemplateStorage) CreateTemplate(ctx context.Context, template *models.TenderTemplate) (*models.TenderTemplate, error) {
+	ctx, span := tracing.Start(ctx, "TemplateStorage.CreateTemplate")
+	defer span.End()
+
+	if template.TemplateId == "" {
+		template.TemplateId = primitive.NewObjectID().Hex()
+	}
+	now := time.Now().UTC()
+	template.CreatedAt = now
+	template.UpdatedAt = now
+
+	_, err := s.db.InsertOne(ctx, template)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to create template",
+			"error", err,
+			"client_id", template.ClientId)
+		return nil, fmt.Errorf("failed to create template: %w", err)
+	}
+
+	return template, nil
+}
+
+func (s *TemplateStorage) GetTemplate(ctx context.Context, clientId string, id string) (*models.TenderTemplate, error) {
+	ctx, span := tracing.Start(ctx, "TemplateStorage.GetTemplate")
+	defer span.End()
+
+	var template models.TenderTemplate
+	err := s.db.FindOne(ctx, bson.M{"template_id": id, "client_id": clientId}).Decode(&template)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("template not found: %s", id)
+		}
+		s.logger.ErrorContext(ctx, "failed to get template",
+			"error", err,
+			"template_id", id)
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+
+	return &template, nil
+}
+
+func (s *TemplateStorage) ListTemplates(ctx context.Context, clientId string) ([]*models.TenderTemplate, error) {
+	ctx, span := tracing.Start(ctx, "TemplateStorage.ListTemplates")
+	defer span.End()
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := s.db.Find(ctx, bson.M{"client_id": clientId}, opts)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list templates",
+			"error", err,
+			"client_id", clientId)
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	templates := []*models.TenderTemplate{}
+	if err = cursor.All(ctx, &templates); err != nil {
+		return nil, fmt.Errorf("failed to decode templates: %w", err)
+	}
+
+	return templates, nil
+}
+
+func (s *TemplateStorage) UpdateTemplate(ctx context.Context, template *models.TenderTemplate) (*models.TenderTemplate, error) {
+	ctx, span := tracing.Start(ctx, "TemplateStorage.UpdateTemplate")
+	defer span.End()
+
+	update := bson.M{"$set": bson.M{
+		"name":                    template.Name,
+		"title":                   template.Title,
+		"description":             template.Description,
+		"budget":                  template.Budget,
+		"category_id":             template.CategoryId,
+		"tags":                    template.Tags,
+		"line_items":              template.LineItems,
+		"evaluation_criteria":     template.EvaluationCriteria,
+		"requires_verified":       template.RequiresVerified,
+		"required_certifications": template.RequiredCertifications,
+		"updated_at":              time.Now().UTC(),
+	}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var result models.TenderTemplate
+	err := s.db.FindOneAndUpdate(ctx, bson.M{"template_id": template.TemplateId, "client_id": template.ClientId}, update, opts).Decode(&result)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("template not found: %s", template.TemplateId)
+		}
+		s.logger.ErrorContext(ctx, "failed to update template",
+			"error", err,
+			"template_id", template.TemplateId)
+		return nil, fmt.Errorf("failed to update template: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (s *TemplateStorage) DeleteTemplate(ctx context.Context, clientId string, id string) error {
+	ctx, span := tracing.Start(ctx, "TemplateStorage.DeleteTemplate")
+	defer span.End()
+
+	result, err := s.db.DeleteOne(ctx, bson.M{"template_id": id, "client_id": clientId})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to delete template",
+			"error", err,
+			"template_id", id)
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("template not found: %s", id)
+	}
+
+	return nil
+}
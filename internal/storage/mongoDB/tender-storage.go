@@ -17,6 +17,7 @@ import (
 
 	"github.com/zohirovs/internal/models"
 	"github.com/zohirovs/internal/storage/redis"
+	"github.com/zohirovs/internal/tracing"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -38,24 +39,54 @@ func NewTenderStorage(db *mongo.Database, logger *slog.Logger, cache *redis.Tend
 }
 
 func (s *TenderStorage) CreateTender(ctx context.Context, tender *models.Tender) (*models.Tender, error) {
+	ctx, span := tracing.Start(ctx, "TenderStorage.CreateTender")
+	defer span.End()
+
 	if tender.TenderId == "" {
 		tender.TenderId = primitive.NewObjectID().Hex()
 	}
 
-	deadline, err := time.Parse(time.RFC3339, tender.Deadline)
-	if err != nil {
-		return nil, fmt.Errorf("invalid deadline format: %w", err)
+	if _, err := models.LoadTimeZone(tender.TimeZone); err != nil {
+		return nil, err
+	}
+	if tender.TimeZone == "" {
+		tender.TimeZone = models.DefaultTimeZone
+	}
+	if tender.Visibility == "" {
+		tender.Visibility = string(models.VisibilityPublic)
+	}
+	for i := range tender.LineItems {
+		tender.LineItems[i].ItemId = primitive.NewObjectID().Hex()
+	}
+	for i := range tender.Lots {
+		lot := &tender.Lots[i]
+		lot.LotId = primitive.NewObjectID().Hex()
+		lot.Status = models.LotOpen
+		for j := range lot.LineItems {
+			lot.LineItems[j].ItemId = primitive.NewObjectID().Hex()
+			lot.LineItems[j].LotId = lot.LotId
+		}
 	}
 
-	if deadline.Before(time.Now()) {
+	now := time.Now().UTC()
+	if tender.Deadline.Before(now) {
 		return nil, errors.New("deadline must be in the future")
 	}
 
-	tender.Status = string(models.OPEN)
+	// Tenders are published immediately unless created as drafts.
+	tender.Deadline = tender.Deadline.UTC()
+	tender.CreatedAt = now
+	tender.UpdatedAt = now
+	if tender.Status == string(models.DRAFT) {
+		tender.PublishedAt = nil
+	} else {
+		tender.Status = string(models.OPEN)
+		tender.PublishedAt = &now
+	}
 
-	_, err = s.db.InsertOne(ctx, tender)
+	_, err := s.db.InsertOne(ctx, tender)
 	if err != nil {
-		s.logger.Error("failed to create tender",
+		s.logger.ErrorContext(ctx, "failed to create tender",
 			"error", err,
 			"tenderid", tender.TenderId)
 		return nil, fmt.Errorf("failed to create tender: %w", err)
@@ -65,14 +96,17 @@ func (s *TenderStorage) CreateTender(ctx context.Context, tender *models.Tender)
 }
 
 func (s *TenderStorage) GetTender(ctx context.Context, id string) (*models.Tender, error) {
+	ctx, span := tracing.Start(ctx, "TenderStorage.GetTender")
+	defer span.End()
+
 	var tender models.Tender
 
-	err := s.db.FindOne(ctx, bson.M{"tenderid": id}).Decode(&tender)
+	err := s.db.FindOne(ctx, bson.M{"tenderid": id, "deletedat": notDeleted}).Decode(&tender)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, fmt.Errorf("tender not found: %s", id)
 		}
-		s.logger.Error("failed to get tender",
+		s.logger.ErrorContext(ctx, "failed to get tender",
 			"error", err,
 			"tenderid", id)
 		return nil, fmt.Errorf("failed to get tender: %w", err)
@@ -82,15 +116,18 @@ func (s *TenderStorage) GetTender(ctx context.Context, id string) (*models.Tende
 }
 
 func (s *TenderStorage) UpdateTender(ctx context.Context, updatedTender *models.Tender) (*models.Tender, error) {
-	if updatedTender.Deadline != "" {
-		deadline, err := time.Parse(time.RFC3339, updatedTender.Deadline)
-		if err != nil {
-			return nil, fmt.Errorf("invalid deadline format: %w", err)
-		}
+	ctx, span := tracing.Start(ctx, "TenderStorage.UpdateTender")
+	defer span.End()
 
-		if updatedTender.Status == "open" && deadline.Before(time.Now()) {
-			return nil, errors.New("deadline must be in the future for open tenders")
-		}
+	if updatedTender.Status == string(models.OPEN) && updatedTender.Deadline.Before(time.Now()) {
+		return nil, errors.New("deadline must be in the future for open tenders")
+	}
+
+	if _, err := models.LoadTimeZone(updatedTender.TimeZone); err != nil {
+		return nil, err
+	}
+	if updatedTender.TimeZone == "" {
+		updatedTender.TimeZone = models.DefaultTimeZone
 	}
 
 	update := bson.M{
@@ -100,8 +137,18 @@ func (s *TenderStorage) UpdateTender(ctx context.Context, updatedTender *models.
 			"description":    updatedTender.Description,
 			"budget":         updatedTender.Budget,
 			"status":         updatedTender.Status,
-			"deadline":       updatedTender.Deadline,
+			"deadline":       updatedTender.Deadline.UTC(),
+			"timezone":       updatedTender.TimeZone,
 			"attachment_url": updatedTender.AttachmentUrl,
+			"updatedat":      time.Now().UTC(),
+			"categoryid":     updatedTender.CategoryId,
+			"categorypath":   updatedTender.CategoryPath,
+			"tags":           updatedTender.Tags,
+
+			"requiresverified":       updatedTender.RequiresVerified,
+			"requiredcertifications": updatedTender.RequiredCertifications,
+			"visibility":             updatedTender.Visibility,
+			"evaluationcriteria":     updatedTender.EvaluationCriteria,
 		},
 	}
 
@@ -110,7 +157,7 @@ func (s *TenderStorage) UpdateTender(ctx context.Context, updatedTender *models.
 	var result models.Tender
 	err := s.db.FindOneAndUpdate(
 		ctx,
-		bson.M{"tenderid": updatedTender.TenderId},
+		bson.M{"tenderid": updatedTender.TenderId, "deletedat": notDeleted},
 		update,
 		opts,
 	).Decode(&result)
@@ -119,7 +166,7 @@ func (s *TenderStorage) UpdateTender(ctx context.Context, updatedTender *models.
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, fmt.Errorf("tender not found: %s", updatedTender.TenderId)
 		}
-		s.logger.Error("failed to update tender",
+		s.logger.ErrorContext(ctx, "failed to update tender",
 			"error", err,
 			"tenderid", updatedTender.TenderId)
 		return nil, fmt.Errorf("failed to update tender: %w", err)
@@ -128,27 +175,109 @@ func (s *TenderStorage) UpdateTender(ctx context.Context, updatedTender *models.
 	return &result, nil
 }
 
-// DeleteTender deletes a tender from the database by its ID. If the tender is not found, it returns an error.
-func (s *TenderStorage) DeleteTender(ctx context.Context, id string) error {
-	result, err := s.db.DeleteOne(ctx, bson.M{"tenderid": id})
+// DeleteTender soft-deletes a tender by stamping it with the deletion time.
+// If the tender is not found, it returns an error.
+func (s *TenderStorage) DeleteTender(ctx context.Context, id string, at time.Time) error {
+	ctx, span := tracing.Start(ctx, "TenderStorage.DeleteTender")
+	defer span.End()
+
+	update := bson.M{"$set": bson.M{"deletedat": at, "updatedat": at}}
+
+	result, err := s.db.UpdateOne(ctx, bson.M{"tenderid": id, "deletedat": notDeleted}, update)
 	if err != nil {
-		s.logger.Error("failed to delete tender",
+		s.logger.ErrorContext(ctx, "failed to delete tender",
 			"error", err,
 			"tenderid", id)
 		return fmt.Errorf("failed to delete tender: %w", err)
 	}
 
-	if result.DeletedCount == 0 {
+	if result.MatchedCount == 0 {
 		return fmt.Errorf("tender not found: %s", id)
 	}
 
 	return nil
 }
 
+func (s *TenderStorage) GetDeletedTender(ctx context.Context, id string) (*models.Tender, error) {
+	ctx, span := tracing.Start(ctx, "TenderStorage.GetDeletedTender")
+	defer span.End()
+
+	var tender models.Tender
+
+	err := s.db.FindOne(ctx, bson.M{"tenderid": id, "deletedat": isDeleted}).Decode(&tender)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("deleted tender not found: %s", id)
+		}
+		s.logger.ErrorContext(ctx, "failed to get deleted tender",
+			"error", err,
+			"tenderid", id)
+		return nil, fmt.Errorf("failed to get deleted tender: %w", err)
+	}
+
+	return &tender, nil
+}
+
+func (s *TenderStorage) RestoreTender(ctx context.Context, id string) error {
+	ctx, span := tracing.Start(ctx, "TenderStorage.RestoreTender")
+	defer span.End()
+
+	update := bson.M{
+		"$set":   bson.M{"updatedat": time.Now().UTC()},
+		"$unset": bson.M{"deletedat": ""},
+	}
+
+	result, err := s.db.UpdateOne(ctx, bson.M{"tenderid": id, "deletedat": isDeleted}, update)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to restore tender",
+			"error", err,
+			"tenderid", id)
+		return fmt.Errorf("failed to restore tender: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("deleted tender not found: %s", id)
+	}
+
+	return nil
+}
+
+func (s *TenderStorage) ListPurgeableTenders(ctx context.Context, cutoff time.Time) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "TenderStorage.ListPurgeableTenders")
+	defer span.End()
+
+	return purgeableIDs(ctx, s.db, "tenderid", "deletedat", cutoff)
+}
+
+// PurgeDeletedTenders permanently removes tenders soft-deleted at or before
+// cutoff and returns how many were removed.
+func (s *TenderStorage) PurgeDeletedTenders(ctx context.Context, cutoff time.Time) (int64, error) {
+	ctx, span := tracing.Start(ctx, "TenderStorage.PurgeDeletedTenders")
+	defer span.End()
+
+	result, err := s.db.DeleteMany(ctx, bson.M{"deletedat": purgeable(cutoff)})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to purge deleted tenders",
+			"error", err)
+		return 0, fmt.Errorf("failed to purge deleted tenders: %w", err)
+	}
+
+	return result.DeletedCount, nil
+}
+
+// ListTenders lists tenders matching filter, leaving out soft-deleted ones.
 func (s *TenderStorage) ListTenders(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]*models.Tender, error) {
-	cursor, err := s.db.Find(ctx, filter, opts)
+	ctx, span := tracing.Start(ctx, "TenderStorage.ListTenders")
+	defer span.End()
+
+	live := bson.M{"deletedat": notDeleted}
+	for k, v := range filter {
+		live[k] = v
+	}
+
+	cursor, err := s.db.Find(ctx, live, opts)
 	if err != nil {
-		s.logger.Error("failed to list tenders",
+		s.logger.ErrorContext(ctx, "failed to list tenders",
 			"error", err)
 		return nil, fmt.Errorf("failed to list tenders: %w", err)
 	}
@@ -163,12 +292,26 @@ func (s *TenderStorage) ListTenders(ctx context.Context, filter bson.M, opts *op
 }
 
 func (s *TenderStorage) ListTendersByClient(ctx context.Context, clientID string) ([]*models.Tender, error) {
+	ctx, span := tracing.Start(ctx, "TenderStorage.ListTendersByClient")
+	defer span.End()
+
 	filter := bson.M{"clientid": clientID}
 	return s.ListTenders(ctx, filter, nil)
 }
 
+func (s *TenderStorage) ListTendersByOrganization(ctx context.Context, orgID string) ([]*models.Tender, error) {
+	ctx, span := tracing.Start(ctx, "TenderStorage.ListTendersByOrganization")
+	defer span.End()
+
+	filter := bson.M{"organizationid": orgID}
+	return s.ListTenders(ctx, filter, nil)
+}
+
 func (s *TenderStorage) ListOpenTenders(ctx context.Context) ([]*models.Tender, error) {
-	now := time.Now().Format(time.RFC3339)
+	ctx, span := tracing.Start(ctx, "TenderStorage.ListOpenTenders")
+	defer span.End()
+
+	now := time.Now().UTC()
 	filter := bson.M{
 		"status":   "OPEN",
 		"deadline": bson.M{"$gt": now},
@@ -176,16 +319,30 @@ func (s *TenderStorage) ListOpenTenders(ctx context.Context) ([]*models.Tender,
 	return s.ListTenders(ctx, filter, nil)
 }
 
+// UpdateStatus changes the tender status and maintains the lifecycle timestamps:
+// reopening a tender republishes it, closing or awarding it records ClosedAt.
 func (s *TenderStorage) UpdateStatus(ctx context.Context, id string, status models.Status) error {
-	update := bson.M{
-		"$set": bson.M{
-			"status": status,
-		},
+	ctx, span := tracing.Start(ctx, "TenderStorage.UpdateStatus")
+	defer span.End()
+
+	now := time.Now().UTC()
+	set := bson.M{
+		"status":    status,
+		"updatedat": now,
+	}
+	update := bson.M{"$set": set}
+
+	switch status {
+	case models.OPEN:
+		set["publishedat"] = now
+		update["$unset"] = bson.M{"closedat": ""}
+	case models.CLOSED, models.AWARDED:
+		set["closedat"] = now
 	}
 
-	result, err := s.db.UpdateOne(ctx, bson.M{"tenderid": id}, update)
+	result, err := s.db.UpdateOne(ctx, bson.M{"tenderid": id, "deletedat": notDeleted}, update)
 	if err != nil {
-		s.logger.Error("failed to update tender status",
+		s.logger.ErrorContext(ctx, "failed to update tender status",
 			"error", err,
 			"tenderid", id,
 			"status", status)
@@ -199,33 +356,105 @@ func (s *TenderStorage) UpdateStatus(ctx context.Context, id string, status mode
 	return nil
 }
 
-func (s *TenderStorage) CreateIndexes(ctx context.Context) error {
-	indexes := []mongo.IndexModel{
-		{
-			Keys: bson.D{
-				{Key: "tenderid", Value: 1},
-			},
-			Options: options.Index().SetUnique(true),
-		},
-		{
-			Keys: bson.D{
-				{Key: "clientid", Value: 1},
-			},
-		},
-		{
-			Keys: bson.D{
-				{Key: "status", Value: 1},
-				{Key: "deadline", Value: 1},
-			},
-		},
+// ResolveLot awards or cancels an open lot. The status check is part of the
+// update so two concurrent decisions cannot both succeed.
+func (s *TenderStorage) ResolveLot(ctx context.Context, tenderId string, lot *models.Lot) error {
+	ctx, span := tracing.Start(ctx, "TenderStorage.ResolveLot")
+	defer span.End()
+
+	now := time.Now().UTC()
+	filter := bson.M{
+		"tenderid":  tenderId,
+		"deletedat": notDeleted,
+		"lots":      bson.M{"$elemMatch": bson.M{"lotid": lot.LotId, "status": models.LotOpen}},
+	}
+	update := bson.M{"$set": bson.M{
+		"lots.$.status":              lot.Status,
+		"lots.$.awardedbidid":        lot.AwardedBidId,
+		"lots.$.awardedcontractorid": lot.AwardedContractorId,
+		"lots.$.resolvedat":          now,
+		"updatedat":                  now,
+	}}
+
+	result, err := s.db.UpdateOne(ctx, filter, update)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to resolve lot",
+			"error", err,
+			"tenderid", tenderId,
+			"lot_id", lot.LotId)
+		return fmt.Errorf("failed to resolve lot: %w", err)
 	}
 
-	_, err := s.db.Indexes().CreateMany(ctx, indexes)
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("open lot not found: %s", lot.LotId)
+	}
+
+	return nil
+}
+
+// ConvertStringDeadlines rewrites tenders whose deadline was stored as an RFC3339
+// string into BSON dates, and backfills the time zone and creation timestamps.
+// It returns the number of converted tenders.
+func (s *TenderStorage) ConvertStringDeadlines(ctx context.Context) (int, error) {
+	ctx, span := tracing.Start(ctx, "TenderStorage.ConvertStringDeadlines")
+	defer span.End()
+
+	cursor, err := s.db.Find(ctx, bson.M{"deadline": bson.M{"$type": "string"}})
 	if err != nil {
-		s.logger.Error("failed to create indexes",
+		s.logger.ErrorContext(ctx, "failed to find tenders with string deadlines",
 			"error", err)
-		return fmt.Errorf("failed to create indexes: %w", err)
+		return 0, fmt.Errorf("failed to find tenders: %w", err)
 	}
+	defer cursor.Close(ctx)
 
-	return nil
+	converted := 0
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID        primitive.ObjectID `bson:"_id"`
+			TenderId  string             `bson:"tenderid"`
+			Deadline  string             `bson:"deadline"`
+			TimeZone  string             `bson:"timezone"`
+			CreatedAt time.Time          `bson:"createdat"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return converted, fmt.Errorf("failed to decode tender: %w", err)
+		}
+
+		deadline, err := models.ParseDeadline(doc.Deadline, doc.TimeZone)
+		if err != nil {
+			s.logger.WarnContext(ctx, "skipping tender with unparsable deadline",
+				"error", err,
+				"tenderid", doc.TenderId,
+				"deadline", doc.Deadline)
+			continue
+		}
+
+		set := bson.M{"deadline": deadline}
+		if doc.TimeZone == "" {
+			set["timezone"] = models.DefaultTimeZone
+		}
+		if doc.CreatedAt.IsZero() {
+			createdAt := doc.ID.Timestamp().UTC()
+			if oid, err := primitive.ObjectIDFromHex(doc.TenderId); err == nil {
+				createdAt = oid.Timestamp().UTC()
+			}
+			set["createdat"] = createdAt
+			set["updatedat"] = createdAt
+			set["publishedat"] = createdAt
+		}
+
+		if _, err := s.db.UpdateByID(ctx, doc.ID, bson.M{"$set": set}); err != nil {
+			s.logger.ErrorContext(ctx, "failed to convert tender deadline",
+				"error", err,
+				"tenderid", doc.TenderId)
+			return converted, fmt.Errorf("failed to convert tender %s: %w", doc.TenderId, err)
+		}
+		converted++
+	}
+
+	if err := cursor.Err(); err != nil {
+		return converted, fmt.Errorf("failed to iterate tenders: %w", err)
+	}
+
+	return converted, nil
 }
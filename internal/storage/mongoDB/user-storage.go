@@ -13,6 +13,7 @@ import (
 	"github.com/zohirovs/internal/models"
 	"github.com/zohirovs/internal/repos"
 	"github.com/zohirovs/internal/storage/redis"
+	"github.com/zohirovs/internal/tracing"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -20,6 +21,18 @@ import (
 	"gopkg.in/gomail.v2"
 )
 
+// userDocument is a stored user; its ID is the document's _id.
+type userDocument struct {
+	ObjectID    primitive.ObjectID `bson:"_id"`
+	models.User `bson:",inline"`
+}
+
+func (d *userDocument) user() *models.User {
+	user := d.User
+	user.ID = d.ObjectID.Hex()
+	return &user
+}
+
 type UserStorage struct {
 	db        *mongo.Collection
 	logger    *slog.Logger
@@ -27,7 +40,7 @@ type UserStorage struct {
 	cfg       *config.Config
 }
 
-func NewUserStorage(db *mongo.Database, cfg *config.Config, logger *slog.Logger, cache *redis.UserCaching) repos.UserRepo {
+func NewUserStorage(db *mongo.Database, cfg *config.Config, logger *slog.Logger, cache *redis.UserCaching) *UserStorage {
 	return &UserStorage{
 		db:        db.Collection("Users"),
 		logger:    logger,
@@ -38,7 +51,10 @@ func NewUserStorage(db *mongo.Database, cfg *config.Config, logger *slog.Logger,
 
 // 1
 func (u *UserStorage) RegisterUser(ctx context.Context, user *models.User) (string, error) {
-	u.logger.Info("starting user registration", "email", user.Email, "username", user.Username)
+	ctx, span := tracing.Start(ctx, "UserStorage.RegisterUser")
+	defer span.End()
+
+	u.logger.InfoContext(ctx, "starting user registration", "email", user.Email, "username", user.Username)
 
 	// Foydalanuvchini yaratish vaqtini belgilash
 	user.CreatedAt = time.Now()
@@ -46,7 +62,7 @@ func (u *UserStorage) RegisterUser(ctx context.Context, user *models.User) (stri
 	// Parolni hash qilish
 	hashedPassword, err := u.hashPassword(user.Password)
 	if err != nil {
-		u.logger.Error("failed to hash password", "error", err)
+		u.logger.ErrorContext(ctx, "failed to hash password", "error", err)
 		return "", fmt.Errorf("failed to hash password: %w", err)
 	}
 	user.Password = string(hashedPassword)
@@ -54,20 +70,25 @@ func (u *UserStorage) RegisterUser(ctx context.Context, user *models.User) (stri
 	// Foydalanuvchini MongoDB'ga yozish
 	result, err := u.db.InsertOne(ctx, user)
 	if err != nil {
-		u.logger.Error("failed to insert user", "error", err)
+		if mongo.IsDuplicateKeyError(err) {
+			return "", repos.ErrDuplicateUser
+		}
+		u.logger.ErrorContext(ctx, "failed to insert user", "error", err)
 		return "", err
 	}
 
 	// Yozilgan ID'ni olish
 	insertedID, ok := result.InsertedID.(primitive.ObjectID)
 	if !ok {
-		u.logger.Error("failed to convert inserted ID to ObjectID")
+		u.logger.ErrorContext(ctx, "failed to convert inserted ID to ObjectID")
 		return "", fmt.Errorf("failed to convert inserted ID to ObjectID")
 	}
 
+	user.ID = insertedID.Hex()
+
 	// Foydalanuvchini cache'ga saqlash
 	if err := u.userCache.SetUser(ctx, user); err != nil {
-		u.logger.Warn("failed to cache user data", "error", err)
+		u.logger.WarnContext(ctx, "failed to cache user data", "error", err)
 		// Bu yerda xatoni qaytarmaymiz, chunki foydalanuvchi bazaga muvaffaqiyatli saqlandi
 	}
 
@@ -81,179 +102,218 @@ func (u *UserStorage) RegisterUser(ctx context.Context, user *models.User) (stri
 
 	token, err := jwttokens.GenerateAccessToken(u.cfg.JWT.SecretKey, &tokenClaims)
 	if err != nil {
-		u.logger.Error("failed to generate access token", "error", err)
+		u.logger.ErrorContext(ctx, "failed to generate access token", "error", err)
 		return "", err
 	}
 
-	u.logger.Info("user registration completed successfully", "userID", insertedID.Hex())
+	u.logger.InfoContext(ctx, "user registration completed successfully", "userID", insertedID.Hex())
 	return token, nil
 }
 
 // 2
 func (u *UserStorage) GetUserByUserID(ctx context.Context, userID string) (*models.User, error) {
-	u.logger.Info("fetching user by ID", "userID", userID)
+	ctx, span := tracing.Start(ctx, "UserStorage.GetUserByUserID")
+	defer span.End()
+
+	u.logger.DebugContext(ctx, "fetching user by ID", "userID", userID)
 
 	// First try to get user from cache
 	if cachedUser, err := u.userCache.GetUserByUserID(ctx, userID); err == nil {
-		u.logger.Debug("user found in cache", "userID", userID)
+		u.logger.DebugContext(ctx, "user found in cache", "userID", userID)
 		return cachedUser, nil
 	}
 
 	// Convert string ID to ObjectID
 	objectID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
-		u.logger.Error("failed to convert userID to ObjectID", "error", err)
+		u.logger.ErrorContext(ctx, "failed to convert userID to ObjectID", "error", err)
 		return nil, fmt.Errorf("invalid user ID format: %w", err)
 	}
 
 	// Find user in MongoDB
-	var user models.User
-	err = u.db.FindOne(ctx, bson.M{"_id": objectID}).Decode(&user)
+	var doc userDocument
+	err = u.db.FindOne(ctx, bson.M{"_id": objectID, "deletedat": notDeleted}).Decode(&doc)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			u.logger.Warn("user not found", "userID", userID)
+			u.logger.WarnContext(ctx, "user not found", "userID", userID)
 			return nil, fmt.Errorf("user not found")
 		}
-		u.logger.Error("failed to fetch user from database", "error", err)
+		u.logger.ErrorContext(ctx, "failed to fetch user from database", "error", err)
 		return nil, fmt.Errorf("failed to fetch user: %w", err)
 	}
 
+	user := doc.user()
+
 	// Cache the user data for future requests
-	if err := u.userCache.SetUser(ctx, &user); err != nil {
-		u.logger.Warn("failed to cache user data", "error", err)
+	if err := u.userCache.SetUser(ctx, user); err != nil {
+		u.logger.WarnContext(ctx, "failed to cache user data", "error", err)
 		// Don't return error here as we still have the user data
 	}
 
-	u.logger.Info("successfully fetched user", "userID", userID)
-	return &user, nil
+	u.logger.DebugContext(ctx, "successfully fetched user", "userID", userID)
+	return user, nil
 }
 
 // 3
 func (u *UserStorage) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
-	u.logger.Info("fetching user by email", "email", email)
+	ctx, span := tracing.Start(ctx, "UserStorage.GetUserByEmail")
+	defer span.End()
+
+	u.logger.DebugContext(ctx, "fetching user by email", "email", email)
 
 	// First try to get user from cache
 	if cachedUser, err := u.userCache.GetUserByEmail(ctx, email); err == nil {
-		u.logger.Debug("user found in cache", "email", email)
+		u.logger.DebugContext(ctx, "user found in cache", "email", email)
 		return cachedUser, nil
 	}
 
 	// Find user in MongoDB
-	var user models.User
-	err := u.db.FindOne(ctx, bson.M{"email": email}).Decode(&user)
+	var doc userDocument
+	err := u.db.FindOne(ctx, bson.M{"email": email, "deletedat": notDeleted}).Decode(&doc)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			u.logger.Warn("user not found", "email", email)
+			u.logger.WarnContext(ctx, "user not found", "email", email)
 			return nil, fmt.Errorf("user not found")
 		}
-		u.logger.Error("failed to fetch user from database", "error", err)
+		u.logger.ErrorContext(ctx, "failed to fetch user from database", "error", err)
 		return nil, fmt.Errorf("failed to fetch user: %w", err)
 	}
 
+	user := doc.user()
+
 	// Cache the user data for future requests
-	if err := u.userCache.SetUser(ctx, &user); err != nil {
-		u.logger.Warn("failed to cache user data", "error", err)
+	if err := u.userCache.SetUser(ctx, user); err != nil {
+		u.logger.WarnContext(ctx, "failed to cache user data", "error", err)
 		// Don't return error here as we still have the user data
 	}
 
-	u.logger.Info("successfully fetched user", "email", email)
-	return &user, nil
+	u.logger.DebugContext(ctx, "successfully fetched user", "email", email)
+	return user, nil
 }
 
 // 3.1
 func (u *UserStorage) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
-	u.logger.Info("fetching user by username", "username", username)
+	ctx, span := tracing.Start(ctx, "UserStorage.GetUserByUsername")
+	defer span.End()
+
+	u.logger.DebugContext(ctx, "fetching user by username", "username", username)
 
 	// First try to get user from cache
 	if cachedUser, err := u.userCache.GetUserByUsername(ctx, username); err == nil {
-		u.logger.Debug("user found in cache", "username", username)
+		u.logger.DebugContext(ctx, "user found in cache", "username", username)
 		return cachedUser, nil
 	}
 
 	// Find user in MongoDB
-	var user models.User
-	err := u.db.FindOne(ctx, bson.M{"username": username}).Decode(&user)
+	var doc userDocument
+	err := u.db.FindOne(ctx, bson.M{"username": username, "deletedat": notDeleted}).Decode(&doc)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			u.logger.Warn("user not found", "username", username)
+			u.logger.WarnContext(ctx, "user not found", "username", username)
 			return nil, fmt.Errorf("user not found")
 		}
-		u.logger.Error("failed to fetch user from database", "error", err)
+		u.logger.ErrorContext(ctx, "failed to fetch user from database", "error", err)
 		return nil, fmt.Errorf("failed to fetch user: %w", err)
 	}
 
+	user := doc.user()
+
 	// Cache the user data for future requests
-	if err := u.userCache.SetUser(ctx, &user); err != nil {
-		u.logger.Warn("failed to cache user data", "error", err)
+	if err := u.userCache.SetUser(ctx, user); err != nil {
+		u.logger.WarnContext(ctx, "failed to cache user data", "error", err)
 		// Don't return error here as we still have the user data
 	}
 
-	u.logger.Info("successfully fetched user", "username", username)
-	return &user, nil
+	u.logger.DebugContext(ctx, "successfully fetched user", "username", username)
+	return user, nil
+}
+
+// GetCredentials returns a user with its password hash. It always reads the
+// database, since the cache never holds password hashes.
+func (u *UserStorage) GetCredentials(ctx context.Context, username string) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "UserStorage.GetCredentials")
+	defer span.End()
+
+	var doc userDocument
+	err := u.db.FindOne(ctx, bson.M{"username": username, "deletedat": notDeleted}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			u.logger.WarnContext(ctx, "user not found", "username", username)
+			return nil, fmt.Errorf("user not found")
+		}
+		u.logger.ErrorContext(ctx, "failed to fetch user credentials", "error", err)
+		return nil, fmt.Errorf("failed to fetch user: %w", err)
+	}
+
+	return doc.user(), nil
 }
 
 // 4
 func (u *UserStorage) ChangeUserRole(ctx context.Context, userID string, role string) error {
-	u.logger.Info("changing user role", "userID", userID, "newRole", role)
+	ctx, span := tracing.Start(ctx, "UserStorage.ChangeUserRole")
+	defer span.End()
+
+	u.logger.InfoContext(ctx, "changing user role", "userID", userID, "newRole", role)
 
 	// Convert string ID to ObjectID
 	objectID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
-		u.logger.Error("failed to convert userID to ObjectID", "error", err)
+		u.logger.ErrorContext(ctx, "failed to convert userID to ObjectID", "error", err)
 		return fmt.Errorf("invalid user ID format: %w", err)
 	}
 
 	// Start a MongoDB transaction
 	session, err := u.db.Database().Client().StartSession()
 	if err != nil {
-		u.logger.Error("failed to start session", "error", err)
+		u.logger.ErrorContext(ctx, "failed to start session", "error", err)
 		return fmt.Errorf("failed to start session: %w", err)
 	}
 	defer session.EndSession(ctx)
 
 	// Execute transaction
 	err = mongo.WithSession(ctx, session, func(sc mongo.SessionContext) error {
-		u.logger.Debug("starting MongoDB transaction")
+		u.logger.DebugContext(ctx, "starting MongoDB transaction")
 		if err := session.StartTransaction(); err != nil {
 			return fmt.Errorf("failed to start transaction: %w", err)
 		}
 
 		// Update user role in MongoDB
-		filter := bson.M{"_id": objectID}
+		filter := bson.M{"_id": objectID, "deletedat": notDeleted}
 		update := bson.M{"$set": bson.M{"role": role}}
 		result, err := u.db.UpdateOne(sc, filter, update)
 		if err != nil {
 			session.AbortTransaction(sc)
-			u.logger.Error("failed to update user role", "error", err)
+			u.logger.ErrorContext(ctx, "failed to update user role", "error", err)
 			return fmt.Errorf("failed to update user role: %w", err)
 		}
 
 		if result.MatchedCount == 0 {
 			session.AbortTransaction(sc)
-			u.logger.Warn("user not found", "userID", userID)
+			u.logger.WarnContext(ctx, "user not found", "userID", userID)
 			return fmt.Errorf("user not found")
 		}
 
 		// Get updated user data
-		var updatedUser models.User
-		err = u.db.FindOne(sc, filter).Decode(&updatedUser)
+		var updated userDocument
+		err = u.db.FindOne(sc, filter).Decode(&updated)
 		if err != nil {
 			session.AbortTransaction(sc)
-			u.logger.Error("failed to fetch updated user", "error", err)
+			u.logger.ErrorContext(ctx, "failed to fetch updated user", "error", err)
 			return fmt.Errorf("failed to fetch updated user: %w", err)
 		}
 
 		// Commit the transaction
 		if err = session.CommitTransaction(sc); err != nil {
-			u.logger.Error("failed to commit transaction", "error", err)
+			u.logger.ErrorContext(ctx, "failed to commit transaction", "error", err)
 			return fmt.Errorf("failed to commit transaction: %w", err)
 		}
 
-		u.logger.Debug("successfully committed transaction")
+		u.logger.DebugContext(ctx, "successfully committed transaction")
 
-		// Update cache only after successful MongoDB transaction
-		if err := u.userCache.SetUser(ctx, &updatedUser); err != nil {
-			u.logger.Warn("failed to update user cache", "error", err)
+		// Invalidate only after successful MongoDB transaction; the next
+		// read caches the new role
+		if err := u.userCache.DeleteUser(ctx, updated.user()); err != nil {
+			u.logger.WarnContext(ctx, "failed to invalidate user cache", "error", err)
 			// Don't return error here as the DB update was successful
 		}
 
@@ -264,25 +324,31 @@ func (u *UserStorage) ChangeUserRole(ctx context.Context, userID string, role st
 		return err
 	}
 
-	u.logger.Info("successfully changed user role", "userID", userID, "newRole", role)
+	u.logger.InfoContext(ctx, "successfully changed user role", "userID", userID, "newRole", role)
 	return nil
 }
 
 // 5
 func (u *UserStorage) ChangeUserPassword(ctx context.Context, resetPassword *models.ResetPassword) error {
-	u.logger.Info("starting password change process", "email", resetPassword.Email)
+	ctx, span := tracing.Start(ctx, "UserStorage.ChangeUserPassword")
+	defer span.End()
+
+	u.logger.InfoContext(ctx, "starting password change process", "email", resetPassword.Email)
 
 	// Verify email and code first
 	err := u.verifyEmail(ctx, resetPassword.Email, resetPassword.Code)
 	if err != nil {
-		u.logger.Error("email verification failed", "error", err)
+		u.logger.ErrorContext(ctx, "email verification failed", "error", err)
+		if errors.Is(err, redis.ErrUnavailable) {
+			return fmt.Errorf("failed to check verification code: %w", err)
+		}
 		return fmt.Errorf("invalid verification code: %w", err)
 	}
 
 	// Start a session for transaction
 	session, err := u.db.Database().Client().StartSession()
 	if err != nil {
-		u.logger.Error("failed to start session", "error", err)
+		u.logger.ErrorContext(ctx, "failed to start session", "error", err)
 		return fmt.Errorf("failed to start session: %w", err)
 	}
 	defer session.EndSession(ctx)
@@ -290,17 +356,17 @@ func (u *UserStorage) ChangeUserPassword(ctx context.Context, resetPassword *mod
 	// Execute transaction
 	err = mongo.WithSession(ctx, session, func(sc mongo.SessionContext) error {
 		if err := session.StartTransaction(); err != nil {
-			u.logger.Error("failed to start transaction", "error", err)
+			u.logger.ErrorContext(ctx, "failed to start transaction", "error", err)
 			return fmt.Errorf("failed to start transaction: %w", err)
 		}
 
 		// Get current user data
 		var user models.User
-		filter := bson.M{"email": resetPassword.Email}
+		filter := bson.M{"email": resetPassword.Email, "deletedat": notDeleted}
 		err = u.db.FindOne(sc, filter).Decode(&user)
 		if err != nil {
 			session.AbortTransaction(sc)
-			u.logger.Error("failed to fetch user", "error", err)
+			u.logger.ErrorContext(ctx, "failed to fetch user", "error", err)
 			return fmt.Errorf("failed to fetch user: %w", err)
 		}
 
@@ -308,7 +374,7 @@ func (u *UserStorage) ChangeUserPassword(ctx context.Context, resetPassword *mod
 		hashedPassword, err := u.hashPassword(resetPassword.NewPassword)
 		if err != nil {
 			session.AbortTransaction(sc)
-			u.logger.Error("failed to hash password", "error", err)
+			u.logger.ErrorContext(ctx, "failed to hash password", "error", err)
 			return fmt.Errorf("failed to hash new password: %w", err)
 		}
 
@@ -317,38 +383,38 @@ func (u *UserStorage) ChangeUserPassword(ctx context.Context, resetPassword *mod
 		result, err := u.db.UpdateOne(sc, filter, update)
 		if err != nil {
 			session.AbortTransaction(sc)
-			u.logger.Error("failed to update password in database", "error", err)
+			u.logger.ErrorContext(ctx, "failed to update password in database", "error", err)
 			return fmt.Errorf("failed to update password: %w", err)
 		}
 
 		if result.MatchedCount == 0 {
 			session.AbortTransaction(sc)
-			u.logger.Warn("user not found during password update", "email", resetPassword.Email)
+			u.logger.WarnContext(ctx, "user not found during password update", "email", resetPassword.Email)
 			return fmt.Errorf("user not found")
 		}
 
 		// Get updated user data
-		var updatedUser models.User
-		err = u.db.FindOne(sc, filter).Decode(&updatedUser)
+		var updated userDocument
+		err = u.db.FindOne(sc, filter).Decode(&updated)
 		if err != nil {
 			session.AbortTransaction(sc)
-			u.logger.Error("failed to fetch updated user", "error", err)
+			u.logger.ErrorContext(ctx, "failed to fetch updated user", "error", err)
 			return fmt.Errorf("failed to fetch updated user: %w", err)
 		}
 
 		// Commit the transaction
 		if err = session.CommitTransaction(sc); err != nil {
-			u.logger.Error("failed to commit transaction", "error", err)
+			u.logger.ErrorContext(ctx, "failed to commit transaction", "error", err)
 			return fmt.Errorf("failed to commit transaction: %w", err)
 		}
 
-		// Update cache only after successful MongoDB transaction
-		if err := u.userCache.SetUser(ctx, &updatedUser); err != nil {
-			u.logger.Warn("failed to update user cache", "error", err)
+		// Invalidate only after successful MongoDB transaction
+		if err := u.userCache.DeleteUser(ctx, updated.user()); err != nil {
+			u.logger.WarnContext(ctx, "failed to invalidate user cache", "error", err)
 			// Don't return error here as the DB update was successful
 		}
 
-		u.logger.Info("password successfully changed", "email", resetPassword.Email)
+		u.logger.InfoContext(ctx, "password successfully changed", "email", resetPassword.Email)
 		return nil
 	})
 
@@ -361,10 +427,20 @@ func (u *UserStorage) ChangeUserPassword(ctx context.Context, resetPassword *mod
 
 // 6
 func (u *UserStorage) SendVerificationCode(ctx context.Context, email string) error {
-	u.logger.Info("starting verification code sending process", "email", email)
+	ctx, span := tracing.Start(ctx, "UserStorage.SendVerificationCode")
+	defer span.End()
+
+	u.logger.InfoContext(ctx, "starting verification code sending process", "email", email)
 
 	code := u.generateVerificationCode()
-	u.logger.Debug("verification code generated", "email", email)
+	u.logger.DebugContext(ctx, "verification code generated", "email", email)
+
+	// Store the code first: without Redis it could never be checked, so
+	// don't mail it.
+	if err := u.userCache.StoreEmailAndCode(ctx, email, code); err != nil {
+		u.logger.ErrorContext(ctx, "failed to store verification code in cache", "error", err, "email", email)
+		return err
+	}
 
 	m := gomail.NewMessage()
 	m.SetHeader("From", u.cfg.Email.SmtpUser)
@@ -375,27 +451,25 @@ func (u *UserStorage) SendVerificationCode(ctx context.Context, email string) er
 	d := gomail.NewDialer(u.cfg.Email.SmtpHost, u.cfg.Email.SmtpPort, u.cfg.Email.SmtpUser, u.cfg.Email.SmtpPass)
 
 	if err := d.DialAndSend(m); err != nil {
-		u.logger.Error("failed to send verification email", "error", err, "email", email)
-		return err
-	}
-
-	if err := u.userCache.StoreEmailAndCode(ctx, email, code); err != nil {
-		u.logger.Error("failed to store verification code in cache", "error", err, "email", email)
+		u.logger.ErrorContext(ctx, "failed to send verification email", "error", err, "email", email)
 		return err
 	}
 
-	u.logger.Info("verification code sent successfully", "email", email)
+	u.logger.InfoContext(ctx, "verification code sent successfully", "email", email)
 	return nil
 }
 
 // 7
 func (u *UserStorage) Login(ctx context.Context, login *models.LoginRequest) (string, error) {
-	u.logger.Info("starting login process", "username", login.Username)
+	ctx, span := tracing.Start(ctx, "UserStorage.Login")
+	defer span.End()
+
+	u.logger.InfoContext(ctx, "starting login process", "username", login.Username)
 
 	// Find user by username
 	user, err := u.GetUserByUsername(ctx, login.Username)
 	if err != nil {
-		u.logger.Error("failed to get user during login", "error", err, "username", login.Username)
+		u.logger.ErrorContext(ctx, "failed to get user during login", "error", err, "username", login.Username)
 		return "", fmt.Errorf("failed to get user: %w", err)
 	}
 
@@ -408,14 +482,102 @@ func (u *UserStorage) Login(ctx context.Context, login *models.LoginRequest) (st
 
 	token, err := jwttokens.GenerateAccessToken(u.cfg.JWT.SecretKey, &tokenClaims)
 	if err != nil {
-		u.logger.Error("failed to generate access token", "error", err, "username", login.Username)
+		u.logger.ErrorContext(ctx, "failed to generate access token", "error", err, "username", login.Username)
 		return "", err
 	}
 
-	u.logger.Info("login successful", "username", login.Username)
+	u.logger.InfoContext(ctx, "login successful", "username", login.Username)
 	return token, nil
 }
 
+// DeleteUser soft-deletes a user. Deleted users cannot be found, log in or
+// reset their password until restored.
+func (u *UserStorage) DeleteUser(ctx context.Context, userID string) error {
+	ctx, span := tracing.Start(ctx, "UserStorage.DeleteUser")
+	defer span.End()
+
+	u.logger.InfoContext(ctx, "deleting user", "userID", userID)
+
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to convert userID to ObjectID", "error", err)
+		return fmt.Errorf("invalid user ID format: %w", err)
+	}
+
+	var doc userDocument
+	err = u.db.FindOneAndUpdate(ctx,
+		bson.M{"_id": objectID, "deletedat": notDeleted},
+		bson.M{"$set": bson.M{"deletedat": time.Now().UTC()}},
+	).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			u.logger.WarnContext(ctx, "user not found", "userID", userID)
+			return fmt.Errorf("user not found")
+		}
+		u.logger.ErrorContext(ctx, "failed to delete user", "error", err)
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	if err := u.userCache.DeleteUser(ctx, doc.user()); err != nil {
+		u.logger.WarnContext(ctx, "failed to drop deleted user from cache", "error", err)
+	}
+
+	u.logger.InfoContext(ctx, "successfully deleted user", "userID", userID)
+	return nil
+}
+
+func (u *UserStorage) RestoreUser(ctx context.Context, userID string) error {
+	ctx, span := tracing.Start(ctx, "UserStorage.RestoreUser")
+	defer span.End()
+
+	u.logger.InfoContext(ctx, "restoring user", "userID", userID)
+
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to convert userID to ObjectID", "error", err)
+		return fmt.Errorf("invalid user ID format: %w", err)
+	}
+
+	result, err := u.db.UpdateOne(ctx,
+		bson.M{"_id": objectID, "deletedat": isDeleted},
+		bson.M{"$set": bson.M{"deletedat": time.Time{}}},
+	)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to restore user", "error", err)
+		return fmt.Errorf("failed to restore user: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		u.logger.WarnContext(ctx, "deleted user not found", "userID", userID)
+		return fmt.Errorf("deleted user not found")
+	}
+
+	u.logger.InfoContext(ctx, "successfully restored user", "userID", userID)
+	return nil
+}
+
+func (u *UserStorage) ListPurgeableUsers(ctx context.Context, cutoff time.Time) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "UserStorage.ListPurgeableUsers")
+	defer span.End()
+
+	return purgeableIDs(ctx, u.db, "_id", "deletedat", cutoff)
+}
+
+// PurgeDeletedUsers permanently removes users soft-deleted at or before
+// cutoff and returns how many were removed.
+func (u *UserStorage) PurgeDeletedUsers(ctx context.Context, cutoff time.Time) (int64, error) {
+	ctx, span := tracing.Start(ctx, "UserStorage.PurgeDeletedUsers")
+	defer span.End()
+
+	result, err := u.db.DeleteMany(ctx, bson.M{"deletedat": purgeable(cutoff)})
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to purge deleted users", "error", err)
+		return 0, fmt.Errorf("failed to purge deleted users: %w", err)
+	}
+
+	return result.DeletedCount, nil
+}
+
 func (u *UserStorage) hashPassword(password string) (string, error) {
 	u.logger.Debug("hashing password")
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -447,16 +609,16 @@ func (u *UserStorage) generateVerificationCode() int {
 }
 
 func (u *UserStorage) verifyEmail(ctx context.Context, email string, code int) error {
-	u.logger.Debug("verifying email code", "email", email)
+	u.logger.DebugContext(ctx, "verifying email code", "email", email)
 	c, err := u.userCache.GetCodeByEmail(ctx, email)
 	if err != nil {
-		u.logger.Error("failed to get code from cache", "error", err, "email", email)
+		u.logger.ErrorContext(ctx, "failed to get code from cache", "error", err, "email", email)
 		return err
 	}
 	if c != code {
-		u.logger.Warn("invalid verification code provided", "email", email)
+		u.logger.WarnContext(ctx, "invalid verification code provided", "email", email)
 		return errors.New("invalide code")
 	}
-	u.logger.Debug("email code verified successfully", "email", email)
+	u.logger.DebugContext(ctx, "email code verified successfully", "email", email)
 	return nil
 }
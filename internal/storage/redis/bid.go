@@ -1,11 +1,41 @@
 package redis
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/go-redis/redis/v8"
+	"github.com/zohirovs/internal/metrics"
+	"github.com/zohirovs/internal/models"
 )
 
+const (
+	bidListKeyPrefix    = "bids:tender:"
+	bidSummaryKeyPrefix = "bids:summary:"
+	bidGenKeyPrefix     = "bids:gen:"
+
+	// Bid lists are invalidated on every change; the TTL only bounds how
+	// long a missed invalidation can linger.
+	bidTTL = 10 * time.Minute
+
+	// The generation outlives the entries stored under it, so a load that
+	// started before an invalidation cannot see the counter reset.
+	bidGenTTL = 2 * bidTTL
+)
+
+// setIfGeneration stores ARGV[2] at KEYS[2] for ARGV[3] milliseconds, but
+// only while the generation at KEYS[1] is still ARGV[1].
+var setIfGeneration = redis.NewScript(`
+if (redis.call("GET", KEYS[1]) or "0") ~= ARGV[1] then
+	return 0
+end
+redis.call("SET", KEYS[2], ARGV[2], "PX", ARGV[3])
+return 1
+`)
+
 type BidCaching struct {
 	redisClient *redis.Client
 	logger      *slog.Logger
@@ -17,3 +47,115 @@ func NewBidCaching(client *redis.Client, logger *slog.Logger) *BidCaching {
 		logger:      logger,
 	}
 }
+
+// GetList returns the cached live bids of a tender, or redis.Nil on a miss.
+func (bc *BidCaching) GetList(ctx context.Context, tenderId string) ([]*models.Bid, error) {
+	var bids []*models.Bid
+	if err := bc.get(ctx, bidListKeyPrefix+tenderId, "list", &bids); err != nil {
+		return nil, err
+	}
+	return bids, nil
+}
+
+// SetList caches the live bids of a tender that were read at generation gen.
+// Nothing is stored if the tender's bids were invalidated since.
+func (bc *BidCaching) SetList(ctx context.Context, tenderId string, gen int64, bids []*models.Bid) error {
+	return bc.set(ctx, tenderId, bidListKeyPrefix+tenderId, gen, bids)
+}
+
+// GetSummary returns the cached bid summary of a tender, or redis.Nil on a miss.
+func (bc *BidCaching) GetSummary(ctx context.Context, tenderId string) (*models.BidSummary, error) {
+	var summary models.BidSummary
+	if err := bc.get(ctx, bidSummaryKeyPrefix+tenderId, "summary", &summary); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// SetSummary caches a bid summary computed at generation gen. Nothing is
+// stored if the tender's bids were invalidated since.
+func (bc *BidCaching) SetSummary(ctx context.Context, gen int64, summary *models.BidSummary) error {
+	return bc.set(ctx, summary.TenderId, bidSummaryKeyPrefix+summary.TenderId, gen, summary)
+}
+
+// Generation returns the number of times a tender's bids were invalidated.
+// Loaders read it before querying MongoDB and pass it to SetList and
+// SetSummary, so a slow load cannot cache bids an invalidation has replaced.
+func (bc *BidCaching) Generation(ctx context.Context, tenderId string) (int64, error) {
+	gen, err := bc.redisClient.Get(ctx, bidGenKeyPrefix+tenderId).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		metrics.BidCacheEvents.WithLabelValues(metrics.Error).Inc()
+		return 0, fmt.Errorf("failed to get bid cache generation: %w", err)
+	}
+	return gen, nil
+}
+
+// Invalidate drops everything cached about a tender's bids and moves it to
+// a new generation.
+func (bc *BidCaching) Invalidate(ctx context.Context, tenderId string) error {
+	genKey := bidGenKeyPrefix + tenderId
+	_, err := bc.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Incr(ctx, genKey)
+		pipe.Expire(ctx, genKey, bidGenTTL)
+		pipe.Del(ctx, bidListKeyPrefix+tenderId, bidSummaryKeyPrefix+tenderId)
+		return nil
+	})
+	if err != nil {
+		metrics.BidCacheEvents.WithLabelValues(metrics.Error).Inc()
+		return fmt.Errorf("failed to invalidate bid cache: %w", err)
+	}
+
+	metrics.BidCacheEvents.WithLabelValues(metrics.Invalidation).Inc()
+	return nil
+}
+
+// RecordCoalesced counts a miss that shared another request's database read.
+func (bc *BidCaching) RecordCoalesced() {
+	metrics.BidCacheEvents.WithLabelValues(metrics.Coalesced).Inc()
+}
+
+func (bc *BidCaching) get(ctx context.Context, key, kind string, v any) error {
+	data, err := bc.redisClient.Get(ctx, key).Bytes()
+	if err != nil {
+		recordLookup("bid_"+kind, err)
+		if err == redis.Nil {
+			return err
+		}
+		return fmt.Errorf("failed to get bids from cache: %w", err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		recordLookup("bid_"+kind, redis.Nil)
+		bc.logger.WarnContext(ctx, "discarding unreadable cached bids",
+			"error", err,
+			"key", key)
+		bc.redisClient.Del(ctx, key)
+		return redis.Nil
+	}
+
+	recordLookup("bid_"+kind, nil)
+	return nil
+}
+
+func (bc *BidCaching) set(ctx context.Context, tenderId, key string, gen int64, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal bids: %w", err)
+	}
+
+	stored, err := setIfGeneration.Run(ctx, bc.redisClient,
+		[]string{bidGenKeyPrefix + tenderId, key},
+		gen, data, bidTTL.Milliseconds()).Int()
+	if err != nil {
+		metrics.BidCacheEvents.WithLabelValues(metrics.Error).Inc()
+		return fmt.Errorf("failed to set bids in cache: %w", err)
+	}
+	if stored == 0 {
+		metrics.BidCacheEvents.WithLabelValues(metrics.StaleSet).Inc()
+	}
+
+	return nil
+}
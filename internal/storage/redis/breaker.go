@@ -0,0 +1,159 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// ErrUnavailable is returned by every Redis command while the breaker is
+// open. Cache reads treat it as a miss; features that cannot work without
+// Redis report it to the caller.
+var ErrUnavailable = errors.New("redis is unavailable")
+
+const (
+	// breakerThreshold consecutive connection failures open the breaker.
+	breakerThreshold = 3
+	// breakerRetryInterval is how often an open breaker pings Redis.
+	breakerRetryInterval = 5 * time.Second
+)
+
+type probeKey struct{}
+
+// Breaker is a circuit breaker installed as a hook on the Redis client.
+// After repeated connection failures it opens and fails commands at once
+// with ErrUnavailable instead of letting each one wait for a timeout. Watch
+// closes it again once Redis answers.
+type Breaker struct {
+	mu       sync.Mutex
+	open     bool
+	failures int
+	logger   *slog.Logger
+}
+
+func NewBreaker(logger *slog.Logger) *Breaker {
+	return &Breaker{logger: logger}
+}
+
+// Available reports whether commands are currently sent to Redis.
+func (b *Breaker) Available() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.open
+}
+
+// Trip opens the breaker, e.g. when Redis cannot be reached at startup.
+func (b *Breaker) Trip(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trip(err)
+}
+
+func (b *Breaker) trip(err error) {
+	if b.open {
+		return
+	}
+	b.open = true
+	b.logger.Warn("redis circuit breaker opened; caching is disabled",
+		"error", err)
+}
+
+// Watch pings Redis while the breaker is open and closes it once Redis
+// answers and reset has succeeded. It returns when ctx is cancelled.
+func (b *Breaker) Watch(ctx context.Context, client *redis.Client, reset func(context.Context) error) {
+	ticker := time.NewTicker(breakerRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if b.Available() {
+			continue
+		}
+
+		probe := context.WithValue(ctx, probeKey{}, true)
+		if err := client.Ping(probe).Err(); err != nil {
+			continue
+		}
+		if err := reset(probe); err != nil {
+			b.logger.WarnContext(ctx, "redis is reachable again but could not be reset",
+				"error", err)
+			continue
+		}
+
+		b.mu.Lock()
+		b.open = false
+		b.failures = 0
+		b.mu.Unlock()
+		b.logger.InfoContext(ctx, "redis circuit breaker closed; caching is enabled")
+	}
+}
+
+func (b *Breaker) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	return ctx, b.allow(ctx)
+}
+
+func (b *Breaker) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	b.record(ctx, cmd.Err())
+	return nil
+}
+
+func (b *Breaker) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	return ctx, b.allow(ctx)
+}
+
+func (b *Breaker) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	for _, cmd := range cmds {
+		if err := cmd.Err(); err != nil {
+			b.record(ctx, err)
+			return nil
+		}
+	}
+	b.record(ctx, nil)
+	return nil
+}
+
+func (b *Breaker) allow(ctx context.Context) error {
+	if probe, _ := ctx.Value(probeKey{}).(bool); probe {
+		return nil
+	}
+	if !b.Available() {
+		return ErrUnavailable
+	}
+	return nil
+}
+
+// record counts connection failures. Replies from Redis, including
+// redis.Nil and command errors, show the server is up; the caller's own
+// cancellation says nothing about Redis.
+func (b *Breaker) record(ctx context.Context, err error) {
+	if probe, _ := ctx.Value(probeKey{}).(bool); probe {
+		return
+	}
+
+	var reply redis.Error
+	switch {
+	case errors.Is(err, ErrUnavailable), ctx.Err() != nil:
+		return
+	case err == nil, errors.As(err, &reply):
+		b.mu.Lock()
+		b.failures = 0
+		b.mu.Unlock()
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.failures >= breakerThreshold {
+		b.trip(err)
+	}
+}
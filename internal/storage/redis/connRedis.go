@@ -12,9 +12,13 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net"
+	"strings"
+	"time"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/zohirovs/internal/config"
+	"github.com/zohirovs/internal/metrics"
 )
 
 type RedisService struct {
@@ -23,30 +27,144 @@ type RedisService struct {
 	Tender       *TenderCaching
 	Bid          *BidCaching
 	Contractor   *ContractorCaching
+	RateLimit    *RateLimiter
+
+	// Breaker stops commands while Redis is unreachable; see breaker.go.
+	Breaker *Breaker
+
+	client *redis.Client
+	logger *slog.Logger
+}
+
+// cachedKeyPatterns match every cached entity. They are dropped when Redis
+// comes back, since invalidations were lost while it was unreachable.
+// Verification codes, rate limits and lockouts are left alone.
+var cachedKeyPatterns = []string{
+	userKeyPrefix + "*",
+	userEmailKeyPrefix + "*",
+	userUsernameKeyPrefix + "*",
+	tenderKeyPrefix + "*",
+	bidListKeyPrefix + "*",
+	bidSummaryKeyPrefix + "*",
+	contractorKeyPrefix + "*",
 }
 
 func New(redisDb *redis.Client, logger *slog.Logger) *RedisService {
+	// Tracing goes first so commands the breaker rejects still get a span.
+	breaker := NewBreaker(logger)
+	redisDb.AddHook(tracingHook{})
+	redisDb.AddHook(breaker)
+
 	return &RedisService{
 		Notification: NewNotificationCaching(redisDb, logger),
 		User:         NewUserCaching(redisDb, logger),
 		Tender:       NewTenderCaching(redisDb, logger),
 		Bid:          NewBidCaching(redisDb, logger),
 		Contractor:   NewContractorCaching(redisDb, logger),
+		RateLimit:    NewRateLimiter(redisDb, logger),
+		Breaker:      breaker,
+		client:       redisDb,
+		logger:       logger,
+	}
+}
+
+// Ping checks Redis once and opens the breaker if it cannot be reached, so
+// the service starts without a cache instead of failing.
+func (r *RedisService) Ping(ctx context.Context) error {
+	if err := r.client.Ping(ctx).Err(); err != nil {
+		r.Breaker.Trip(err)
+		return fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	return nil
+}
+
+// Close closes the Redis connection pool.
+func (r *RedisService) Close() error {
+	return r.client.Close()
+}
+
+// Watch reconnects in the background whenever the breaker is open. It
+// returns when ctx is cancelled.
+func (r *RedisService) Watch(ctx context.Context) {
+	r.Breaker.Watch(ctx, r.client, r.dropCached)
+}
+
+func (r *RedisService) dropCached(ctx context.Context) error {
+	var dropped int
+	for _, pattern := range cachedKeyPatterns {
+		iter := r.client.Scan(ctx, 0, pattern, 500).Iterator()
+		var keys []string
+		for iter.Next(ctx) {
+			keys = append(keys, iter.Val())
+		}
+		if err := iter.Err(); err != nil {
+			return fmt.Errorf("failed to scan %s: %w", pattern, err)
+		}
+		for len(keys) > 0 {
+			n := min(len(keys), 500)
+			if err := r.client.Del(ctx, keys[:n]...).Err(); err != nil {
+				return fmt.Errorf("failed to drop cached keys: %w", err)
+			}
+			dropped += n
+			keys = keys[n:]
+		}
+	}
+
+	r.logger.InfoContext(ctx, "dropped possibly stale cache entries", "keys", dropped)
+	return nil
+}
+
+// recordLookup counts a cache lookup for /metrics; redis.Nil is a miss.
+func recordLookup(cache string, err error) {
+	result := metrics.Hit
+	switch {
+	case err == redis.Nil:
+		result = metrics.Miss
+	case err != nil:
+		result = metrics.Error
 	}
+	metrics.CacheRequests.WithLabelValues(cache, result).Inc()
 }
 
+// NewRedisClient does not connect; call RedisService.Ping to check Redis.
+// Timeouts are short so that an unreachable Redis trips the breaker quickly
+// instead of stalling requests.
+// NewRedisClient builds the client without connecting; see Ping. The URI
+// is either host:port or a redis:// or rediss:// URL.
 func NewRedisClient(cfg *config.Config) (*redis.Client, error) {
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     cfg.RedisURI,
-		Password: "", // Change this if password is required
-		DB:       0,
-	})
+	opts := &redis.Options{Addr: cfg.Redis.URI}
+	if strings.Contains(cfg.Redis.URI, "://") {
+		parsed, err := redis.ParseURL(cfg.Redis.URI)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Redis URI: %w", err)
+		}
+		opts = parsed
+	}
 
-	// Check Redis connection
-	_, err := rdb.Ping(context.Background()).Result()
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	if cfg.Redis.Username != "" {
+		opts.Username = cfg.Redis.Username
+	}
+	if cfg.Redis.Password != "" {
+		opts.Password = cfg.Redis.Password
 	}
+	if cfg.Redis.DB != 0 {
+		opts.DB = cfg.Redis.DB
+	}
+	if cfg.Redis.TLS.Enabled {
+		tlsConfig, err := cfg.Redis.TLS.Config()
+		if err != nil {
+			return nil, err
+		}
+		if host, _, err := net.SplitHostPort(opts.Addr); err == nil {
+			tlsConfig.ServerName = host
+		}
+		opts.TLSConfig = tlsConfig
+	}
+
+	opts.DialTimeout = 2 * time.Second
+	opts.ReadTimeout = time.Second
+	opts.WriteTimeout = time.Second
+	opts.MaxRetries = 1
 
-	return rdb, nil
+	return redis.NewClient(opts), nil
 }
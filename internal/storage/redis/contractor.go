@@ -1,11 +1,17 @@
 package redis
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"log/slog"
 
 	"github.com/go-redis/redis/v8"
+	"github.com/zohirovs/internal/models"
 )
 
+const contractorKeyPrefix = "contractor:"
+
 type ContractorCaching struct {
 	redisClient *redis.Client
 	logger      *slog.Logger
@@ -17,3 +23,53 @@ func NewContractorCaching(client *redis.Client, logger *slog.Logger) *Contractor
 		logger:      logger,
 	}
 }
+
+func (cc *ContractorCaching) Set(ctx context.Context, profile *models.ContractorProfile) error {
+	key := cc.generateKey(profile.UserId)
+
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return fmt.Errorf("failed to marshal contractor profile: %w", err)
+	}
+
+	err = cc.redisClient.Set(ctx, key, data, defaultTTL).Err()
+	if err != nil {
+		return fmt.Errorf("failed to set contractor profile in cache: %w", err)
+	}
+
+	return nil
+}
+
+func (cc *ContractorCaching) Get(ctx context.Context, userID string) (*models.ContractorProfile, error) {
+	key := cc.generateKey(userID)
+
+	data, err := cc.redisClient.Get(ctx, key).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, fmt.Errorf("contractor profile not found in cache")
+		}
+		return nil, fmt.Errorf("failed to get contractor profile from cache: %w", err)
+	}
+
+	var profile models.ContractorProfile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal contractor profile: %w", err)
+	}
+
+	return &profile, nil
+}
+
+func (cc *ContractorCaching) Delete(ctx context.Context, userID string) error {
+	key := cc.generateKey(userID)
+
+	err := cc.redisClient.Del(ctx, key).Err()
+	if err != nil {
+		return fmt.Errorf("failed to delete contractor profile from cache: %w", err)
+	}
+
+	return nil
+}
+
+func (cc *ContractorCaching) generateKey(userID string) string {
+	return fmt.Sprintf("%s%s", contractorKeyPrefix, userID)
+}
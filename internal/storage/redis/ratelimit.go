@@ -0,0 +1,141 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/zohirovs/internal/models"
+)
+
+const (
+	rateLimitKeyPrefix     = "ratelimit:"
+	loginFailuresKeyPrefix = "login:failures:"
+	loginLockedKeyPrefix   = "login:locked:"
+
+	// loginFailureMemory is how long failed logins are remembered after the
+	// last one.
+	loginFailureMemory = 24 * time.Hour
+)
+
+// slidingWindow keeps one sorted-set member per request, scored by its time
+// in milliseconds. It admits the request if fewer than the limit fall in the
+// window and returns whether it did, how many requests the window now holds
+// and how long until the oldest one leaves it.
+var slidingWindow = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	redis.call('PEXPIRE', KEYS[1], window)
+	count = count + 1
+	allowed = 1
+end
+
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+local reset = 0
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+return {allowed, count, reset}
+`)
+
+// RateLimiter stores request windows for the rate limit middleware and the
+// failed-login counters and lockouts of UserService.
+type RateLimiter struct {
+	redisClient *redis.Client
+	logger      *slog.Logger
+}
+
+func NewRateLimiter(client *redis.Client, logger *slog.Logger) *RateLimiter {
+	return &RateLimiter{
+		redisClient: client,
+		logger:      logger,
+	}
+}
+
+// Allow records a request under key if fewer than limit were made in the
+// past window; otherwise it records nothing and reports it as not allowed.
+func (r *RateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (*models.RateLimitStatus, error) {
+	member := make([]byte, 8)
+	if _, err := rand.Read(member); err != nil {
+		return nil, fmt.Errorf("failed to generate rate limit member: %w", err)
+	}
+
+	now := time.Now().UnixMilli()
+	res, err := slidingWindow.Run(ctx, r.redisClient,
+		[]string{rateLimitKeyPrefix + key},
+		now, window.Milliseconds(), limit, strconv.FormatInt(now, 10)+"-"+hex.EncodeToString(member),
+	).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check rate limit: %w", err)
+	}
+	if len(res) != 3 {
+		return nil, fmt.Errorf("unexpected rate limit reply %v", res)
+	}
+
+	return &models.RateLimitStatus{
+		Limit:     limit,
+		Remaining: max(limit-int(res[1]), 0),
+		Reset:     time.Duration(res[2]) * time.Millisecond,
+		Allowed:   res[0] == 1,
+	}, nil
+}
+
+// RecordLoginFailure counts a failed login for an account and returns the
+// number of failures since the last success.
+func (r *RateLimiter) RecordLoginFailure(ctx context.Context, account string) (int64, error) {
+	key := loginFailuresKeyPrefix + account
+
+	var incr *redis.IntCmd
+	_, err := r.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, loginFailureMemory)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to record login failure: %w", err)
+	}
+
+	return incr.Val(), nil
+}
+
+// LockLogin blocks logins to an account for d.
+func (r *RateLimiter) LockLogin(ctx context.Context, account string, d time.Duration) error {
+	if err := r.redisClient.Set(ctx, loginLockedKeyPrefix+account, 1, d).Err(); err != nil {
+		return fmt.Errorf("failed to lock login: %w", err)
+	}
+	return nil
+}
+
+// LoginLockedFor returns how long logins to an account stay blocked, or zero.
+func (r *RateLimiter) LoginLockedFor(ctx context.Context, account string) (time.Duration, error) {
+	ttl, err := r.redisClient.PTTL(ctx, loginLockedKeyPrefix+account).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check login lock: %w", err)
+	}
+	// PTTL reports a missing key as a negative duration.
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+// ClearLoginFailures forgets an account's failed logins after a success.
+func (r *RateLimiter) ClearLoginFailures(ctx context.Context, account string) error {
+	err := r.redisClient.Del(ctx, loginFailuresKeyPrefix+account, loginLockedKeyPrefix+account).Err()
+	if err != nil {
+		return fmt.Errorf("failed to clear login failures: %w", err)
+	}
+	return nil
+}
@@ -56,6 +56,7 @@ func (tc *TenderCaching) Get(ctx context.Context, id string) (*models.Tender, er
 	key := tc.generateKey(id)
 
 	data, err := tc.redisClient.Get(ctx, key).Bytes()
+	recordLookup("tender", err)
 	if err != nil {
 		if err == redis.Nil {
 			return nil, fmt.Errorf("tender not found in cache")
@@ -2,6 +2,8 @@ package redis
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
@@ -10,6 +12,64 @@ import (
 	"github.com/zohirovs/internal/models"
 )
 
+// Users are cached as JSON under user:id:<id>, with user:email:<email> and
+// user:username:<username> holding the ID for lookups by those fields.
+const (
+	userKeyPrefix         = "user:id:"
+	userEmailKeyPrefix    = "user:email:"
+	userUsernameKeyPrefix = "user:username:"
+
+	// userCodecVersion is bumped whenever cachedUser changes shape; entries
+	// written with another version are treated as misses.
+	userCodecVersion = 1
+)
+
+// cachedUser is the cached form of a user. It deliberately has no password
+// hash: credentials are always read from the database.
+type cachedUser struct {
+	Version   int       `json:"v"`
+	ID        string    `json:"id"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+func encodeUser(user *models.User) ([]byte, error) {
+	return json.Marshal(cachedUser{
+		Version:   userCodecVersion,
+		ID:        user.ID,
+		Username:  user.Username,
+		Email:     user.Email,
+		Role:      string(user.Role),
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+		DeletedAt: user.DeletedAt,
+	})
+}
+
+func decodeUser(data []byte) (*models.User, error) {
+	var cached cachedUser
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal user: %w", err)
+	}
+	if cached.Version != userCodecVersion {
+		return nil, fmt.Errorf("unsupported user cache version %d", cached.Version)
+	}
+
+	return &models.User{
+		ID:        cached.ID,
+		Username:  cached.Username,
+		Email:     cached.Email,
+		Role:      models.Role(cached.Role),
+		CreatedAt: cached.CreatedAt,
+		UpdatedAt: cached.UpdatedAt,
+		DeletedAt: cached.DeletedAt,
+	}, nil
+}
+
 type UserCaching struct {
 	redisClient *redis.Client
 	logger      *slog.Logger
@@ -22,18 +82,26 @@ func NewUserCaching(client *redis.Client, logger *slog.Logger) *UserCaching {
 	}
 }
 
+// SetUser caches a user and its email and username lookups. Users without
+// an ID are not cached.
 func (u *UserCaching) SetUser(ctx context.Context, user *models.User) error {
-	err := u.redisClient.HSet(ctx, "user:"+user.ID, map[string]interface{}{
-		"id":        user.ID,
-		"username":  user.Username,
-		"email":     user.Email,
-		"password":  user.Password,
-		"createdAt": user.CreatedAt,
-		"updatedAt": user.UpdatedAt,
-	}).Err()
+	if user.ID == "" {
+		return errors.New("cannot cache user without an ID")
+	}
 
+	data, err := encodeUser(user)
 	if err != nil {
-		u.logger.Error("failed to set user in redis",
+		return err
+	}
+
+	_, err = u.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, userKeyPrefix+user.ID, data, defaultTTL)
+		pipe.Set(ctx, userEmailKeyPrefix+user.Email, user.ID, defaultTTL)
+		pipe.Set(ctx, userUsernameKeyPrefix+user.Username, user.ID, defaultTTL)
+		return nil
+	})
+	if err != nil {
+		u.logger.ErrorContext(ctx, "failed to set user in redis",
 			"error", err,
 			"user_id", user.ID,
 		)
@@ -43,132 +111,99 @@ func (u *UserCaching) SetUser(ctx context.Context, user *models.User) error {
 	return nil
 }
 
+// GetUserByUserID returns redis.Nil when the user is not cached.
 func (u *UserCaching) GetUserByUserID(ctx context.Context, userID string) (*models.User, error) {
-	user := models.User{}
+	user, err := u.getUser(ctx, userID)
+	recordLookup("user", err)
+	return user, err
+}
+
+func (u *UserCaching) getUser(ctx context.Context, userID string) (*models.User, error) {
+	data, err := u.redisClient.Get(ctx, userKeyPrefix+userID).Bytes()
+	if err != nil {
+		// A miss or an open breaker is not worth logging.
+		if err != redis.Nil && !errors.Is(err, ErrUnavailable) {
+			u.logger.ErrorContext(ctx, "failed to get user from redis",
+				"error", err,
+				"user_id", userID,
+			)
+		}
+		return nil, err
+	}
 
-	values, err := u.redisClient.HGetAll(ctx, "user:"+userID).Result()
+	user, err := decodeUser(data)
 	if err != nil {
-		u.logger.Error("failed to get user from redis",
+		// Drop entries this version cannot read so they are re-cached.
+		u.logger.WarnContext(ctx, "discarding unreadable cached user",
 			"error", err,
 			"user_id", userID,
 		)
-		return &user, err
+		u.redisClient.Del(ctx, userKeyPrefix+userID)
+		return nil, redis.Nil
 	}
 
-	if len(values) == 0 {
-		return &user, redis.Nil
-	}
-	createdAt, _ := time.Parse(time.RFC3339, values["createdAt"])
-	updatedAt, _ := time.Parse(time.RFC3339, values["updatedAt"])
-	deletedAt, _ := time.Parse(time.RFC3339, values["deletedAt"])
-
-	user = models.User{
-		ID:        values["id"],
-		Username:  values["username"],
-		Email:     values["email"],
-		Password:  values["password"],
-		CreatedAt: createdAt,
-		UpdatedAt: updatedAt,
-		DeletedAt: deletedAt,
-	}
-	return &user, nil
+	return user, nil
 }
 
 func (u *UserCaching) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
-	user := models.User{}
+	user, err := u.lookup(ctx, userEmailKeyPrefix+email)
+	if err == nil && user.Email != email {
+		user, err = nil, redis.Nil
+	}
+	recordLookup("user", err)
+	return user, err
+}
 
-	// Get all users from Redis
-	keys, err := u.redisClient.Keys(ctx, "user:*").Result()
-	if err != nil {
-		u.logger.Error("failed to get user keys from redis",
-			"error", err,
-			"email", email,
-		)
-		return &user, err
+func (u *UserCaching) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	user, err := u.lookup(ctx, userUsernameKeyPrefix+username)
+	if err == nil && user.Username != username {
+		user, err = nil, redis.Nil
 	}
+	recordLookup("user", err)
+	return user, err
+}
 
-	// Iterate through users to find matching email
-	for _, key := range keys {
-		values, err := u.redisClient.HGetAll(ctx, key).Result()
-		if err != nil {
-			u.logger.Error("failed to get user from redis",
+// lookup follows a secondary key to the cached user. Callers check that the
+// user still has the looked-up value, since the secondary key may be stale.
+func (u *UserCaching) lookup(ctx context.Context, key string) (*models.User, error) {
+	userID, err := u.redisClient.Get(ctx, key).Result()
+	if err != nil {
+		if err != redis.Nil && !errors.Is(err, ErrUnavailable) {
+			u.logger.ErrorContext(ctx, "failed to get user key from redis",
 				"error", err,
 				"key", key,
 			)
-			continue
-		}
-
-		if values["email"] == email {
-			createdAt, _ := time.Parse(time.RFC3339, values["createdAt"])
-			updatedAt, _ := time.Parse(time.RFC3339, values["updatedAt"])
-			deletedAt, _ := time.Parse(time.RFC3339, values["deletedAt"])
-
-			user = models.User{
-				ID:        values["id"],
-				Username:  values["username"],
-				Email:     values["email"],
-				Password:  values["password"],
-				CreatedAt: createdAt,
-				UpdatedAt: updatedAt,
-				DeletedAt: deletedAt,
-			}
-			return &user, nil
 		}
+		return nil, err
 	}
 
-	return &user, redis.Nil
+	return u.getUser(ctx, userID)
 }
 
-func (u *UserCaching) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
-	user := models.User{}
-
-	// Get all users from Redis
-	keys, err := u.redisClient.Keys(ctx, "user:*").Result()
+// DeleteUser drops a cached user and its lookups, e.g. after its role or
+// password changed or it was deleted.
+func (u *UserCaching) DeleteUser(ctx context.Context, user *models.User) error {
+	err := u.redisClient.Del(ctx,
+		userKeyPrefix+user.ID,
+		userEmailKeyPrefix+user.Email,
+		userUsernameKeyPrefix+user.Username,
+	).Err()
 	if err != nil {
-		u.logger.Error("failed to get user keys from redis",
+		u.logger.ErrorContext(ctx, "failed to delete user from redis",
 			"error", err,
-			"username", username,
+			"user_id", user.ID,
 		)
-		return &user, err
-	}
-
-	// Iterate through users to find matching username
-	for _, key := range keys {
-		values, err := u.redisClient.HGetAll(ctx, key).Result()
-		if err != nil {
-			u.logger.Error("failed to get user from redis",
-				"error", err,
-				"key", key,
-			)
-			continue
-		}
-
-		if values["username"] == username {
-			createdAt, _ := time.Parse(time.RFC3339, values["createdAt"])
-			updatedAt, _ := time.Parse(time.RFC3339, values["updatedAt"])
-			deletedAt, _ := time.Parse(time.RFC3339, values["deletedAt"])
-
-			user = models.User{
-				ID:        values["id"],
-				Username:  values["username"],
-				Email:     values["email"],
-				Password:  values["password"],
-				CreatedAt: createdAt,
-				UpdatedAt: updatedAt,
-				DeletedAt: deletedAt,
-			}
-			return &user, nil
-		}
+		return err
 	}
 
-	return &user, redis.Nil
+	return nil
 }
 
 func (u *UserCaching) StoreEmailAndCode(ctx context.Context, email string, code int) error {
 	codeKey := "verification_code:" + email
 	err := u.redisClient.Set(ctx, codeKey, code, time.Minute*1).Err()
 	if err != nil {
-		u.logger.Error("Error while storing verification code", slog.String("error", err.Error()))
+		u.logger.ErrorContext(ctx, "Error while storing verification code", slog.String("error", err.Error()))
 		return err
 	}
 	return nil
@@ -180,14 +215,14 @@ func (u *UserCaching) GetCodeByEmail(ctx context.Context, email string) (int, er
 	if err == redis.Nil {
 		return 0, nil
 	} else if err != nil {
-		u.logger.Error("Error while getting verification code", slog.String("error", err.Error()))
+		u.logger.ErrorContext(ctx, "Error while getting verification code", slog.String("error", err.Error()))
 		return 0, err
 	}
 
 	var code int
 	_, err = fmt.Sscanf(codeStr, "%d", &code)
 	if err != nil {
-		u.logger.Error("Error while parsing verification code", slog.String("error", err.Error()))
+		u.logger.ErrorContext(ctx, "Error while parsing verification code", slog.String("error", err.Error()))
 		return 0, err
 	}
 
@@ -9,6 +9,7 @@
 package storage
 
 import (
+	"context"
 	"log/slog"
 
 	"github.com/zohirovs/internal/config"
@@ -16,6 +17,7 @@ import (
 	mongodb "github.com/zohirovs/internal/storage/mongoDB"
 	"github.com/zohirovs/internal/storage/redis"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 type StorageI interface {
@@ -23,6 +25,24 @@ type StorageI interface {
 	TenderRepo() repos.TenderRepo
 	BidRepo() repos.BidRepo
 	NotificationRepo() repos.NotificationRepo
+	AttachmentRepo() repos.AttachmentRepo
+	ContractorRepo() repos.ContractorRepo
+	ReviewRepo() repos.ReviewRepo
+	CategoryRepo() repos.CategoryRepo
+	SavedSearchRepo() repos.SavedSearchRepo
+	TenderSearchIndex() repos.TenderSearchIndex
+	QuestionRepo() repos.QuestionRepo
+	InvitationRepo() repos.InvitationRepo
+	TemplateRepo() repos.TemplateRepo
+	OrganizationRepo() repos.OrganizationRepo
+	AuditRepo() repos.AuditRepo
+	QuotaRepo() repos.QuotaRepo
+	LockRepo() repos.LockRepo
+
+	// Ping checks that MongoDB answers.
+	Ping(ctx context.Context) error
+	// Close disconnects from MongoDB.
+	Close(ctx context.Context) error
 }
 
 type Storage struct {
@@ -30,14 +50,43 @@ type Storage struct {
 	tenderRepo       repos.TenderRepo
 	bidRepo          repos.BidRepo
 	notificationRepo repos.NotificationRepo
+	attachmentRepo   repos.AttachmentRepo
+	contractorRepo   repos.ContractorRepo
+	reviewRepo       repos.ReviewRepo
+	categoryRepo     repos.CategoryRepo
+	savedSearchRepo  repos.SavedSearchRepo
+	tenderSearch     repos.TenderSearchIndex
+	questionRepo     repos.QuestionRepo
+	invitationRepo   repos.InvitationRepo
+	templateRepo     repos.TemplateRepo
+	organizationRepo repos.OrganizationRepo
+	auditRepo        repos.AuditRepo
+	quotaRepo        repos.QuotaRepo
+	lockRepo         repos.LockRepo
+
+	db *mongo.Database
 }
 
 func New(db *mongo.Database, cfg *config.Config, logger *slog.Logger, cache *redis.RedisService) StorageI {
 	return &Storage{
 		userRepo:         mongodb.NewUserStorage(db, cfg, logger, cache.User),
 		tenderRepo:       mongodb.NewTenderStorage(db, logger, cache.Tender),
-		bidRepo:          mongodb.NewBidStorage(db, logger),
+		bidRepo:          mongodb.NewBidStorage(db, logger, cache.Bid),
 		notificationRepo: mongodb.NewNotificationStorage(db, logger, cache.Notification),
+		attachmentRepo:   mongodb.NewAttachmentStorage(db, logger),
+		contractorRepo:   mongodb.NewContractorStorage(db, logger),
+		reviewRepo:       mongodb.NewReviewStorage(db, logger),
+		categoryRepo:     mongodb.NewCategoryStorage(db, logger),
+		savedSearchRepo:  mongodb.NewSavedSearchStorage(db, logger),
+		tenderSearch:     mongodb.NewTenderSearchStorage(db, cfg.Search.Language, logger),
+		questionRepo:     mongodb.NewQuestionStorage(db, logger),
+		invitationRepo:   mongodb.NewInvitationStorage(db, logger),
+		templateRepo:     mongodb.NewTemplateStorage(db, logger),
+		organizationRepo: mongodb.NewOrganizationStorage(db, logger),
+		auditRepo:        mongodb.NewAuditStorage(db, logger),
+		quotaRepo:        mongodb.NewQuotaStorage(db, logger),
+		lockRepo:         mongodb.NewLockStorage(db, logger),
+		db:               db,
 	}
 }
 
@@ -56,3 +105,63 @@ func (s *Storage) BidRepo() repos.BidRepo {
 func (s *Storage) NotificationRepo() repos.NotificationRepo {
 	return s.notificationRepo
 }
+
+func (s *Storage) AttachmentRepo() repos.AttachmentRepo {
+	return s.attachmentRepo
+}
+
+func (s *Storage) ContractorRepo() repos.ContractorRepo {
+	return s.contractorRepo
+}
+
+func (s *Storage) ReviewRepo() repos.ReviewRepo {
+	return s.reviewRepo
+}
+
+func (s *Storage) CategoryRepo() repos.CategoryRepo {
+	return s.categoryRepo
+}
+
+func (s *Storage) SavedSearchRepo() repos.SavedSearchRepo {
+	return s.savedSearchRepo
+}
+
+func (s *Storage) TenderSearchIndex() repos.TenderSearchIndex {
+	return s.tenderSearch
+}
+
+func (s *Storage) QuestionRepo() repos.QuestionRepo {
+	return s.questionRepo
+}
+
+func (s *Storage) InvitationRepo() repos.InvitationRepo {
+	return s.invitationRepo
+}
+
+func (s *Storage) TemplateRepo() repos.TemplateRepo {
+	return s.templateRepo
+}
+
+func (s *Storage) OrganizationRepo() repos.OrganizationRepo {
+	return s.organizationRepo
+}
+
+func (s *Storage) AuditRepo() repos.AuditRepo {
+	return s.auditRepo
+}
+
+func (s *Storage) QuotaRepo() repos.QuotaRepo {
+	return s.quotaRepo
+}
+
+func (s *Storage) LockRepo() repos.LockRepo {
+	return s.lockRepo
+}
+
+func (s *Storage) Ping(ctx context.Context) error {
+	return s.db.Client().Ping(ctx, readpref.Primary())
+}
+
+func (s *Storage) Close(ctx context.Context) error {
+	return s.db.Client().Disconnect(ctx)
+}
@@ -3,6 +3,7 @@ package websocket
 import (
 	"encoding/json"
 	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
@@ -20,33 +21,88 @@ type Manager struct {
 	unregister chan *Client
 	broadcast  chan []byte
 	mu         sync.RWMutex
+
+	done     chan struct{}
+	shutdown sync.Once
 }
 
+// closeTimeout bounds how long Shutdown waits to send each close frame.
+const closeTimeout = time.Second
+
 func NewManager() *Manager {
 	return &Manager{
 		clients:    make(map[*Client]bool),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
 		broadcast:  make(chan []byte),
+		done:       make(chan struct{}),
 	}
 }
 
-// RegisterClient registers a new client
+// RegisterClient registers a new client; after Shutdown the connection is
+// closed instead.
 func (m *Manager) RegisterClient(client *Client) {
-	m.register <- client
+	select {
+	case m.register <- client:
+	case <-m.done:
+		client.Conn.Close()
+	}
 }
 
 // UnregisterClient unregisters a client
 func (m *Manager) UnregisterClient(client *Client) {
-	m.unregister <- client
+	select {
+	case m.unregister <- client:
+	case <-m.done:
+	}
+}
+
+// Shutdown sends every client a going-away close frame, closes their
+// connections and stops Run.
+func (m *Manager) Shutdown() {
+	m.shutdown.Do(func() {
+		close(m.done)
+
+		msg := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		for client := range m.clients {
+			client.mu.Lock()
+			client.Conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(closeTimeout))
+			client.mu.Unlock()
+			client.Conn.Close()
+			delete(m.clients, client)
+		}
+	})
+}
+
+// Stats reports the open connections and the tenders they watch.
+func (m *Manager) Stats() (clients, rooms int) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tenders := make(map[string]struct{})
+	for client := range m.clients {
+		tenders[client.TenderID] = struct{}{}
+	}
+	return len(m.clients), len(tenders)
 }
 
 func (m *Manager) Run() {
 	for {
 		select {
+		case <-m.done:
+			return
+
 		case client := <-m.register:
 			m.mu.Lock()
-			m.clients[client] = true
+			select {
+			case <-m.done:
+				// Shutdown has already closed the others.
+				client.Conn.Close()
+			default:
+				m.clients[client] = true
+			}
 			m.mu.Unlock()
 
 		case client := <-m.unregister:
@@ -70,33 +126,50 @@ func (m *Manager) BroadcastToTender(tenderID string, message interface{}) error
 	}
 
 	m.mu.RLock()
-	defer m.mu.RUnlock()
-
+	var failed []*Client
 	for client := range m.clients {
 		if client.TenderID == tenderID {
 			client.mu.Lock()
-			err := client.Conn.WriteMessage(websocket.TextMessage, data)
-			if err != nil {
-				client.Conn.Close()
-				delete(m.clients, client)
+			if err := client.Conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				failed = append(failed, client)
 			}
 			client.mu.Unlock()
 		}
 	}
+	m.mu.RUnlock()
+
+	m.drop(failed)
 	return nil
 }
 
 func (m *Manager) broadcastMessage(message []byte) {
 	m.mu.RLock()
-	defer m.mu.RUnlock()
-
+	var failed []*Client
 	for client := range m.clients {
 		client.mu.Lock()
-		err := client.Conn.WriteMessage(websocket.TextMessage, message)
-		if err != nil {
+		if err := client.Conn.WriteMessage(websocket.TextMessage, message); err != nil {
+			failed = append(failed, client)
+		}
+		client.mu.Unlock()
+	}
+	m.mu.RUnlock()
+
+	m.drop(failed)
+}
+
+// drop closes and forgets clients whose connection failed. The map may only
+// be modified under the write lock.
+func (m *Manager) drop(clients []*Client) {
+	if len(clients) == 0 {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, client := range clients {
+		if _, ok := m.clients[client]; ok {
 			client.Conn.Close()
 			delete(m.clients, client)
 		}
-		client.mu.Unlock()
 	}
 }